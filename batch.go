@@ -0,0 +1,230 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// MapBatch is like calling Map for each value in ts, storing the coordinates of ts[i] in xs[i]
+// and ys[i]. The slices are provided by the caller, so can be reused between calls, and nothing
+// is allocated, which makes it much faster than calling Map in a loop for large inputs.
+//
+// ErrInvalidLength is returned if the slices are different lengths. If a value is outside of the
+// space, and the BoundsPolicy does not allow it, an *IndexError for the first one is returned,
+// wrapping a *RangeError with the value, and the values after it are left unchanged.
+func (s *Hilbert) MapBatch(ts []int, xs, ys []int) error {
+	if len(xs) != len(ts) || len(ys) != len(ts) {
+		return ErrInvalidLength
+	}
+
+	// The same walk as Map, with everything that does not depend on t done once, outside of
+	// the loop.
+	size := s.N * s.N
+	order := uint(s.GetOrder())
+	tables := s.forward != nil
+	bitwise := bitwiseCore && order >= bitwiseMinOrder
+	for i, t := range ts {
+		if t < 0 || t >= size {
+			fitted, ok := s.bounds.fit(t, size)
+			if !ok {
+				return &IndexError{Index: i, Err: &RangeError{Name: "t", Value: t, Min: 0, Max: size - 1}}
+			}
+			t = fitted
+		}
+
+		if tables {
+			p := int(s.forward[t])
+			xs[i], ys[i] = p%s.N, p/s.N
+			continue
+		}
+		if s.reversed {
+			t = size - 1 - t
+		}
+		if bitwise {
+			bx, by := decodeBits(uint64(t), order)
+			xs[i], ys[i] = applyLayout(s.startState, s.mirrored, s.N, int(bx), int(by))
+			continue
+		}
+
+		var x, y int
+		state := s.startState
+		shift := 2 * order
+		if order&1 == 1 {
+			shift -= 2
+			e := forwardStates[state<<2|uint8(t>>shift&3)]
+			x, y = int(e>>3), int(e>>2&1)
+			state = e & 3
+		}
+		for shift > 0 {
+			shift -= 4
+			e := forwardStates2[(uint(state)<<4|uint(t>>shift&15))&63]
+			x = x<<2 | int(e>>4)
+			y = y<<2 | int(e>>2&3)
+			state = e & 3
+		}
+		if s.mirrored {
+			x = s.N - 1 - x
+		}
+		xs[i], ys[i] = x, y
+	}
+	return nil
+}
+
+// MapInverseBatch is like calling MapInverse for each coordinate (xs[i],ys[i]), storing the
+// result in ts[i]. Errors are returned in the same way as MapBatch.
+func (s *Hilbert) MapInverseBatch(xs, ys []int, ts []int) error {
+	if len(ys) != len(xs) || len(ts) != len(xs) {
+		return ErrInvalidLength
+	}
+
+	// The same walk as MapInverse, with everything that does not depend on the coordinates done
+	// once, outside of the loop.
+	size := s.N * s.N
+	order := uint(s.GetOrder())
+	tables := s.inverse != nil
+	bitwise := bitwiseCore && order >= bitwiseMinOrder
+	for i, x := range xs {
+		y := ys[i]
+		if x < 0 || x >= s.N || y < 0 || y >= s.N {
+			fx, okX := s.fitCoord(x)
+			fy, okY := s.fitCoord(y)
+			switch {
+			case !okX:
+				return &IndexError{Index: i, Err: &RangeError{Name: "x", Value: x, Min: 0, Max: s.N - 1}}
+			case !okY:
+				return &IndexError{Index: i, Err: &RangeError{Name: "y", Value: y, Min: 0, Max: s.N - 1}}
+			}
+			x, y = fx, fy
+		}
+
+		if tables {
+			ts[i] = int(s.inverse[y*s.N+x])
+			continue
+		}
+		if s.mirrored {
+			x = s.N - 1 - x
+		}
+		if bitwise {
+			x, y = applyLayout(s.startState, false, s.N, x, y)
+			t := int(encodeBits(uint32(x), uint32(y), order))
+			if s.reversed {
+				t = size - 1 - t
+			}
+			ts[i] = t
+			continue
+		}
+
+		var t int
+		state := s.startState
+		shift := order
+		if order&1 == 1 {
+			shift--
+			e := inverseStates[int(state)<<2|(x>>shift&1)<<1|y>>shift&1]
+			t = int(e >> 2)
+			state = e & 3
+		}
+		for shift > 0 {
+			shift -= 2
+			e := inverseStates2[(uint(state)<<4|uint(x>>shift&3)<<2|uint(y>>shift&3))&63]
+			t = t<<4 | int(e>>2)
+			state = e & 3
+		}
+
+		if s.reversed {
+			t = size - 1 - t
+		}
+		ts[i] = t
+	}
+	return nil
+}
+
+// parallelChunk is the number of values each goroutine converts at a time in MapBatchParallel
+// and MapInverseBatchParallel, which is large enough for the scheduling to be negligible.
+const parallelChunk = 1 << 14
+
+// MapBatchParallel is like MapBatch, but splits the work across workers goroutines, or
+// GOMAXPROCS if workers is less than one. If ctx is cancelled its error is returned, and some of
+// the values may not have been converted. If several values are outside of the space, the
+// returned *IndexError is for the first of them.
+func (s *Hilbert) MapBatchParallel(ctx context.Context, ts []int, xs, ys []int, workers int) error {
+	if len(xs) != len(ts) || len(ys) != len(ts) {
+		return ErrInvalidLength
+	}
+	return parallelChunks(ctx, len(ts), workers, func(lo, hi int) error {
+		return s.MapBatch(ts[lo:hi], xs[lo:hi], ys[lo:hi])
+	})
+}
+
+// MapInverseBatchParallel is like MapInverseBatch, but splits the work across goroutines in the
+// same way as MapBatchParallel.
+func (s *Hilbert) MapInverseBatchParallel(ctx context.Context, xs, ys []int, ts []int, workers int) error {
+	if len(ys) != len(xs) || len(ts) != len(xs) {
+		return ErrInvalidLength
+	}
+	return parallelChunks(ctx, len(xs), workers, func(lo, hi int) error {
+		return s.MapInverseBatch(xs[lo:hi], ys[lo:hi], ts[lo:hi])
+	})
+}
+
+// parallelChunks calls fn for each chunk [lo, hi) of [0, n) using the given number of
+// goroutines, checking ctx between chunks. The index of an *IndexError returned by fn is
+// relative to lo, and is made relative to 0.
+func parallelChunks(ctx context.Context, n, workers int, fn func(lo, hi int) error) error {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	chunks := (n + parallelChunk - 1) / parallelChunk
+	workers = min(workers, chunks)
+
+	// Each chunk records its own error, so the first one in index order can be returned no
+	// matter which goroutine finished first.
+	errs := make([]error, chunks)
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				c := int(next.Add(1) - 1)
+				if c >= chunks {
+					return
+				}
+				lo := c * parallelChunk
+				if err := fn(lo, min(lo+parallelChunk, n)); err != nil {
+					if ie, ok := err.(*IndexError); ok {
+						ie.Index += lo
+					}
+					errs[c] = err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
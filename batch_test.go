@@ -0,0 +1,206 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMapBatch(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		s, err := NewHilbert(16, vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		ts := make([]int, s.N*s.N)
+		for i := range ts {
+			ts[i] = i
+		}
+		xs, ys := make([]int, len(ts)), make([]int, len(ts))
+		if err := s.MapBatch(ts, xs, ys); err != nil {
+			t.Fatalf("MapBatch() returned error: %s", err)
+		}
+
+		got := make([]int, len(ts))
+		if err := s.MapInverseBatch(xs, ys, got); err != nil {
+			t.Fatalf("MapInverseBatch() returned error: %s", err)
+		}
+
+		for i, d := range ts {
+			x, y, _ := s.Map(d)
+			if xs[i] != x || ys[i] != y {
+				t.Errorf("MapBatch() for t=%d = (%d, %d) want (%d, %d)", d, xs[i], ys[i], x, y)
+			}
+			if got[i] != d {
+				t.Errorf("MapInverseBatch() for (%d, %d) = %d want %d", x, y, got[i], d)
+			}
+		}
+	}
+}
+
+func TestMapBatchErrors(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	buf := make([]int, 3)
+	if err := s.MapBatch(buf, buf, buf[:2]); err != ErrInvalidLength {
+		t.Errorf("MapBatch() with short ys = %v want %v", err, ErrInvalidLength)
+	}
+	if err := s.MapInverseBatch(buf[:2], buf, buf); err != ErrInvalidLength {
+		t.Errorf("MapInverseBatch() with short xs = %v want %v", err, ErrInvalidLength)
+	}
+
+	var ie *IndexError
+	err = s.MapBatch([]int{0, 15, 16}, make([]int, 3), make([]int, 3))
+	if !errors.As(err, &ie) || ie.Index != 2 || !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("MapBatch([0 15 16]) = %v want index 2 %v", err, ErrOutOfRange)
+	}
+	var re *RangeError
+	if !errors.As(err, &re) || *re != (RangeError{Name: "t", Value: 16, Min: 0, Max: 15}) {
+		t.Errorf("MapBatch([0 15 16]) = %v want t = 16 is not within [0, 15]", err)
+	}
+	err = s.MapInverseBatch([]int{0, -1}, []int{0, 0}, make([]int, 2))
+	if !errors.As(err, &ie) || ie.Index != 1 || !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("MapInverseBatch([0 -1], [0 0]) = %v want index 1 %v", err, ErrOutOfRange)
+	}
+	if !errors.As(err, &re) || *re != (RangeError{Name: "x", Value: -1, Min: 0, Max: 3}) {
+		t.Errorf("MapInverseBatch([0 -1], [0 0]) = %v want x = -1 is not within [0, 3]", err)
+	}
+	err = s.MapInverseBatch([]int{0, 1}, []int{0, 7}, make([]int, 2))
+	if got, want := err.Error(), "index 1: y = 7 is not within [0, 3]"; got != want {
+		t.Errorf("MapInverseBatch([0 1], [0 7]) = %q want %q", got, want)
+	}
+
+	w, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsWrap))
+	xs, ys := make([]int, 1), make([]int, 1)
+	if err := w.MapBatch([]int{-1}, xs, ys); err != nil || xs[0] != 3 || ys[0] != 0 {
+		t.Errorf("MapBatch([-1]) with BoundsWrap = (%d, %d, %v) want (3, 0, nil)", xs[0], ys[0], err)
+	}
+}
+
+func TestMapBatchParallel(t *testing.T) {
+	s, err := NewHilbert(512, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	ts := make([]int, s.N*s.N)
+	for i := range ts {
+		ts[i] = len(ts) - 1 - i
+	}
+	wantX, wantY := make([]int, len(ts)), make([]int, len(ts))
+	if err := s.MapBatch(ts, wantX, wantY); err != nil {
+		t.Fatalf("MapBatch() returned error: %s", err)
+	}
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		xs, ys := make([]int, len(ts)), make([]int, len(ts))
+		if err := s.MapBatchParallel(context.Background(), ts, xs, ys, workers); err != nil {
+			t.Fatalf("MapBatchParallel(%d workers) returned error: %s", workers, err)
+		}
+		got := make([]int, len(ts))
+		if err := s.MapInverseBatchParallel(context.Background(), xs, ys, got, workers); err != nil {
+			t.Fatalf("MapInverseBatchParallel(%d workers) returned error: %s", workers, err)
+		}
+		for i := range ts {
+			if xs[i] != wantX[i] || ys[i] != wantY[i] || got[i] != ts[i] {
+				t.Fatalf("%d workers, t=%d: (%d, %d) -> %d want (%d, %d) -> %d", workers, ts[i], xs[i], ys[i], got[i], wantX[i], wantY[i], ts[i])
+			}
+		}
+	}
+}
+
+func TestMapBatchParallelErrors(t *testing.T) {
+	s, err := NewHilbert(512, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	ts := make([]int, 3*parallelChunk)
+	xs, ys := make([]int, len(ts)), make([]int, len(ts))
+	if err := s.MapBatchParallel(context.Background(), ts, xs, ys[1:], 2); err != ErrInvalidLength {
+		t.Errorf("MapBatchParallel() with short ys = %v want %v", err, ErrInvalidLength)
+	}
+
+	// The first bad value is reported, even if a later chunk fails first.
+	ts[parallelChunk+5], ts[2*parallelChunk] = -1, -1
+	var ie *IndexError
+	err = s.MapBatchParallel(context.Background(), ts, xs, ys, 3)
+	if !errors.As(err, &ie) || ie.Index != parallelChunk+5 || !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("MapBatchParallel() = %v want index %d %v", err, parallelChunk+5, ErrOutOfRange)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.MapInverseBatchParallel(ctx, xs, ys, ts, 2); err != context.Canceled {
+		t.Errorf("MapInverseBatchParallel() with cancelled context = %v want %v", err, context.Canceled)
+	}
+}
+
+func BenchmarkMapBatch(b *testing.B) {
+	s, err := NewHilbert(benchmarkN, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+	ts := make([]int, benchmarkN*benchmarkN)
+	for i := range ts {
+		ts[i] = i
+	}
+	xs, ys := make([]int, len(ts)), make([]int, len(ts))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.MapBatch(ts, xs, ys)
+	}
+}
+
+func BenchmarkMapInverseBatch(b *testing.B) {
+	s, err := NewHilbert(benchmarkN, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+	xs, ys := make([]int, benchmarkN*benchmarkN), make([]int, benchmarkN*benchmarkN)
+	for i := range xs {
+		xs[i], ys[i] = i%benchmarkN, i/benchmarkN
+	}
+	ts := make([]int, len(xs))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.MapInverseBatch(xs, ys, ts)
+	}
+}
+
+func BenchmarkMapBatchParallel(b *testing.B) {
+	s, err := NewHilbert(1024, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+	ts := make([]int, s.N*s.N)
+	for i := range ts {
+		ts[i] = i
+	}
+	xs, ys := make([]int, len(ts)), make([]int, len(ts))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.MapBatchParallel(context.Background(), ts, xs, ys, 0)
+	}
+}
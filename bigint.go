@@ -0,0 +1,129 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/big"
+
+// HilbertBig represents a 2D Hilbert space of arbitrary order, using big.Int values for the
+// index and coordinates. It is slower than Hilbert, but has no limit on the size of the space.
+type HilbertBig struct {
+	order              int
+	verticalCompatible bool
+}
+
+// NewHilbertBig returns a HilbertBig space of the given order, that is a 2^order by 2^order
+// space. verticalCompatible has the same meaning as in NewHilbert.
+func NewHilbertBig(order int, verticalCompatible bool) (*HilbertBig, error) {
+	if order < 0 {
+		return nil, ErrNegativeOrder
+	}
+
+	return &HilbertBig{
+		order:              order,
+		verticalCompatible: verticalCompatible,
+	}, nil
+}
+
+// GetOrder returns the order of the curve. The width and height of the space are 2^order.
+func (s *HilbertBig) GetOrder() int {
+	return s.order
+}
+
+// inRange returns true if v is within [0, 2^bits-1].
+func inRange(v *big.Int, bits int) bool {
+	return v.Sign() >= 0 && v.BitLen() <= bits
+}
+
+// MapBig transforms a one dimension value, t, in the range [0, 4^order-1] to coordinates on the
+// Hilbert curve in the two-dimension space, where x and y are within [0, 2^order-1].
+func (s *HilbertBig) MapBig(t *big.Int) (x, y *big.Int, err error) {
+	if t == nil || !inRange(t, 2*s.order) {
+		return nil, nil, ErrOutOfRange
+	}
+
+	x, y = new(big.Int), new(big.Int)
+	mask := new(big.Int)
+	for i := 0; i < s.order; i++ {
+		rx := t.Bit(2*i+1) == 1
+		ry := t.Bit(2*i) == 1
+		if rx {
+			ry = !ry
+		}
+
+		// Rotate the lower i bits, where mask is 2^i-1.
+		if !ry {
+			if rx {
+				x.Xor(x, mask)
+				y.Xor(y, mask)
+			}
+			x, y = y, x
+		}
+
+		x.SetBit(x, i, uint(b2i(rx)))
+		y.SetBit(y, i, uint(b2i(ry)))
+
+		mask.SetBit(mask, i, 1)
+	}
+
+	if s.verticalCompatible {
+		// The rotation followed by the reflection done in Hilbert.Map is a transpose.
+		x, y = y, x
+	}
+
+	return x, y, nil
+}
+
+// MapInverseBig transform coordinates on the Hilbert curve from (x,y) to t.
+func (s *HilbertBig) MapInverseBig(x, y *big.Int) (t *big.Int, err error) {
+	if x == nil || y == nil || !inRange(x, s.order) || !inRange(y, s.order) {
+		return nil, ErrOutOfRange
+	}
+
+	if s.verticalCompatible {
+		x, y = y, x
+	}
+
+	// Work on copies, as the rotations modify the values.
+	x, y = new(big.Int).Set(x), new(big.Int).Set(y)
+
+	one := big.NewInt(1)
+	t = new(big.Int)
+	mask := new(big.Int)
+	for i := s.order - 1; i >= 0; i-- {
+		rx := x.Bit(i) == 1
+		ry := y.Bit(i) == 1
+
+		a := 0
+		if rx {
+			a = 3
+		}
+		t.Lsh(t, 2)
+		t.Or(t, big.NewInt(int64(a^b2i(ry))))
+
+		// Clear bit i, and rotate the remaining lower bits, where mask is 2^i-1.
+		x.SetBit(x, i, 0)
+		y.SetBit(y, i, 0)
+		mask.Sub(mask.Lsh(one, uint(i)), one)
+		if !ry {
+			if rx {
+				x.Xor(x, mask)
+				y.Xor(y, mask)
+			}
+			x, y = y, x
+		}
+	}
+
+	return t, nil
+}
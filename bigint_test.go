@@ -0,0 +1,149 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func TestNewHilbertBigErrors(t *testing.T) {
+	s, err := NewHilbertBig(-1, false)
+	if s != nil || err != ErrNegativeOrder {
+		t.Errorf("NewHilbertBig(-1) = (%+v, %q) want (nil, %q)", s, err, ErrNegativeOrder)
+	}
+}
+
+func TestMapBigRangeErrors(t *testing.T) {
+	s, err := NewHilbertBig(4, false)
+	if err != nil {
+		t.Fatalf("NewHilbertBig(4) failed: %s", err)
+	}
+
+	mapTestCases := []struct {
+		t       *big.Int
+		wantErr error
+	}{
+		{nil, ErrOutOfRange},
+		{big.NewInt(-1), ErrOutOfRange},
+		{big.NewInt(0), nil},
+		{big.NewInt(255), nil},
+		{big.NewInt(256), ErrOutOfRange},
+	}
+
+	for _, tc := range mapTestCases {
+		if _, _, err := s.MapBig(tc.t); err != tc.wantErr {
+			t.Errorf("MapBig(%v) = %q want %q", tc.t, err, tc.wantErr)
+		}
+	}
+
+	mapInverseTestCases := []struct {
+		x, y    *big.Int
+		wantErr error
+	}{
+		{nil, big.NewInt(0), ErrOutOfRange},
+		{big.NewInt(0), nil, ErrOutOfRange},
+		{big.NewInt(-1), big.NewInt(0), ErrOutOfRange},
+		{big.NewInt(0), big.NewInt(16), ErrOutOfRange},
+		{big.NewInt(15), big.NewInt(15), nil},
+	}
+
+	for _, tc := range mapInverseTestCases {
+		if _, err := s.MapInverseBig(tc.x, tc.y); err != tc.wantErr {
+			t.Errorf("MapInverseBig(%v, %v) = %q want %q", tc.x, tc.y, err, tc.wantErr)
+		}
+	}
+}
+
+func TestMapBigMatchesHilbert(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		h, err := NewHilbert(16, vertical)
+		if err != nil {
+			t.Fatalf("NewHilbert(16) failed: %s", err)
+		}
+		s, err := NewHilbertBig(4, vertical)
+		if err != nil {
+			t.Fatalf("NewHilbertBig(4) failed: %s", err)
+		}
+
+		for d := 0; d < h.N*h.N; d++ {
+			wantX, wantY, _ := h.Map(d)
+			x, y, err := s.MapBig(big.NewInt(int64(d)))
+			if err != nil {
+				t.Errorf("MapBig(%d) returned error: %s", d, err)
+				continue
+			}
+			if x.Int64() != int64(wantX) || y.Int64() != int64(wantY) {
+				t.Errorf("MapBig(%d) = (%v, %v) want (%d, %d)", d, x, y, wantX, wantY)
+			}
+
+			got, err := s.MapInverseBig(x, y)
+			if err != nil {
+				t.Errorf("MapInverseBig(%v, %v) returned error: %s", x, y, err)
+				continue
+			}
+			if got.Int64() != int64(d) {
+				t.Errorf("MapInverseBig(%v, %v) = %v want %d", x, y, got, d)
+			}
+		}
+	}
+}
+
+func TestMapBigLargeOrders(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, order := range []int{0, 1, 31, 32, 63, 64, 100, 128, 257} {
+		s, err := NewHilbertBig(order, false)
+		if err != nil {
+			t.Fatalf("NewHilbertBig(%d) failed: %s", order, err)
+		}
+
+		max := new(big.Int).Lsh(big.NewInt(1), uint(2*order))
+		last := new(big.Int).Sub(max, big.NewInt(1))
+		values := []*big.Int{big.NewInt(0), last}
+		for i := 0; i < 20; i++ {
+			values = append(values, new(big.Int).Rand(r, max))
+		}
+
+		for _, d := range values {
+			x, y, err := s.MapBig(d)
+			if err != nil {
+				t.Errorf("order %d: MapBig(%v) returned error: %s", order, d, err)
+				continue
+			}
+			got, err := s.MapInverseBig(x, y)
+			if err != nil {
+				t.Errorf("order %d: MapInverseBig(%v, %v) returned error: %s", order, x, y, err)
+				continue
+			}
+			if got.Cmp(d) != 0 {
+				t.Errorf("order %d: Failed MapBig(%v) -> MapInverseBig(%v, %v) -> %v", order, d, x, y, got)
+			}
+		}
+
+		// The curve always ends at (2^order-1, 0).
+		x, y, _ := s.MapBig(last)
+		wantX := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(order)), big.NewInt(1))
+		if x.Cmp(wantX) != 0 || y.Sign() != 0 {
+			t.Errorf("order %d: MapBig(%v) = (%v, %v) want (%v, 0)", order, last, x, y, wantX)
+		}
+	}
+}
+
+// bigUint returns v as a big.Int.
+func bigUint(v uint64) *big.Int {
+	return new(big.Int).SetUint64(v)
+}
@@ -0,0 +1,86 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/rand"
+
+// maxBijectiveN is the largest N checked by IsBijective, which needs N*N bits of memory.
+const maxBijectiveN = 1 << 13
+
+// IsBijective returns true if Map is a bijection from [0, N*N) onto the cells of the space for
+// the curve's configuration, that is every cell is visited exactly once, and MapInverse returns t
+// for the cell at t. This checks every value, so ErrTooLarge is returned for N larger than 8192;
+// use IsBijectiveSampled instead. Any error from Map or MapInverse is returned.
+func (s *Hilbert) IsBijective() (bool, error) {
+	if s.N > maxBijectiveN {
+		return false, ErrTooLarge
+	}
+
+	seen := make([]uint64, (s.N*s.N+63)/64)
+	for t := 0; t < s.N*s.N; t++ {
+		x, y, err := s.Map(t)
+		if err != nil {
+			return false, err
+		}
+		if x < 0 || x >= s.N || y < 0 || y >= s.N {
+			return false, nil
+		}
+
+		p := y*s.N + x
+		if seen[p/64]&(1<<uint(p%64)) != 0 {
+			return false, nil
+		}
+		seen[p/64] |= 1 << uint(p%64)
+
+		if got, err := s.MapInverse(x, y); err != nil || got != t {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// IsBijectiveSampled is like IsBijective, but only checks the given number of random values and
+// cells, chosen using seed, so it can be used for curves of any size. Each value t must map to a
+// cell within the space which maps back to t, and each cell must map to a value which maps back
+// to the cell. A true result is strong evidence, but not proof, that the curve is a bijection.
+func (s *Hilbert) IsBijectiveSampled(samples int, seed int64) (bool, error) {
+	if samples <= 0 {
+		return false, ErrNotPositive
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	for i := 0; i < samples; i++ {
+		t := r.Intn(s.N * s.N)
+		x, y, err := s.Map(t)
+		if err != nil {
+			return false, err
+		}
+		if x < 0 || x >= s.N || y < 0 || y >= s.N {
+			return false, nil
+		}
+		if got, err := s.MapInverse(x, y); err != nil || got != t {
+			return false, err
+		}
+
+		x, y = r.Intn(s.N), r.Intn(s.N)
+		if t, err = s.MapInverse(x, y); err != nil {
+			return false, err
+		}
+		if gotX, gotY, err := s.Map(t); err != nil || gotX != x || gotY != y {
+			return false, err
+		}
+	}
+	return true, nil
+}
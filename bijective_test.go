@@ -0,0 +1,58 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestIsBijective(t *testing.T) {
+	for _, n := range []int{1, 2, 4, 32, 256} {
+		h, _ := NewHilbert(n, false)
+		v, _ := NewHilbert(n, true)
+		for _, s := range []*Hilbert{h, v, h.Reversed(), v.Reversed()} {
+			if ok, err := s.IsBijective(); !ok || err != nil {
+				t.Errorf("%s IsBijective() = (%t, %v) want (true, nil)", s.Describe(), ok, err)
+			}
+			if ok, err := s.IsBijectiveSampled(100, 1); !ok || err != nil {
+				t.Errorf("%s IsBijectiveSampled(100, 1) = (%t, %v) want (true, nil)", s.Describe(), ok, err)
+			}
+		}
+	}
+
+	// Corrupt the lookup tables, so two values map to the same cell.
+	s, _ := NewHilbert(16, false)
+	if err := s.Prewarm(); err != nil {
+		t.Fatalf("Prewarm() returned error: %s", err)
+	}
+	s.forward[7] = s.forward[8]
+	if ok, err := s.IsBijective(); ok || err != nil {
+		t.Errorf("corrupt IsBijective() = (%t, %v) want (false, nil)", ok, err)
+	}
+	if ok, err := s.IsBijectiveSampled(1000, 1); ok || err != nil {
+		t.Errorf("corrupt IsBijectiveSampled(1000, 1) = (%t, %v) want (false, nil)", ok, err)
+	}
+}
+
+func TestIsBijectiveLarge(t *testing.T) {
+	s, _ := NewHilbert(1<<MaxOrder, true)
+	if _, err := s.IsBijective(); err != ErrTooLarge {
+		t.Errorf("IsBijective() = %v want %v", err, ErrTooLarge)
+	}
+	if ok, err := s.IsBijectiveSampled(1000, 1); !ok || err != nil {
+		t.Errorf("IsBijectiveSampled(1000, 1) = (%t, %v) want (true, nil)", ok, err)
+	}
+	if _, err := s.IsBijectiveSampled(0, 1); err != ErrNotPositive {
+		t.Errorf("IsBijectiveSampled(0, 1) = %v want %v", err, ErrNotPositive)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// The walks in Map and MapInverse take one table lookup for every level, or every two levels, and
+// each lookup depends on the state from the one before, so the time grows with the order. The
+// functions here instead compute every level at once, with a fixed number of branchless bitwise
+// operations on whole words. The state at each level is an XOR prefix of the digits above it,
+// so it can be found for all levels in log2(32) doubling steps, as in the parallel prefix
+// formulation of the curve by rawrunprotected. They are used in place of the walks when
+// bitwiseCore is true, see bitwise_enabled.go, for orders of at least bitwiseMinOrder.
+
+// bitwiseMinOrder is the smallest order for which encodeBits and decodeBits are used. Their cost
+// is the same for every order, so below this the walks, which only take a few lookups, are
+// faster. At order 31 they are 3 to 4 times faster than the walks.
+const bitwiseMinOrder = 11
+
+// spreadBits spreads the low 32 bits of v out to the even bits of the result, so bit i of v
+// becomes bit 2i.
+func spreadBits(v uint64) uint64 {
+	v &= 0xFFFFFFFF
+	v = (v | v<<16) & 0x0000FFFF0000FFFF
+	v = (v | v<<8) & 0x00FF00FF00FF00FF
+	v = (v | v<<4) & 0x0F0F0F0F0F0F0F0F
+	v = (v | v<<2) & 0x3333333333333333
+	v = (v | v<<1) & 0x5555555555555555
+	return v
+}
+
+// compactBits is the inverse of spreadBits, gathering the even bits of v into the low 32 bits of
+// the result.
+func compactBits(v uint64) uint64 {
+	v &= 0x5555555555555555
+	v = (v | v>>1) & 0x3333333333333333
+	v = (v | v>>2) & 0x0F0F0F0F0F0F0F0F
+	v = (v | v>>4) & 0x00FF00FF00FF00FF
+	v = (v | v>>8) & 0x0000FFFF0000FFFF
+	v = (v | v>>16) & 0x00000000FFFFFFFF
+	return v
+}
+
+// encodeBits returns the value on the horizontal Hilbert curve of the given order, in the range
+// [0, 32], for the cell (x,y), the same as Encode2D. Bits of x and y above order are ignored.
+func encodeBits(x, y uint32, order uint) uint64 {
+	const ones = 0xFFFFFFFF
+
+	// Align the coordinates to the top of 32 bits, so every order uses the same steps. The
+	// shifts of a uint32 drop any bits above order.
+	xw, yw := uint64(x<<(32-order)), uint64(y<<(32-order))
+
+	// Each step combines the transforms of pairs of runs of levels, doubling the length of the
+	// runs, until C and D hold the transform at every level.
+	a := xw ^ yw
+	b := ones ^ a
+	c := ones ^ (xw | yw)
+	d := xw & (yw ^ ones)
+	A := a | b>>1
+	B := a>>1 ^ a
+	C := c>>1 ^ b&(d>>1) ^ c
+	D := a&(c>>1) ^ d>>1 ^ d
+
+	a, b, c, d = A, B, C, D
+	A = a&(a>>2) ^ b&(b>>2)
+	B = a&(b>>2) ^ b&((a^b)>>2)
+	C ^= a&(c>>2) ^ b&(d>>2)
+	D ^= b&(c>>2) ^ (a^b)&(d>>2)
+
+	a, b, c, d = A, B, C, D
+	A = a&(a>>4) ^ b&(b>>4)
+	B = a&(b>>4) ^ b&((a^b)>>4)
+	C ^= a&(c>>4) ^ b&(d>>4)
+	D ^= b&(c>>4) ^ (a^b)&(d>>4)
+
+	a, b, c, d = A, B, C, D
+	A = a&(a>>8) ^ b&(b>>8)
+	B = a&(b>>8) ^ b&((a^b)>>8)
+	C ^= a&(c>>8) ^ b&(d>>8)
+	D ^= b&(c>>8) ^ (a^b)&(d>>8)
+
+	// The last step only needs C and D.
+	a, b, c, d = A, B, C, D
+	C ^= a&(c>>16) ^ b&(d>>16)
+	D ^= b&(c>>16) ^ (a^b)&(d>>16)
+
+	// Recover the two bits of each digit of t from the transforms, and interleave them.
+	a, b = C^C>>1, D^D>>1
+	i0 := xw ^ yw
+	i1 := b | (ones ^ (i0 | a))
+	return (spreadBits(i1)<<1 | spreadBits(i0)) >> (64 - 2*order)
+}
+
+// decodeBits is the inverse of encodeBits, returning the cell for the value h on the horizontal
+// Hilbert curve of the given order, in the range [0, 32], the same as Decode2D. Bits of h above
+// 2*order are ignored.
+func decodeBits(h uint64, order uint) (x, y uint32) {
+	const ones = 0xFFFFFFFF
+
+	// Align h to the top of 64 bits, dropping any bits above 2*order, and split each digit into
+	// its two bits.
+	h <<= 64 - 2*order
+	i0 := compactBits(h)
+	i1 := compactBits(h >> 1)
+
+	// The transform at each level is an XOR prefix, from the top, of where the digits are 0, which
+	// transpose the quadrants below them, and where they are 3, which also flip them.
+	t0 := (i0 | i1) ^ ones
+	t1 := i0 & i1
+	t0 ^= t0 >> 16
+	t1 ^= t1 >> 16
+	t0 ^= t0 >> 8
+	t1 ^= t1 >> 8
+	t0 ^= t0 >> 4
+	t1 ^= t1 >> 4
+	t0 ^= t0 >> 2
+	t1 ^= t1 >> 2
+	t0 ^= t0 >> 1
+	t1 ^= t1 >> 1
+
+	a := (i0^ones)&t1 | i0&t0
+	return uint32((a ^ i1) >> (32 - order)), uint32((a ^ i0 ^ i1) >> (32 - order))
+}
@@ -0,0 +1,24 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !purego
+
+package hilbert
+
+import "math/bits"
+
+// bitwiseCore is true if Map, MapInverse and their batch and fixed order forms use encodeBits
+// and decodeBits instead of walking the levels. They work on 64 bit words, so are only used where
+// those are native. Build with the purego tag to always use the walks.
+const bitwiseCore = bits.UintSize == 64
@@ -0,0 +1,167 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// walkEncode and walkDecode walk the levels one at a time, as Encode2D and Decode2D do when the
+// bitwise core is not used, to check encodeBits and decodeBits against.
+func walkEncode(x, y uint32, order int) uint64 {
+	var h uint64
+	state := uint8(0)
+	for shift := uint(order); shift > 0; shift-- {
+		e := inverseStates[state<<2|uint8(x>>(shift-1)&1)<<1|uint8(y>>(shift-1)&1)]
+		h = h<<2 | uint64(e>>2)
+		state = e & 3
+	}
+	return h
+}
+
+func walkDecode(h uint64, order int) (x, y uint32) {
+	state := uint8(0)
+	for shift := uint(2 * order); shift > 0; shift -= 2 {
+		e := forwardStates[state<<2|uint8(h>>(shift-2)&3)]
+		x = x<<1 | uint32(e>>3)
+		y = y<<1 | uint32(e>>2&1)
+		state = e & 3
+	}
+	return x, y
+}
+
+func TestEncodeBits(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for order := 0; order <= MaxOrder64; order++ {
+		for i := 0; i < 1000; i++ {
+			x, y := r.Uint32(), r.Uint32()
+			mask := uint32(1<<uint(order) - 1)
+			want := walkEncode(x&mask, y&mask, order)
+			if got := encodeBits(x, y, uint(order)); got != want {
+				t.Fatalf("encodeBits(%d, %d, %d) = %d want %d", x, y, order, got, want)
+			}
+
+			// Also check the round trip, with bits of h above 2*order set.
+			h := r.Uint64()
+			hmask := uint64(1)<<uint(2*order) - 1
+			if order == MaxOrder64 {
+				hmask = ^uint64(0)
+			}
+			wantX, wantY := walkDecode(h&hmask, order)
+			if gotX, gotY := decodeBits(h, uint(order)); gotX != wantX || gotY != wantY {
+				t.Fatalf("decodeBits(%d, %d) = (%d, %d) want (%d, %d)", h, order, gotX, gotY, wantX, wantY)
+			}
+			if got := encodeBits(wantX, wantY, uint(order)); got != h&hmask {
+				t.Fatalf("encodeBits(decodeBits(%d, %d)) = %d want %d", h, order, got, h&hmask)
+			}
+		}
+	}
+}
+
+func TestLargeOrderMatchesReference(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, order := range []int{bitwiseMinOrder - 1, bitwiseMinOrder, (bitwiseMinOrder + MaxOrder) / 2, MaxOrder} {
+		h, _ := NewHilbert(1<<uint(order), false)
+		v, _ := NewHilbert(1<<uint(order), true)
+		for _, s := range []*Hilbert{h, h.Reversed(), v, v.Reversed()} {
+			for i := 0; i < 1000; i++ {
+				d := r.Intn(s.N * s.N)
+				wantX, wantY := mapReference(s, d)
+				if x, y, _ := s.Map(d); x != wantX || y != wantY {
+					t.Errorf("NewHilbert(1<<%d, %t).Map(%d) = (%d, %d) want (%d, %d)", order, s.verticalCompatible, d, x, y, wantX, wantY)
+				}
+				if got, _ := s.MapInverse(wantX, wantY); got != d {
+					t.Errorf("NewHilbert(1<<%d, %t).MapInverse(%d, %d) = %d want %d", order, s.verticalCompatible, wantX, wantY, got, d)
+				}
+			}
+		}
+	}
+}
+
+func TestLargeOrderTransforms(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	n := 1 << 12
+	base, _ := NewHilbert(n, false)
+	for _, mirror := range []bool{false, true} {
+		for rotation := 0; rotation < 4; rotation++ {
+			tr := Transform{Mirror: mirror, Rotation: rotation}
+			s, _ := NewHilbert(n, false, WithTransform(tr))
+
+			ts, xs, ys := make([]int, 1000), make([]int, 1000), make([]int, 1000)
+			for i := range ts {
+				ts[i] = r.Intn(n * n)
+			}
+			s.MapBatch(ts, xs, ys)
+			for i, d := range ts {
+				bx, by, _ := base.Map(d)
+				wantX, wantY := tr.Apply(n, bx, by)
+				if x, y, _ := s.Map(d); x != wantX || y != wantY {
+					t.Errorf("WithTransform(%+v).Map(%d) = (%d, %d) want (%d, %d)", tr, d, x, y, wantX, wantY)
+				}
+				if xs[i] != wantX || ys[i] != wantY {
+					t.Errorf("WithTransform(%+v).MapBatch()[%d] = (%d, %d) want (%d, %d)", tr, i, xs[i], ys[i], wantX, wantY)
+				}
+				if got, _ := s.MapInverse(wantX, wantY); got != d {
+					t.Errorf("WithTransform(%+v).MapInverse(%d, %d) = %d want %d", tr, wantX, wantY, got, d)
+				}
+			}
+
+			want := append([]int(nil), ts...)
+			s.MapInverseBatch(xs, ys, ts)
+			for i := range ts {
+				if ts[i] != want[i] {
+					t.Errorf("WithTransform(%+v).MapInverseBatch()[%d] = %d want %d", tr, i, ts[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkMapBatchLarge(b *testing.B) {
+	s, err := NewHilbert(1<<MaxOrder, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+	r := rand.New(rand.NewSource(1))
+	ts := make([]int, 4096)
+	for i := range ts {
+		ts[i] = r.Intn(s.N * s.N)
+	}
+	xs, ys := make([]int, len(ts)), make([]int, len(ts))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.MapBatch(ts, xs, ys)
+	}
+}
+
+func BenchmarkMapInverseBatchLarge(b *testing.B) {
+	s, err := NewHilbert(1<<MaxOrder, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+	r := rand.New(rand.NewSource(1))
+	xs, ys := make([]int, 4096), make([]int, 4096)
+	for i := range xs {
+		xs[i], ys[i] = r.Intn(s.N), r.Intn(s.N)
+	}
+	ts := make([]int, len(xs))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.MapInverseBatch(xs, ys, ts)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// BoundsPolicy controls how Map and MapInverse handle values outside of the space.
+type BoundsPolicy int
+
+// Supported bounds policies.
+const (
+	// BoundsError returns ErrOutOfRange for values outside of the space. This is the default.
+	BoundsError BoundsPolicy = iota
+
+	// BoundsClamp moves values outside of the space to the nearest edge, so Map(-1) is the same
+	// as Map(0), and MapInverse(x, N) is the same as MapInverse(x, N-1).
+	BoundsClamp
+
+	// BoundsWrap wraps values outside of the space around to the other side, so Map(-1) is the
+	// same as Map(N*N-1), and MapInverse(x, N) is the same as MapInverse(x, 0).
+	BoundsWrap
+)
+
+// Option configures optional behaviour of a Hilbert curve created by NewHilbert.
+type Option func(*Hilbert)
+
+// WithBoundsPolicy sets how the curve handles values outside of the space.
+func WithBoundsPolicy(p BoundsPolicy) Option {
+	return func(s *Hilbert) {
+		s.bounds = p
+	}
+}
+
+// fit applies the bounds policy to v, which is outside of [0, n-1], returning the value to use
+// instead, or false if the policy is to return an error.
+func (p BoundsPolicy) fit(v, n int) (int, bool) {
+	switch p {
+	case BoundsClamp:
+		if v < 0 {
+			return 0, true
+		}
+		return n - 1, true
+	case BoundsWrap:
+		v %= n
+		if v < 0 {
+			v += n
+		}
+		return v, true
+	}
+	return -1, false
+}
@@ -0,0 +1,114 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestBoundsPolicyMap(t *testing.T) {
+	testCases := []struct {
+		policy  BoundsPolicy
+		d       int
+		wantD   int // The value within the space which should be mapped instead.
+		wantErr error
+	}{
+		{BoundsError, -1, -1, ErrOutOfRange},
+		{BoundsError, 0, 0, nil},
+		{BoundsError, 255, 255, nil},
+		{BoundsError, 256, -1, ErrOutOfRange},
+		{BoundsClamp, -1, 0, nil},
+		{BoundsClamp, -1000, 0, nil},
+		{BoundsClamp, 0, 0, nil},
+		{BoundsClamp, 255, 255, nil},
+		{BoundsClamp, 256, 255, nil},
+		{BoundsClamp, 1000, 255, nil},
+		{BoundsWrap, -1, 255, nil},
+		{BoundsWrap, -256, 0, nil},
+		{BoundsWrap, -257, 255, nil},
+		{BoundsWrap, 0, 0, nil},
+		{BoundsWrap, 255, 255, nil},
+		{BoundsWrap, 256, 0, nil},
+		{BoundsWrap, 513, 1, nil},
+	}
+
+	ref, _ := NewHilbert(16, false)
+	for _, tc := range testCases {
+		s, err := NewHilbert(16, false, WithBoundsPolicy(tc.policy))
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		wantX, wantY := -1, -1
+		if tc.wantErr == nil {
+			wantX, wantY, _ = ref.Map(tc.wantD)
+		}
+		x, y, err := s.Map(tc.d)
+		if x != wantX || y != wantY || err != tc.wantErr {
+			t.Errorf("policy %d: Map(%d) = (%d, %d, %v) want (%d, %d, %v)", tc.policy, tc.d, x, y, err, wantX, wantY, tc.wantErr)
+		}
+	}
+}
+
+func TestBoundsPolicyMapInverse(t *testing.T) {
+	testCases := []struct {
+		policy       BoundsPolicy
+		x, y         int
+		wantX, wantY int // The coordinates within the space which should be mapped instead.
+		wantErr      error
+	}{
+		{BoundsError, -1, 0, -1, -1, ErrOutOfRange},
+		{BoundsError, 0, 16, -1, -1, ErrOutOfRange},
+		{BoundsError, 15, 15, 15, 15, nil},
+		{BoundsClamp, -1, 0, 0, 0, nil},
+		{BoundsClamp, 16, -5, 15, 0, nil},
+		{BoundsClamp, 3, 100, 3, 15, nil},
+		{BoundsClamp, 16, 16, 15, 15, nil},
+		{BoundsWrap, -1, 0, 15, 0, nil},
+		{BoundsWrap, 16, -1, 0, 15, nil},
+		{BoundsWrap, 3, 33, 3, 1, nil},
+		{BoundsWrap, -16, -17, 0, 15, nil},
+	}
+
+	ref, _ := NewHilbert(16, true)
+	for _, tc := range testCases {
+		s, err := NewHilbert(16, true, WithBoundsPolicy(tc.policy))
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		want := -1
+		if tc.wantErr == nil {
+			want, _ = ref.MapInverse(tc.wantX, tc.wantY)
+		}
+		got, err := s.MapInverse(tc.x, tc.y)
+		if got != want || err != tc.wantErr {
+			t.Errorf("policy %d: MapInverse(%d, %d) = (%d, %v) want (%d, %v)", tc.policy, tc.x, tc.y, got, err, want, tc.wantErr)
+		}
+	}
+}
+
+func TestBoundsPolicyKept(t *testing.T) {
+	s, _ := NewHilbert(16, false, WithBoundsPolicy(BoundsWrap))
+	r := s.Reversed()
+	if x, y, err := r.Map(256); err != nil || x != 15 || y != 0 {
+		t.Errorf("Reversed().Map(256) = (%d, %d, %v) want (15, 0, nil)", x, y, err)
+	}
+
+	if err := s.Prewarm(); err != nil {
+		t.Fatalf("Prewarm() returned error: %s", err)
+	}
+	if x, y, err := s.Map(-1); err != nil || x != 15 || y != 0 {
+		t.Errorf("Prewarm(); Map(-1) = (%d, %d, %v) want (15, 0, nil)", x, y, err)
+	}
+}
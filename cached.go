@@ -0,0 +1,138 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"container/list"
+	"sync"
+)
+
+// DefaultCacheSize is the number of results each of Map and MapInverse remember in a CachedCurve.
+const DefaultCacheSize = 4096
+
+// CachedCurve wraps any SpaceFilling, remembering the results of the most recent calls to Map and
+// MapInverse so repeated calls for the same values do not need to be computed again. It is safe
+// for concurrent use, as long as the wrapped curve is. Implements SpaceFilling interface.
+type CachedCurve struct {
+	curve SpaceFilling
+
+	points  *lru[int, [2]int] // t to (x,y)
+	indexes *lru[[2]int, int] // (x,y) to t
+}
+
+// NewCached returns a CachedCurve wrapping c. Map and MapInverse each remember up to
+// DefaultCacheSize results. Once full, the least recently used result is evicted to make room for
+// a new one. Errors are not cached.
+func NewCached(c SpaceFilling) *CachedCurve {
+	return &CachedCurve{
+		curve:   c,
+		points:  newLRU[int, [2]int](DefaultCacheSize),
+		indexes: newLRU[[2]int, int](DefaultCacheSize),
+	}
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (c *CachedCurve) GetDimensions() (int, int) {
+	return c.curve.GetDimensions()
+}
+
+// Map transforms a one dimension value, t, to coordinates on the wrapped curve.
+func (c *CachedCurve) Map(t int) (x, y int, err error) {
+	if p, ok := c.points.get(t); ok {
+		return p[0], p[1], nil
+	}
+
+	x, y, err = c.curve.Map(t)
+	if err != nil {
+		return x, y, err
+	}
+	c.points.add(t, [2]int{x, y})
+	return x, y, nil
+}
+
+// MapInverse transform coordinates on the wrapped curve from (x,y) to t.
+func (c *CachedCurve) MapInverse(x, y int) (t int, err error) {
+	if t, ok := c.indexes.get([2]int{x, y}); ok {
+		return t, nil
+	}
+
+	t, err = c.curve.MapInverse(x, y)
+	if err != nil {
+		return t, err
+	}
+	c.indexes.add([2]int{x, y}, t)
+	return t, nil
+}
+
+// lru is a concurrency-safe map holding at most size entries, evicting the least recently used
+// entry when full.
+type lru[K comparable, V any] struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // Of *lruEntry, most recently used first
+	entries map[K]*list.Element
+}
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+func newLRU[K comparable, V any](size int) *lru[K, V] {
+	return &lru[K, V]{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[K]*list.Element, size),
+	}
+}
+
+// get returns the value for key, marking it as the most recently used.
+func (l *lru[K, V]) get(key K) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e, ok := l.entries[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	l.order.MoveToFront(e)
+	return e.Value.(*lruEntry[K, V]).value, true
+}
+
+// add sets the value for key, evicting the least recently used entry if the map is full.
+func (l *lru[K, V]) add(key K, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if e, ok := l.entries[key]; ok {
+		e.Value.(*lruEntry[K, V]).value = value
+		l.order.MoveToFront(e)
+		return
+	}
+	if l.order.Len() >= l.size {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry[K, V]).key)
+	}
+	l.entries[key] = l.order.PushFront(&lruEntry[K, V]{key, value})
+}
+
+// len returns the number of entries in the map.
+func (l *lru[K, V]) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
@@ -0,0 +1,155 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"sync"
+	"testing"
+)
+
+// countingCurve wraps a SpaceFilling counting the calls to Map and MapInverse.
+type countingCurve struct {
+	SpaceFilling
+
+	mu                sync.Mutex
+	maps, mapInverses int
+}
+
+func (c *countingCurve) Map(t int) (x, y int, err error) {
+	c.mu.Lock()
+	c.maps++
+	c.mu.Unlock()
+	return c.SpaceFilling.Map(t)
+}
+
+func (c *countingCurve) MapInverse(x, y int) (t int, err error) {
+	c.mu.Lock()
+	c.mapInverses++
+	c.mu.Unlock()
+	return c.SpaceFilling.MapInverse(x, y)
+}
+
+func TestCachedCurve(t *testing.T) {
+	h, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	counter := &countingCurve{SpaceFilling: h}
+	c := NewCached(counter)
+
+	if w, h := c.GetDimensions(); w != 16 || h != 16 {
+		t.Errorf("GetDimensions() = (%d, %d) want (16, 16)", w, h)
+	}
+
+	for i := 0; i < 3; i++ {
+		for d := 0; d < 256; d++ {
+			wantX, wantY, _ := h.Map(d)
+			x, y, err := c.Map(d)
+			if err != nil || x != wantX || y != wantY {
+				t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, wantX, wantY)
+			}
+			if got, err := c.MapInverse(x, y); err != nil || got != d {
+				t.Errorf("MapInverse(%d, %d) = (%d, %v) want (%d, nil)", x, y, got, err, d)
+			}
+		}
+	}
+	if counter.maps != 256 || counter.mapInverses != 256 {
+		t.Errorf("wrapped curve called (%d, %d) times want (256, 256)", counter.maps, counter.mapInverses)
+	}
+
+	// Errors are returned, and not cached.
+	for i := 0; i < 2; i++ {
+		if _, _, err := c.Map(-1); err != ErrOutOfRange {
+			t.Errorf("Map(-1) = %v want %v", err, ErrOutOfRange)
+		}
+		if _, err := c.MapInverse(16, 0); err != ErrOutOfRange {
+			t.Errorf("MapInverse(16, 0) = %v want %v", err, ErrOutOfRange)
+		}
+	}
+	if counter.maps != 258 || counter.mapInverses != 258 {
+		t.Errorf("wrapped curve called (%d, %d) times want (258, 258)", counter.maps, counter.mapInverses)
+	}
+}
+
+func TestCachedCurveEviction(t *testing.T) {
+	h, err := NewHilbert(128, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	counter := &countingCurve{SpaceFilling: h}
+	c := NewCached(counter)
+
+	for d := 0; d < DefaultCacheSize+10; d++ {
+		c.Map(d)
+	}
+	if got := c.points.len(); got != DefaultCacheSize {
+		t.Errorf("cache holds %d results want %d", got, DefaultCacheSize)
+	}
+
+	// The first values were least recently used, so have been evicted, but the last remain.
+	c.Map(DefaultCacheSize + 9)
+	if counter.maps != DefaultCacheSize+10 {
+		t.Errorf("Map(%d) was not cached", DefaultCacheSize+9)
+	}
+	c.Map(0)
+	if counter.maps != DefaultCacheSize+11 {
+		t.Errorf("Map(0) was not evicted")
+	}
+}
+
+func TestLRU(t *testing.T) {
+	l := newLRU[int, string](2)
+	l.add(1, "one")
+	l.add(2, "two")
+	l.get(1) // 2 is now the least recently used
+	l.add(3, "three")
+
+	for _, tc := range []struct {
+		key  int
+		want string
+		ok   bool
+	}{
+		{1, "one", true},
+		{2, "", false},
+		{3, "three", true},
+	} {
+		if got, ok := l.get(tc.key); got != tc.want || ok != tc.ok {
+			t.Errorf("get(%d) = (%q, %t) want (%q, %t)", tc.key, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestCachedCurveConcurrent(t *testing.T) {
+	h, err := NewHilbert(64, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	c := NewCached(h)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := 0; d < h.N*h.N; d++ {
+				x, y, _ := c.Map(d)
+				if got, _ := c.MapInverse(x, y); got != d {
+					t.Errorf("MapInverse(Map(%d)) = %d", d, got)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
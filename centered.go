@@ -0,0 +1,105 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"errors"
+	"math"
+)
+
+// Centered is a Hilbert curve over a space centered at the origin, so x and y are within
+// [-n/2, n/2-1], for grids which are symmetric around zero. The translation to and from the
+// underlying curve, whose coordinates are within [0, n-1], is handled internally, so callers never
+// need to offset coordinates themselves. Values on the curve are the same as those of the
+// underlying curve.
+//
+// The generic functions which take a SpaceFilling assume coordinates start at zero, so Centered
+// deliberately does not implement it, and its methods are named MapCentered and
+// MapInverseCentered instead. Use Curve for those functions.
+type Centered struct {
+	curve *Hilbert
+	half  int // Added to centered coordinates to give those of the underlying curve
+}
+
+// NewCentered returns a new Centered curve of width and height n, which must be a power of two,
+// as for NewHilbert. For n=1 the only cell is (0,0).
+func NewCentered(n int, verticalCompatible bool, opts ...Option) (*Centered, error) {
+	curve, err := NewHilbert(n, verticalCompatible, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Centered{curve: curve, half: n / 2}, nil
+}
+
+// Curve returns the underlying curve, whose coordinates are not centered.
+func (s *Centered) Curve() *Hilbert {
+	return s.curve
+}
+
+// Bounds returns the smallest and largest coordinate within the space, -n/2 and n/2-1, which are
+// the same for x and y.
+func (s *Centered) Bounds() (lo, hi int) {
+	return -s.half, s.curve.N - 1 - s.half
+}
+
+// GetDimensions returns the width and height of the 2D space. The coordinates are within Bounds
+// rather than starting at zero.
+func (s *Centered) GetDimensions() (int, int) {
+	return s.curve.N, s.curve.N
+}
+
+// MapCentered transforms a one dimension value, t, in the range [0, n^2-1] to centered
+// coordinates, where x and y are within [-n/2, n/2-1]. On error (math.MinInt, math.MinInt) is
+// returned, which is never within the space.
+func (s *Centered) MapCentered(t int) (x, y int, err error) {
+	x, y, err = s.curve.Map(t)
+	if err != nil {
+		return math.MinInt, math.MinInt, err
+	}
+	return x - s.half, y - s.half, nil
+}
+
+// MapInverseCentered transforms the centered coordinates (x,y) to t. Coordinates outside of the
+// space are handled by the curve's bounds policy, as for Hilbert.MapInverse.
+func (s *Centered) MapInverseCentered(x, y int) (t int, err error) {
+	return s.curve.MapInverse(s.shift(x), s.shift(y))
+}
+
+// RangeQuery is like Hilbert.RangeQuery, but the corners are centered coordinates. A *RangeError
+// naming the first invalid corner coordinate, also in centered coordinates, is returned if the
+// rectangle is not within the space.
+func (s *Centered) RangeQuery(x0, y0, x1, y1 int) ([]Range, error) {
+	ranges, err := s.curve.RangeQuery(s.shift(x0), s.shift(y0), s.shift(x1), s.shift(y1))
+	var rangeErr *RangeError
+	if errors.As(err, &rangeErr) {
+		// Report the coordinate as given, rather than as shifted for the underlying curve.
+		return nil, &RangeError{
+			Name:  rangeErr.Name,
+			Value: rangeErr.Value - s.half,
+			Min:   rangeErr.Min - s.half,
+			Max:   rangeErr.Max - s.half,
+		}
+	}
+	return ranges, err
+}
+
+// shift translates the centered coordinate v to the coordinate on the underlying curve, saturating
+// rather than overflowing, so that coordinates far outside the space stay outside it.
+func (s *Centered) shift(v int) int {
+	if v > math.MaxInt-s.half {
+		return math.MaxInt
+	}
+	return v + s.half
+}
@@ -0,0 +1,122 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCentered(t *testing.T) {
+	for _, n := range []int{1, 2, 16} {
+		s, err := NewCentered(n, false)
+		if err != nil {
+			t.Fatalf("NewCentered(%d) failed: %s", n, err)
+		}
+		h := s.Curve()
+
+		lo, hi := s.Bounds()
+		if lo != -n/2 || hi != n-1-n/2 {
+			t.Errorf("Bounds() = (%d, %d) want (%d, %d)", lo, hi, -n/2, n-1-n/2)
+		}
+
+		for d := 0; d < n*n; d++ {
+			hx, hy, _ := h.Map(d)
+			x, y, err := s.MapCentered(d)
+			if err != nil || x != hx-n/2 || y != hy-n/2 {
+				t.Errorf("MapCentered(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, hx-n/2, hy-n/2)
+			}
+			if x < lo || x > hi || y < lo || y > hi {
+				t.Errorf("MapCentered(%d) = (%d, %d) not within [%d, %d]", d, x, y, lo, hi)
+			}
+			if got, err := s.MapInverseCentered(x, y); err != nil || got != d {
+				t.Errorf("MapInverseCentered(%d, %d) = (%d, %v) want (%d, nil)", x, y, got, err, d)
+			}
+		}
+	}
+}
+
+func TestCenteredErrors(t *testing.T) {
+	s, err := NewCentered(8, false)
+	if err != nil {
+		t.Fatalf("NewCentered(8) failed: %s", err)
+	}
+
+	if _, err := NewCentered(6, false); err != ErrNotPowerOfTwo {
+		t.Errorf("NewCentered(6) = %v want %v", err, ErrNotPowerOfTwo)
+	}
+	lo, hi := s.Bounds()
+	if x, y, err := s.MapCentered(64); err != ErrOutOfRange || (x >= lo && x <= hi) || (y >= lo && y <= hi) {
+		t.Errorf("MapCentered(64) = (%d, %d, %v) want coordinates outside [%d, %d] and %v", x, y, err, lo, hi, ErrOutOfRange)
+	}
+
+	// Generic functions assume coordinates start at zero, so must not accept a Centered.
+	if _, ok := any(s).(SpaceFilling); ok {
+		t.Errorf("Centered implements SpaceFilling")
+	}
+
+	// The negative boundary is inside the space, and one past either boundary is not.
+	for _, p := range [][2]int{{-4, -4}, {-4, 3}, {3, -4}, {3, 3}} {
+		if _, err := s.MapInverseCentered(p[0], p[1]); err != nil {
+			t.Errorf("MapInverseCentered(%d, %d) returned error: %s", p[0], p[1], err)
+		}
+	}
+	for _, p := range [][2]int{{-5, 0}, {0, -5}, {4, 0}, {0, 4}, {math.MaxInt, 0}} {
+		if _, err := s.MapInverseCentered(p[0], p[1]); err != ErrOutOfRange {
+			t.Errorf("MapInverseCentered(%d, %d) = %v want %v", p[0], p[1], err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestCenteredBoundsPolicy(t *testing.T) {
+	s, err := NewCentered(8, false, WithBoundsPolicy(BoundsWrap))
+	if err != nil {
+		t.Fatalf("NewCentered(8) failed: %s", err)
+	}
+
+	want, _ := s.MapInverseCentered(3, -4)
+	if got, err := s.MapInverseCentered(-5, 4); err != nil || got != want {
+		t.Errorf("MapInverseCentered(-5, 4) = (%d, %v) want (%d, nil)", got, err, want)
+	}
+}
+
+func TestCenteredRangeQuery(t *testing.T) {
+	s, err := NewCentered(8, false)
+	if err != nil {
+		t.Fatalf("NewCentered(8) failed: %s", err)
+	}
+
+	got, err := s.RangeQuery(-4, -4, -1, 1)
+	if err != nil {
+		t.Fatalf("RangeQuery(-4, -4, -1, 1) returned error: %s", err)
+	}
+	if want, _ := s.Curve().RangeQuery(0, 0, 3, 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("RangeQuery(-4, -4, -1, 1) = %v want %v", got, want)
+	}
+
+	_, err = s.RangeQuery(-5, -4, 0, 0)
+	var rangeErr *RangeError
+	if !errors.As(err, &rangeErr) {
+		t.Fatalf("RangeQuery(-5, -4, 0, 0) = %v want a *RangeError", err)
+	}
+	if want := (RangeError{Name: "x0", Value: -5, Min: -4, Max: 0}); *rangeErr != want {
+		t.Errorf("RangeQuery(-5, -4, 0, 0) = %+v want %+v", *rangeErr, want)
+	}
+	if !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("RangeQuery(-5, -4, 0, 0) = %v want %v", err, ErrOutOfRange)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/google/hilbert"
+	"github.com/google/hilbert/draw"
+	"github.com/google/hilbert/hilbertio"
+)
+
+// formatVar defines the -format flag on fs, for the format of records read and written.
+func formatVar(fs *flag.FlagSet) *string {
+	return fs.String("format", "csv", "the format of the records, csv or ndjson")
+}
+
+// parseFormat returns the hilbertio.Format named by name.
+func parseFormat(name string) (hilbertio.Format, error) {
+	switch name {
+	case "csv":
+		return hilbertio.CSV, nil
+	case "ndjson":
+		return hilbertio.NDJSON, nil
+	}
+	return 0, fmt.Errorf("unknown format %q, want csv or ndjson", name)
+}
+
+// runMap runs the map command.
+func runMap(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	return convert(fs, args, stdin, stdout, hilbertio.Forward)
+}
+
+// runInverse runs the inverse command.
+func runInverse(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	return convert(fs, args, stdin, stdout, hilbertio.Inverse)
+}
+
+// convert converts the records given as arguments, one per argument, or else read from stdin,
+// with hilbertio.ConvertStream.
+func convert(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer, dir hilbertio.Direction) error {
+	curve := curveVar(fs)
+	formatName := formatVar(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	format, err := parseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		stdin = strings.NewReader(strings.Join(args, "\n") + "\n")
+	}
+	return hilbertio.ConvertStream(stdin, stdout, curve.curve, format, dir)
+}
+
+// runRanges runs the ranges command.
+func runRanges(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	curve := curveVar(fs)
+	maxRanges := fs.Int("max", 0, "the most ranges to print, merging ranges across the smallest gaps, or 0 for no limit")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 4 {
+		fs.Usage()
+		return errUsage
+	}
+	var rect [4]int
+	for i, arg := range args {
+		if rect[i], err = strconv.Atoi(arg); err != nil {
+			return fmt.Errorf("invalid coordinate %q", arg)
+		}
+	}
+	s, ok := curve.curve.(*hilbert.Hilbert)
+	if !ok {
+		return errors.New("ranges is only supported for hilbert curves")
+	}
+
+	var ranges []hilbert.Range
+	if *maxRanges > 0 {
+		ranges, err = s.Ranges(rect[0], rect[1], rect[2], rect[3], *maxRanges)
+	} else {
+		ranges, err = s.RangeQuery(rect[0], rect[1], rect[2], rect[3])
+	}
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(stdout)
+	for _, r := range ranges {
+		fmt.Fprintf(bw, "%d,%d\n", r.Lo, r.Hi)
+	}
+	return bw.Flush()
+}
+
+// runRender runs the render command.
+func runRender(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	curve := curveVar(fs)
+	cellSize := fs.Int("cell", 8, "the width and height of each cell in pixels")
+	formatName := fs.String("format", "png", "the format of the image, png or svg")
+	output := fs.String("o", "", "the file to write the image to, instead of stdout")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		fs.Usage()
+		return errUsage
+	}
+	if *cellSize < 1 {
+		return errors.New("cell size must be at least 1")
+	}
+	if *formatName != "png" && *formatName != "svg" {
+		return fmt.Errorf("unknown format %q, want png or svg", *formatName)
+	}
+
+	svg := *formatName == "svg"
+	if *output == "" {
+		return render(stdout, curve.curve, *cellSize, svg)
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := render(f, curve.curve, *cellSize, svg); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// render writes an image of s to w, as SVG if svg is true, or else PNG.
+func render(w io.Writer, s hilbert.SpaceFilling, cellSize int, svg bool) error {
+	bw := bufio.NewWriter(w)
+	var err error
+	if svg {
+		err = draw.WriteSVG(bw, s, cellSize)
+	} else {
+		err = png.Encode(bw, draw.DrawCurve(s, cellSize))
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// runSort runs the sort command.
+func runSort(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error {
+	formatName := formatVar(fs)
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		fs.Usage()
+		return errUsage
+	}
+	format, err := parseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	if format == hilbertio.NDJSON {
+		return sortNDJSON(stdin, stdout)
+	}
+	return sortCSV(stdin, stdout)
+}
+
+// point is a record to sort, with its coordinates.
+type point[T any] struct {
+	p      image.Point
+	record T
+}
+
+// sortCSV sorts CSV records, whose first two fields are x and y, with hilbert.SortFunc. A first
+// record which is not numeric is taken to be a header, and is kept first.
+func sortCSV(r io.Reader, w io.Writer) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	points := make([]point[[]string], 0, len(records))
+	for i, record := range records {
+		p, ok := parsePoint(record)
+		if !ok && i == 0 {
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+			continue
+		}
+		if !ok {
+			return fmt.Errorf("record %d: %s", i+1, hilbertio.ErrBadRecord)
+		}
+		points = append(points, point[[]string]{p, record})
+	}
+
+	hilbert.SortFunc(points, func(p point[[]string]) image.Point { return p.p })
+	for _, p := range points {
+		if err := cw.Write(p.record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// parsePoint returns the coordinates in the first two fields of record.
+func parsePoint(record []string) (image.Point, bool) {
+	if len(record) < 2 {
+		return image.Point{}, false
+	}
+	x, errX := strconv.Atoi(strings.TrimSpace(record[0]))
+	y, errY := strconv.Atoi(strings.TrimSpace(record[1]))
+	return image.Point{x, y}, errX == nil && errY == nil
+}
+
+// sortNDJSON sorts NDJSON objects, with the members "x" and "y", with hilbert.SortFunc. The
+// objects are written unchanged, and blank lines are dropped.
+func sortNDJSON(r io.Reader, w io.Writer) error {
+	var points []point[[]byte]
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := br.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			var p struct {
+				X, Y *int
+			}
+			if json.Unmarshal(line, &p) != nil || p.X == nil || p.Y == nil {
+				return fmt.Errorf("line %d: %s", n, hilbertio.ErrBadRecord)
+			}
+			points = append(points, point[[]byte]{image.Point{*p.X, *p.Y}, line})
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	hilbert.SortFunc(points, func(p point[[]byte]) image.Point { return p.p })
+	bw := bufio.NewWriter(w)
+	for _, p := range points {
+		bw.Write(p.record)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
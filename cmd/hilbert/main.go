@@ -0,0 +1,176 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Command hilbert converts between values on space-filling curves and coordinates in their
+// space, for use in shell pipelines. Usage:
+//
+//	hilbert <command> [flags] [args]
+//
+// The commands are:
+//
+//	map      convert values on the curve, t, to coordinates, x,y
+//	inverse  convert coordinates, x,y, to values on the curve, t
+//	ranges   print the ranges of values on the curve covering a rectangle
+//	render   draw the curve as a PNG or SVG image
+//	sort     sort records by their coordinates along a Hilbert curve
+//
+// The curve is chosen with the -curve flag, in the text form written by the MarshalText methods
+// of the hilbert package, such as "hilbert 16 vertical" or "peano 27". Run "hilbert <command> -h"
+// for the flags of each command.
+package main
+
+import (
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/hilbert"
+)
+
+// errUsage is returned when the command line is invalid, after the usage has been printed.
+var errUsage = errors.New("invalid usage")
+
+// command is a subcommand, run with the arguments after its name.
+type command struct {
+	usage string // The arguments taken, after the flags
+	help  string // A one line description
+	run   func(fs *flag.FlagSet, args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = map[string]command{
+	"map":     {"[t ...]", "convert values on the curve, t, to coordinates, x,y", runMap},
+	"inverse": {"[x,y ...]", "convert coordinates, x,y, to values on the curve, t", runInverse},
+	"ranges":  {"x0 y0 x1 y1", "print the ranges of values on the curve covering a rectangle", runRanges},
+	"render":  {"", "draw the curve as a PNG or SVG image", runRender},
+	"sort":    {"", "sort records by their coordinates along a Hilbert curve", runSort},
+}
+
+func main() {
+	switch err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err {
+	case nil:
+	case errUsage:
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, "hilbert:", err)
+		os.Exit(1)
+	}
+}
+
+// run runs the command named by args[0] with the rest of args, printing usage to stderr.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return errUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "hilbert: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: hilbert %s [flags] %s\n\n%s%s.\n\nFlags:\n", args[0], cmd.usage, strings.ToUpper(cmd.help[:1]), cmd.help[1:])
+		fs.PrintDefaults()
+	}
+	if err := cmd.run(fs, args[1:], stdin, stdout); err != flag.ErrHelp {
+		return err
+	}
+	return nil
+}
+
+// printUsage prints the list of commands to w.
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: hilbert <command> [flags] [args]\n\nCommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", name, commands[name].help)
+	}
+}
+
+// parseFlags parses args with fs, returning the remaining arguments. errUsage is returned if the
+// flags are invalid, which the flag package has already reported along with the usage.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+	return fs.Args(), nil
+}
+
+// curveFlag is a flag holding a curve, set from its text form, as written by MarshalText.
+type curveFlag struct {
+	curve hilbert.SpaceFilling
+}
+
+// textCurve is a curve which can be marshalled to and from text.
+type textCurve interface {
+	hilbert.SpaceFilling
+	encoding.TextMarshaler
+	encoding.TextUnmarshaler
+}
+
+// String implements flag.Value.
+func (f *curveFlag) String() string {
+	if c, ok := f.curve.(textCurve); ok {
+		text, _ := c.MarshalText()
+		return string(text)
+	}
+	return ""
+}
+
+// Set implements flag.Value.
+func (f *curveFlag) Set(text string) error {
+	var c textCurve
+	switch strings.SplitN(strings.TrimSpace(text), " ", 2)[0] {
+	case "hilbert":
+		c = new(hilbert.Hilbert)
+	case "peano":
+		c = new(hilbert.Peano)
+	case "morton":
+		c = new(hilbert.Morton)
+	case "moore":
+		c = new(hilbert.Moore)
+	case "gray":
+		c = new(hilbert.Gray)
+	default:
+		return errors.New("unknown curve, want hilbert, peano, morton, moore or gray")
+	}
+	if err := c.UnmarshalText([]byte(text)); err != nil {
+		return err
+	}
+	f.curve = c
+	return nil
+}
+
+// curveVar defines the -curve flag on fs, defaulting to a 256x256 Hilbert curve.
+func curveVar(fs *flag.FlagSet) *curveFlag {
+	f := &curveFlag{}
+	f.curve, _ = hilbert.NewHilbert(256, false)
+	fs.Var(f, "curve", "the curve, such as \"hilbert 16 vertical\", \"peano 27\", \"morton 8\", \"moore 8\" or \"gray 8\"")
+	return f
+}
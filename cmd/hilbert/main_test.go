@@ -0,0 +1,133 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		args  []string
+		stdin string
+		want  string
+	}{
+		{[]string{"map", "-curve", "hilbert 4", "0", "1", "15"}, "", "0,0\n1,0\n3,0\n"},
+		{[]string{"map", "-curve", "hilbert 4 vertical"}, "t\n1\n", "x,y\n0,1\n"},
+		{[]string{"map", "-curve", "peano 3", "-format", "ndjson"}, "{\"t\":4}\n", "{\"t\":4,\"x\":1,\"y\":1}\n"},
+		{[]string{"inverse", "-curve", "hilbert 4", "3,0", "0,1,a"}, "", "15\n3,a\n"},
+		{[]string{"inverse", "-curve", "morton 4"}, "1,1\n", "3\n"},
+		{[]string{"map", "-curve", "gray 4", "2"}, "", "1,1\n"},
+		{[]string{"ranges", "-curve", "hilbert 4", "0", "0", "1", "1"}, "", "0,3\n"},
+		{[]string{"ranges", "-curve", "hilbert 4", "1", "0", "2", "0"}, "", "1,1\n14,14\n"},
+		{[]string{"ranges", "-curve", "hilbert 4", "-max", "1", "1", "0", "2", "0"}, "", "1,14\n"},
+		{[]string{"sort"}, "x,y,name\n3,0,d\n0,0,a\n1,1,c\n1,0,b\n", "x,y,name\n0,0,a\n1,0,b\n1,1,c\n3,0,d\n"},
+		{[]string{"sort", "-format", "ndjson"}, "{\"x\":1,\"y\":0}\n\n{\"y\":0,\"x\":0}\n", "{\"y\":0,\"x\":0}\n{\"x\":1,\"y\":0}\n"},
+		{[]string{"render", "-curve", "hilbert 2", "-cell", "1", "-format", "svg"}, "", ""},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		if err := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); err != nil {
+			t.Errorf("run(%q) returned error: %s", tt.args, err)
+			continue
+		}
+		if tt.args[0] == "render" {
+			if !strings.HasPrefix(stdout.String(), "<svg") {
+				t.Errorf("run(%q) = %q want an SVG image", tt.args, stdout.String())
+			}
+			continue
+		}
+		if got := stdout.String(); got != tt.want {
+			t.Errorf("run(%q) = %q want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunRenderPNG(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "curve.png")
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"render", "-curve", "moore 4", "-cell", "3", "-o", path}, nil, &stdout, &stderr); err != nil {
+		t.Fatalf("run(render) returned error: %s", err)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("run(render) wrote %d bytes to stdout want 0", stdout.Len())
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open image: %s", err)
+	}
+	defer f.Close()
+	img, err := png.Decode(f)
+	if err != nil {
+		t.Fatalf("Failed to decode image: %s", err)
+	}
+	if got := img.Bounds().Size(); got.X != 12 || got.Y != 12 {
+		t.Errorf("run(render) image size = %v want (12,12)", got)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		args  []string
+		stdin string
+		usage bool
+	}{
+		{nil, "", true},
+		{[]string{"unknown"}, "", true},
+		{[]string{"map", "-unknown"}, "", true},
+		{[]string{"map", "-curve", "spiral 4"}, "", true},
+		{[]string{"map", "-curve", "hilbert 5"}, "", true},
+		{[]string{"map", "-curve", "hilbert 4", "16"}, "", false},
+		{[]string{"map", "-format", "xml", "0"}, "", false},
+		{[]string{"inverse", "-curve", "hilbert 4", "0,0", "a,b"}, "", false},
+		{[]string{"ranges", "0", "0", "1"}, "", true},
+		{[]string{"ranges", "0", "0", "1", "y"}, "", false},
+		{[]string{"ranges", "-curve", "peano 3", "0", "0", "1", "1"}, "", false},
+		{[]string{"ranges", "-curve", "hilbert 4", "0", "0", "4", "4"}, "", false},
+		{[]string{"render", "extra"}, "", true},
+		{[]string{"render", "-cell", "0"}, "", false},
+		{[]string{"render", "-format", "gif"}, "", false},
+		{[]string{"sort"}, "x,y\n1,1\nfoo,1\n", false},
+		{[]string{"sort", "-format", "ndjson"}, "{\"x\":1}\n", false},
+	}
+	for _, tt := range tests {
+		var stdout, stderr bytes.Buffer
+		err := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+		if err == nil {
+			t.Errorf("run(%q) returned no error", tt.args)
+			continue
+		}
+		if usage := err == errUsage; usage != tt.usage {
+			t.Errorf("run(%q) = %v, usage error %t want %t", tt.args, err, usage, tt.usage)
+		}
+		if tt.usage && stderr.Len() == 0 {
+			t.Errorf("run(%q) printed no usage", tt.args)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := run([]string{"map", "-h"}, nil, &stdout, &stderr); err != nil {
+		t.Errorf("run(map -h) = %v want <nil>", err)
+	}
+	if !strings.Contains(stderr.String(), "-curve") {
+		t.Errorf("run(map -h) printed %q want the flags", stderr.String())
+	}
+}
@@ -14,16 +14,62 @@
 
 package hilbert
 
-import "errors"
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"strconv"
+)
 
 // Errors returned when validating input.
 var (
-	ErrNotPositive     = errors.New("N must be greater than zero")
-	ErrNotPowerOfTwo   = errors.New("N must be a power of two")
-	ErrNotPowerOfThree = errors.New("N must be a power of three")
-	ErrOutOfRange      = errors.New("value is out of range")
+	ErrNotPositive      = errors.New("N must be greater than zero")
+	ErrNotPowerOfTwo    = errors.New("N must be a power of two")
+	ErrNotPowerOfThree  = errors.New("N must be a power of three")
+	ErrOutOfRange       = errors.New("value is out of range")
+	ErrInvalidLength    = errors.New("length does not match the order of the curve")
+	ErrNegativeOrder    = errors.New("order must not be negative")
+	ErrNotFinite        = errors.New("value is not finite")
+	ErrTooLarge         = errors.New("space is too large")
+	ErrBadTables        = errors.New("lookup tables are invalid")
+	ErrConfigMismatch   = errors.New("configuration does not match the curve")
+	ErrInvalidConfig    = errors.New("curve configuration is invalid")
+	ErrDimensionsDiffer = errors.New("dimensions of the curves differ")
+	ErrNotContinuous    = errors.New("consecutive cells on the curve are not adjacent")
+	ErrNotSquare        = errors.New("length is not a perfect square")
+	ErrNotSimple        = errors.New("polygon edges intersect")
+	ErrOrderTooSmall    = errors.New("order of the curve is too small")
 )
 
+// RangeError records a value which is outside of its valid range, [Min, Max]. It is returned,
+// possibly within an *IndexError, by the range and batch methods, and wraps ErrOutOfRange, so
+// errors.Is(err, ErrOutOfRange) works and errors.As retrieves the details.
+type RangeError struct {
+	Name     string // The name of the argument, such as "t" or "x0"
+	Value    int
+	Min, Max int
+}
+
+func (e *RangeError) Error() string {
+	return e.Name + " = " + strconv.Itoa(e.Value) + " is not within [" + strconv.Itoa(e.Min) + ", " +
+		strconv.Itoa(e.Max) + "]"
+}
+
+// Unwrap returns ErrOutOfRange.
+func (e *RangeError) Unwrap() error {
+	return ErrOutOfRange
+}
+
+// mulChecked returns a*b for non-negative a and b, with false if the product does not fit in an
+// int.
+func mulChecked(a, b int) (int, bool) {
+	hi, lo := bits.Mul(uint(a), uint(b))
+	if hi != 0 || lo > math.MaxInt {
+		return 0, false
+	}
+	return int(lo), true
+}
+
 // SpaceFilling represents a space-filling curve that can map points from one dimensions to two.
 type SpaceFilling interface {
 	// Map transforms a one dimension value, t, in the range [0, n^2-1] to coordinates on the
@@ -37,6 +83,23 @@ type SpaceFilling interface {
 	GetDimensions() (x, y int)
 }
 
+// The curves in this package are all usable through SpaceFilling.
+var (
+	_ SpaceFilling = (*Hilbert)(nil)
+	_ SpaceFilling = (*Peano)(nil)
+	_ SpaceFilling = (*Morton)(nil)
+	_ SpaceFilling = (*Gray)(nil)
+	_ SpaceFilling = (*Moore)(nil)
+	_ SpaceFilling = (*CompactHilbert)(nil)
+	_ SpaceFilling = (*Generalized)(nil)
+	_ SpaceFilling = (*Tiled)(nil)
+	_ SpaceFilling = (*Stack)(nil)
+	_ SpaceFilling = (*Table)(nil)
+	_ SpaceFilling = (*CachedCurve)(nil)
+	_ SpaceFilling = (*Window)(nil)
+	_ SpaceFilling = (*PermFile)(nil)
+)
+
 func b2i(b bool) int {
 	if b {
 		return 1
@@ -0,0 +1,162 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/bits"
+
+// CompactHilbert represents a Hilbert curve over a rectangle whose sides are powers of two, but
+// may differ, using Hamilton's compact Hilbert index from "Compact Hilbert Indices" (2006). The
+// values on the curve are exactly [0, W*H-1], with no padding, and the cells are in the same
+// order as on the horizontal Hilbert curve over the smallest square containing the rectangle,
+// with the rectangle in its top left corner. Unless W and H are equal, the curve has jumps
+// between cells which are not adjacent. Implements SpaceFilling interface.
+type CompactHilbert struct {
+	W, H int // The width and height of the space, both powers of two.
+
+	m [2]int // Bits in each coordinate, x then y
+}
+
+// NewCompactHilbert returns a CompactHilbert space of width w and height h, which must both be
+// powers of two. ErrTooLarge is returned if w*h would not fit in an int.
+func NewCompactHilbert(w, h int) (*CompactHilbert, error) {
+	if w <= 0 || h <= 0 {
+		return nil, ErrNotPositive
+	}
+	if w&(w-1) != 0 || h&(h-1) != 0 {
+		return nil, ErrNotPowerOfTwo
+	}
+
+	mx, my := bits.TrailingZeros(uint(w)), bits.TrailingZeros(uint(h))
+	if mx+my >= bits.UintSize-1 {
+		return nil, ErrTooLarge
+	}
+	return &CompactHilbert{W: w, H: h, m: [2]int{mx, my}}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *CompactHilbert) GetDimensions() (int, int) {
+	return s.W, s.H
+}
+
+// The helpers below follow Hamilton's notation, for n = 2 dimensions, where bit j of each
+// two-bit value refers to dimension j.
+
+// rotr2 rotates the two-bit value b right by k.
+func rotr2(b, k int) int {
+	if k&1 == 1 {
+		return (b>>1 | b<<1) & 3
+	}
+	return b
+}
+
+// compactEntry returns e(w), the entry point of the w'th sub-cube.
+func compactEntry(w int) int {
+	if w == 0 {
+		return 0
+	}
+	g := 2 * ((w - 1) / 2)
+	return g ^ g>>1
+}
+
+// compactDirection returns d(w), the intra sub-cube direction of the w'th sub-cube.
+func compactDirection(w int) int {
+	switch {
+	case w == 0:
+		return 0
+	case w&1 == 0:
+		return bits.TrailingZeros(^uint(w-1)) % 2
+	}
+	return bits.TrailingZeros(^uint(w)) % 2
+}
+
+// mask returns the dimensions which still have bits at level i.
+func (s *CompactHilbert) mask(i int) int {
+	mu := 0
+	for j, m := range s.m {
+		if m > i {
+			mu |= 1 << uint(j)
+		}
+	}
+	return mu
+}
+
+// MapInverse transform coordinates on the curve from (x,y) to t.
+func (s *CompactHilbert) MapInverse(x, y int) (t int, err error) {
+	if x < 0 || x >= s.W || y < 0 || y >= s.H {
+		return -1, ErrOutOfRange
+	}
+
+	e, d := 0, 0
+	for i := max(s.m[0], s.m[1]) - 1; i >= 0; i-- {
+		mu := rotr2(s.mask(i), d+1)
+		l := (y>>uint(i)&1)<<1 | x>>uint(i)&1
+		w := rotr2(l^e, d+1)
+		w ^= w >> 1 // Inverse Gray code of two bits
+
+		// Only the free bits of w, those of dimensions which still have bits, are kept.
+		for k := 1; k >= 0; k-- {
+			if mu>>uint(k)&1 == 1 {
+				t = t<<1 | w>>uint(k)&1
+			}
+		}
+
+		e ^= rotr2(compactEntry(w), 1-d) // rotl by d+1
+		d = (d + compactDirection(w) + 1) % 2
+	}
+	return t, nil
+}
+
+// Map transforms a one dimension value, t, in the range [0, W*H-1] to coordinates on the curve in
+// the two-dimension space, where x is within [0,W-1] and y is within [0,H-1].
+func (s *CompactHilbert) Map(t int) (x, y int, err error) {
+	if t < 0 || t >= s.W*s.H {
+		return -1, -1, ErrOutOfRange
+	}
+
+	e, d := 0, 0
+	shift := s.m[0] + s.m[1]
+	for i := max(s.m[0], s.m[1]) - 1; i >= 0; i-- {
+		mu := rotr2(s.mask(i), d+1)
+		pi := rotr2(e, d+1) &^ mu
+		free := bits.OnesCount(uint(mu))
+		shift -= free
+		r := t >> uint(shift) & (1<<uint(free) - 1)
+
+		// Rebuild w and its Gray code from the free bits in r and the fixed bits in pi.
+		w, g, prev := 0, 0, 0
+		for k, j := 1, free-1; k >= 0; k-- {
+			var wk, gk int
+			if mu>>uint(k)&1 == 1 {
+				wk = r >> uint(j) & 1
+				gk = wk ^ prev
+				j--
+			} else {
+				gk = pi >> uint(k) & 1
+				wk = gk ^ prev
+			}
+			w |= wk << uint(k)
+			g |= gk << uint(k)
+			prev = wk
+		}
+
+		l := rotr2(g, 1-d) ^ e // Inverse of the transform in MapInverse
+		x |= (l & 1) << uint(i)
+		y |= (l >> 1) << uint(i)
+
+		e ^= rotr2(compactEntry(w), 1-d)
+		d = (d + compactDirection(w) + 1) % 2
+	}
+	return x, y, nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestNewCompactHilbertErrors(t *testing.T) {
+	testCases := []struct {
+		w, h int
+		want error
+	}{
+		{0, 4, ErrNotPositive},
+		{4, -1, ErrNotPositive},
+		{3, 4, ErrNotPowerOfTwo},
+		{4, 6, ErrNotPowerOfTwo},
+		{1 << (bits.UintSize / 2), 1 << (bits.UintSize / 2), ErrTooLarge},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewCompactHilbert(tc.w, tc.h)
+		if s != nil || err != tc.want {
+			t.Errorf("NewCompactHilbert(%d, %d) = (%+v, %q) did not fail want (?, %q)", tc.w, tc.h, s, err, tc.want)
+		}
+	}
+}
+
+func TestCompactHilbert(t *testing.T) {
+	for _, size := range [][2]int{{1, 1}, {2, 1}, {1, 4}, {4, 4}, {1024, 64}, {8, 2}, {2, 8}, {16, 4}, {4, 32}} {
+		w, h := size[0], size[1]
+		s, err := NewCompactHilbert(w, h)
+		if err != nil {
+			t.Fatalf("NewCompactHilbert(%d, %d) failed: %s", w, h, err)
+		}
+
+		// The cells are visited in the same order as the window into the Hilbert curve.
+		window, _ := NewHilbertRect(w, h)
+		d := 0
+		for x, y, next, ok := window.NextValid(0); ok; x, y, next, ok = window.NextValid(next) {
+			gotX, gotY, err := s.Map(d)
+			if err != nil || gotX != x || gotY != y {
+				t.Fatalf("%dx%d Map(%d) = (%d, %d, %v) want (%d, %d, nil)", w, h, d, gotX, gotY, err, x, y)
+			}
+			if got, err := s.MapInverse(x, y); err != nil || got != d {
+				t.Fatalf("%dx%d MapInverse(%d, %d) = (%d, %v) want (%d, nil)", w, h, x, y, got, err, d)
+			}
+			d++
+		}
+		if d != w*h {
+			t.Errorf("%dx%d visited %d cells want %d", w, h, d, w*h)
+		}
+
+		if _, _, err := s.Map(w * h); err != ErrOutOfRange {
+			t.Errorf("%dx%d Map(%d) = %v want %v", w, h, w*h, err, ErrOutOfRange)
+		}
+		if _, err := s.MapInverse(w, 0); err != ErrOutOfRange {
+			t.Errorf("%dx%d MapInverse(%d, 0) = %v want %v", w, h, w, err, ErrOutOfRange)
+		}
+		if _, err := s.MapInverse(0, h); err != ErrOutOfRange {
+			t.Errorf("%dx%d MapInverse(0, %d) = %v want %v", w, h, h, err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestCompactHilbertSquare(t *testing.T) {
+	s, _ := NewCompactHilbert(32, 32)
+	h, _ := NewHilbert(32, false)
+	if JumpCount(s) != 0 {
+		t.Errorf("JumpCount(%s) = %d want 0", DescribeCurve(s), JumpCount(s))
+	}
+	for d := 0; d < 32*32; d++ {
+		x, y, _ := s.Map(d)
+		wantX, wantY, _ := h.Map(d)
+		if x != wantX || y != wantY {
+			t.Errorf("Map(%d) = (%d, %d) want (%d, %d)", d, x, y, wantX, wantY)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Less returns true if the cell (x0,y0) comes before the cell (x1,y1) along the curve, that is
+// MapInverse(x0, y0) < MapInverse(x1, y1). It is intended for use in sort comparators. An error is
+// returned if either cell is outside of the space.
+func (s *Hilbert) Less(x0, y0, x1, y1 int) (bool, error) {
+	t0, err := s.MapInverse(x0, y0)
+	if err != nil {
+		return false, err
+	}
+	t1, err := s.MapInverse(x1, y1)
+	if err != nil {
+		return false, err
+	}
+	return t0 < t1, nil
+}
+
+// Compare returns -1 if the cell (x0,y0) comes before the cell (x1,y1) along the curve, and +1 if
+// it comes after. Coordinates outside of the space which the BoundsPolicy moves onto the same
+// cell have equal values on the curve, so for a total order those are compared by x and then by
+// y. The result is therefore 0 only when the coordinates are identical, not just their values on
+// the curve, which suits stable sorts mixing cells with external data. An error is returned if
+// either cell is outside of the space.
+func (s *Hilbert) Compare(x0, y0, x1, y1 int) (int, error) {
+	t0, err := s.MapInverse(x0, y0)
+	if err != nil {
+		return 0, err
+	}
+	t1, err := s.MapInverse(x1, y1)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case t0 < t1:
+		return -1, nil
+	case t0 > t1:
+		return 1, nil
+	case x0 != x1:
+		return compareInts(x0, x1), nil
+	}
+	return compareInts(y0, y1), nil
+}
+
+// compareInts returns -1, 0 or +1 as a is less than, equal to or greater than b.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,144 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestLess(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		x0, y0, x1, y1 int
+		want           bool
+		err            error
+	}{
+		{0, 0, 0, 1, true, nil},
+		{0, 1, 0, 0, false, nil},
+		{3, 3, 3, 3, false, nil},
+		{15, 0, 0, 0, false, nil},
+		{-1, 0, 0, 0, false, ErrOutOfRange},
+		{0, 0, 0, 16, false, ErrOutOfRange},
+	}
+
+	for _, tc := range testCases {
+		got, err := s.Less(tc.x0, tc.y0, tc.x1, tc.y1)
+		if got != tc.want || err != tc.err {
+			t.Errorf("Less(%d, %d, %d, %d) = (%t, %v) want (%t, %v)", tc.x0, tc.y0, tc.x1, tc.y1, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestLessSort(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	s, err := NewHilbert(32, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	points := make([][2]int, 200)
+	for i := range points {
+		points[i] = [2]int{r.Intn(s.N), r.Intn(s.N)}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		less, _ := s.Less(points[i][0], points[i][1], points[j][0], points[j][1])
+		return less
+	})
+
+	last := -1
+	for _, p := range points {
+		d, _ := s.MapInverse(p[0], p[1])
+		if d < last {
+			t.Errorf("sorted point %v at %d is before %d", p, d, last)
+		}
+		last = d
+	}
+}
+
+func TestCompare(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	c, err := NewHilbert(16, false, WithBoundsPolicy(BoundsClamp))
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		s              *Hilbert
+		x0, y0, x1, y1 int
+		want           int
+		err            error
+	}{
+		{s, 0, 0, 0, 1, -1, nil},
+		{s, 0, 1, 0, 0, 1, nil},
+		{s, 3, 3, 3, 3, 0, nil},
+		{s, 15, 0, 0, 0, 1, nil},
+		{s, -1, 0, 0, 0, 0, ErrOutOfRange},
+		{s, 0, 0, 0, 16, 0, ErrOutOfRange},
+
+		// Clamped onto the same cell, so ordered by the coordinates.
+		{c, -1, 0, 0, 0, -1, nil},
+		{c, 0, 0, -1, 0, 1, nil},
+		{c, 0, 16, 0, 15, 1, nil},
+		{c, 0, 20, 0, 16, 1, nil},
+		{c, -2, 0, -1, 0, -1, nil},
+		{c, -5, -5, -5, -5, 0, nil},
+	}
+
+	for _, tc := range testCases {
+		got, err := tc.s.Compare(tc.x0, tc.y0, tc.x1, tc.y1)
+		if got != tc.want || err != tc.err {
+			t.Errorf("Compare(%d, %d, %d, %d) = (%d, %v) want (%d, %v)", tc.x0, tc.y0, tc.x1, tc.y1, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestCompareSortStable(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	s, err := NewHilbert(8, false, WithBoundsPolicy(BoundsWrap))
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	// Wrapped coordinates give many points with the same value on the curve.
+	points := make([][2]int, 200)
+	for i := range points {
+		points[i] = [2]int{r.Intn(32) - 16, r.Intn(32) - 16}
+	}
+	sort.Slice(points, func(i, j int) bool {
+		c, _ := s.Compare(points[i][0], points[i][1], points[j][0], points[j][1])
+		return c < 0
+	})
+
+	for i := 1; i < len(points); i++ {
+		p, q := points[i-1], points[i]
+		if c, _ := s.Compare(p[0], p[1], q[0], q[1]); c > 0 {
+			t.Errorf("sorted point %v is after %v", p, q)
+		}
+		if c, _ := s.Compare(q[0], q[1], p[0], p[1]); c == 0 && p != q {
+			t.Errorf("Compare(%v, %v) = 0 for different coordinates", q, p)
+		}
+	}
+}
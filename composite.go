@@ -0,0 +1,126 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// maxCompositeOrder is the largest order of each level of a Composite, as the values of each
+// level are stored in a uint64.
+const maxCompositeOrder = 32
+
+// Composite represents a two level Hilbert curve, for spaces too large for a single int. The space
+// is divided into a coarse Hilbert curve of blocks, and each block into a fine Hilbert curve of
+// cells. All blocks use the same fine curve, so unlike a single curve of the combined order the
+// curve jumps between blocks, but locality is kept within and between blocks.
+type Composite struct {
+	coarseOrder, fineOrder int
+}
+
+// CompositeIndex is the position of a cell on a Composite curve, being the value of its block on
+// the coarse curve, and its value within the block on the fine curve.
+type CompositeIndex struct {
+	Coarse, Fine uint64
+}
+
+// NewComposite returns a new Composite curve, whose coarse curve is 2^coarseOrder blocks wide, and
+// whose fine curve is 2^fineOrder cells wide, so the space is 2^(coarseOrder+fineOrder) cells
+// wide. Each order must be at most 32.
+func NewComposite(coarseOrder, fineOrder int) (*Composite, error) {
+	if coarseOrder < 0 || fineOrder < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if coarseOrder > maxCompositeOrder || fineOrder > maxCompositeOrder {
+		return nil, ErrTooLarge
+	}
+	return &Composite{
+		coarseOrder: coarseOrder,
+		fineOrder:   fineOrder,
+	}, nil
+}
+
+// Orders returns the orders of the coarse and fine curves.
+func (c *Composite) Orders() (coarse, fine int) {
+	return c.coarseOrder, c.fineOrder
+}
+
+// inLevel returns true if v is a valid value on a curve of the given order.
+func inLevel(v uint64, order int) bool {
+	return order == maxCompositeOrder || v < 1<<uint(2*order)
+}
+
+// Map transforms the position i to the coordinates of its cell in the space.
+func (c *Composite) Map(i CompositeIndex) (x, y uint64, err error) {
+	if !inLevel(i.Coarse, c.coarseOrder) || !inLevel(i.Fine, c.fineOrder) {
+		return 0, 0, ErrOutOfRange
+	}
+
+	bx, by := Decode2D(i.Coarse, c.coarseOrder)
+	fx, fy := Decode2D(i.Fine, c.fineOrder)
+	return uint64(bx)<<uint(c.fineOrder) | uint64(fx), uint64(by)<<uint(c.fineOrder) | uint64(fy), nil
+}
+
+// MapInverse transforms the coordinates of a cell, (x,y), to its position on the curve.
+func (c *Composite) MapInverse(x, y uint64) (CompositeIndex, error) {
+	if order := c.coarseOrder + c.fineOrder; order < 64 && (x>>uint(order) != 0 || y>>uint(order) != 0) {
+		return CompositeIndex{}, ErrOutOfRange
+	}
+
+	mask := uint64(1)<<uint(c.fineOrder) - 1
+	return CompositeIndex{
+		Coarse: Encode2D(uint32(x>>uint(c.fineOrder)), uint32(y>>uint(c.fineOrder)), c.coarseOrder),
+		Fine:   Encode2D(uint32(x&mask), uint32(y&mask), c.fineOrder),
+	}, nil
+}
+
+// levelBytes returns the number of bytes used by a level of the given order in a key.
+func levelBytes(order int) int {
+	return (2*order + 7) / 8
+}
+
+// Key returns a single key for the position i, made up of the coarse value stored big-endian in
+// ceil(2*coarseOrder/8) bytes, followed by the fine value stored big-endian in
+// ceil(2*fineOrder/8) bytes. As all keys for a curve have the same length, comparing keys byte by
+// byte orders them along the curve.
+func (c *Composite) Key(i CompositeIndex) []byte {
+	nc, nf := levelBytes(c.coarseOrder), levelBytes(c.fineOrder)
+	key := make([]byte, nc+nf)
+	for j := nc - 1; j >= 0; j-- {
+		key[j] = byte(i.Coarse)
+		i.Coarse >>= 8
+	}
+	for j := nc + nf - 1; j >= nc; j-- {
+		key[j] = byte(i.Fine)
+		i.Fine >>= 8
+	}
+	return key
+}
+
+// FromKey is the inverse of Key, returning the position stored in the key.
+func (c *Composite) FromKey(key []byte) (CompositeIndex, error) {
+	nc, nf := levelBytes(c.coarseOrder), levelBytes(c.fineOrder)
+	if len(key) != nc+nf {
+		return CompositeIndex{}, ErrInvalidLength
+	}
+
+	var i CompositeIndex
+	for _, b := range key[:nc] {
+		i.Coarse = i.Coarse<<8 | uint64(b)
+	}
+	for _, b := range key[nc:] {
+		i.Fine = i.Fine<<8 | uint64(b)
+	}
+	if !inLevel(i.Coarse, c.coarseOrder) || !inLevel(i.Fine, c.fineOrder) {
+		return CompositeIndex{}, ErrOutOfRange
+	}
+	return i, nil
+}
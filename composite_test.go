@@ -0,0 +1,115 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestComposite(t *testing.T) {
+	c, err := NewComposite(2, 3)
+	if err != nil {
+		t.Fatalf("NewComposite(2, 3) failed: %s", err)
+	}
+	coarse, _ := NewHilbert(4, false)
+	fine, _ := NewHilbert(8, false)
+
+	var lastKey []byte
+	for bt := 0; bt < 16; bt++ {
+		bx, by, _ := coarse.Map(bt)
+		for ft := 0; ft < 64; ft++ {
+			fx, fy, _ := fine.Map(ft)
+			i := CompositeIndex{uint64(bt), uint64(ft)}
+			wantX, wantY := uint64(bx*8+fx), uint64(by*8+fy)
+
+			x, y, err := c.Map(i)
+			if err != nil || x != wantX || y != wantY {
+				t.Errorf("Map(%v) = (%d, %d, %v) want (%d, %d, nil)", i, x, y, err, wantX, wantY)
+			}
+			if got, err := c.MapInverse(x, y); err != nil || got != i {
+				t.Errorf("MapInverse(%d, %d) = (%v, %v) want (%v, nil)", x, y, got, err, i)
+			}
+
+			key := c.Key(i)
+			if len(key) != 2 {
+				t.Errorf("len(Key(%v)) = %d want 2", i, len(key))
+			}
+			if lastKey != nil && bytes.Compare(lastKey, key) >= 0 {
+				t.Errorf("Key(%v) = %v is not after %v", i, key, lastKey)
+			}
+			lastKey = key
+			if got, err := c.FromKey(key); err != nil || got != i {
+				t.Errorf("FromKey(%v) = (%v, %v) want (%v, nil)", key, got, err, i)
+			}
+		}
+	}
+}
+
+func TestCompositeLarge(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	c, err := NewComposite(32, 32)
+	if err != nil {
+		t.Fatalf("NewComposite(32, 32) failed: %s", err)
+	}
+	for i := 0; i < 100; i++ {
+		x, y := r.Uint64(), r.Uint64()
+		idx, err := c.MapInverse(x, y)
+		if err != nil {
+			t.Errorf("MapInverse(%d, %d) failed: %s", x, y, err)
+			continue
+		}
+		if gotX, gotY, err := c.Map(idx); err != nil || gotX != x || gotY != y {
+			t.Errorf("Map(%v) = (%d, %d, %v) want (%d, %d, nil)", idx, gotX, gotY, err, x, y)
+		}
+		if got, _ := c.FromKey(c.Key(idx)); got != idx {
+			t.Errorf("FromKey(Key(%v)) = %v", idx, got)
+		}
+	}
+}
+
+func TestCompositeErrors(t *testing.T) {
+	for _, orders := range [][2]int{{-1, 0}, {0, -1}, {33, 0}, {0, 33}} {
+		want := ErrTooLarge
+		if orders[0] < 0 || orders[1] < 0 {
+			want = ErrNegativeOrder
+		}
+		if _, err := NewComposite(orders[0], orders[1]); err != want {
+			t.Errorf("NewComposite(%d, %d) = %v want %v", orders[0], orders[1], err, want)
+		}
+	}
+
+	c, err := NewComposite(2, 3)
+	if err != nil {
+		t.Fatalf("NewComposite(2, 3) failed: %s", err)
+	}
+	if _, _, err := c.Map(CompositeIndex{16, 0}); err != ErrOutOfRange {
+		t.Errorf("Map({16, 0}) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, _, err := c.Map(CompositeIndex{0, 64}); err != ErrOutOfRange {
+		t.Errorf("Map({0, 64}) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := c.MapInverse(32, 0); err != ErrOutOfRange {
+		t.Errorf("MapInverse(32, 0) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := c.FromKey([]byte{0}); err != ErrInvalidLength {
+		t.Errorf("FromKey([0]) = %v want %v", err, ErrInvalidLength)
+	}
+	if _, err := c.FromKey([]byte{0, 64}); err != ErrOutOfRange {
+		t.Errorf("FromKey([0, 64]) = %v want %v", err, ErrOutOfRange)
+	}
+}
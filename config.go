@@ -0,0 +1,62 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Config is the configuration of a Hilbert curve, as returned by Hilbert.Config, from which the
+// same curve can be created again with NewHilbertFromConfig. It is comparable, so can be used as
+// a map key, and curves with equal configurations map identically.
+type Config struct {
+	// N is the width and height of the space.
+	N int
+
+	// VerticalCompatible is the verticalCompatible argument given to NewHilbert.
+	VerticalCompatible bool
+
+	// Transform is the transform of the layout chosen by VerticalCompatible, as given to
+	// WithTransform, with Reversed set if the curve is reversed.
+	Transform Transform
+
+	// Bounds is the policy set by WithBoundsPolicy.
+	Bounds BoundsPolicy
+}
+
+// Config returns the configuration of the curve. The ProgressFunc set by WithProgress, and any
+// lookup tables, are not included.
+func (s *Hilbert) Config() Config {
+	c := Config{N: s.N, VerticalCompatible: s.verticalCompatible, Bounds: s.bounds}
+	c.Transform.Reversed = s.reversed
+	for _, mirror := range []bool{false, true} {
+		for rotation := 0; rotation < 4; rotation++ {
+			layout := Hilbert{startState: uint8(b2i(s.verticalCompatible))}
+			WithTransform(Transform{Mirror: mirror, Rotation: rotation})(&layout)
+			if layout.startState == s.startState && layout.mirrored == s.mirrored {
+				c.Transform.Mirror, c.Transform.Rotation = mirror, rotation
+				return c
+			}
+		}
+	}
+	return c
+}
+
+// NewHilbertFromConfig returns a new Hilbert curve with the configuration c, returning the same
+// errors as NewHilbert, or ErrOutOfRange if the rotation of the transform is not in [0, 3].
+// Further options, such as WithProgress, may be given in opts.
+func NewHilbertFromConfig(c Config, opts ...Option) (*Hilbert, error) {
+	if c.Transform.Rotation < 0 || c.Transform.Rotation > 3 {
+		return nil, ErrOutOfRange
+	}
+	opts = append([]Option{WithTransform(c.Transform), WithBoundsPolicy(c.Bounds)}, opts...)
+	return NewHilbert(c.N, c.VerticalCompatible, opts...)
+}
@@ -0,0 +1,67 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestConfig(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(8, true, WithBoundsPolicy(BoundsWrap))
+	rotated, _ := NewHilbert(4, true, WithTransform(Transform{Mirror: true, Rotation: 1, Reversed: true}))
+
+	testCases := []struct {
+		s    *Hilbert
+		want Config
+	}{
+		{h, Config{N: 16}},
+		{h.Reversed(), Config{N: 16, Transform: Transform{Reversed: true}}},
+		{v, Config{N: 8, VerticalCompatible: true, Bounds: BoundsWrap}},
+		{rotated, Config{N: 4, VerticalCompatible: true, Transform: Transform{Mirror: true, Rotation: 1, Reversed: true}}},
+	}
+
+	for _, tc := range testCases {
+		got := tc.s.Config()
+		if got != tc.want {
+			t.Errorf("%s Config() = %+v want %+v", DescribeCurve(tc.s), got, tc.want)
+		}
+
+		s, err := NewHilbertFromConfig(got)
+		if err != nil {
+			t.Fatalf("NewHilbertFromConfig(%+v) returned error: %s", got, err)
+		}
+		if !tc.s.transformedBy(s, Transform{}, tc.s.N*tc.s.N-1) || s.Config() != got {
+			t.Errorf("NewHilbertFromConfig(%+v) = %s want %s", got, DescribeCurve(s), DescribeCurve(tc.s))
+		}
+	}
+}
+
+func TestNewHilbertFromConfigErrors(t *testing.T) {
+	testCases := []struct {
+		c    Config
+		want error
+	}{
+		{Config{N: 0}, ErrNotPositive},
+		{Config{N: 12}, ErrNotPowerOfTwo},
+		{Config{N: 4, Transform: Transform{Rotation: 4}}, ErrOutOfRange},
+		{Config{N: 4, Transform: Transform{Rotation: -1}}, ErrOutOfRange},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbertFromConfig(tc.c)
+		if s != nil || err != tc.want {
+			t.Errorf("NewHilbertFromConfig(%+v) = (%+v, %q) did not fail want (?, %q)", tc.c, s, err, tc.want)
+		}
+	}
+}
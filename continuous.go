@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Continuous maps between the unit interval and the unit square along a Hilbert curve, for
+// working with continuous values instead of cells. It approximates the limit of the curve with a
+// horizontal curve of the given precision, dividing the square into 2^precision by 2^precision
+// cells.
+type Continuous struct {
+	precision int
+	n         float64 // 2^precision
+}
+
+// NewContinuous returns a new Continuous with the given precision, in bits per coordinate.
+// ErrNegativeOrder is returned if precision is negative, and ErrTooLarge if it is more than
+// MaxOrder64. Any precision above 26 is more than a float64 can represent for t.
+func NewContinuous(precision int) (*Continuous, error) {
+	if precision < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if precision > MaxOrder64 {
+		return nil, ErrTooLarge
+	}
+	return &Continuous{precision: precision, n: math.Ldexp(1, precision)}, nil
+}
+
+// Precision returns the number of bits per coordinate.
+func (c *Continuous) Precision() int {
+	return c.precision
+}
+
+// MapFloat returns the point on the curve for t in [0, 1), with x and y in [0, 1). The point is
+// the centre of the cell containing t. ErrOutOfRange is returned if t is not within [0, 1).
+func (c *Continuous) MapFloat(t float64) (x, y float64, err error) {
+	if !(t >= 0 && t < 1) {
+		return math.NaN(), math.NaN(), ErrOutOfRange
+	}
+
+	cx, cy := Decode2D(uint64(t*c.n*c.n), c.precision)
+	return (float64(cx) + 0.5) / c.n, (float64(cy) + 0.5) / c.n, nil
+}
+
+// MapInverseFloat returns the value in [0, 1) for the point (x,y), where x and y are within
+// [0, 1). The value is the start of the part of the interval within the cell containing the point,
+// so MapFloat returns the centre of the same cell. ErrOutOfRange is returned if the point is not
+// within the unit square.
+func (c *Continuous) MapInverseFloat(x, y float64) (t float64, err error) {
+	if !(x >= 0 && x < 1 && y >= 0 && y < 1) {
+		return math.NaN(), ErrOutOfRange
+	}
+
+	// Rounding may push values just below 1 up to n.
+	cx := uint32(math.Min(math.Floor(x*c.n), c.n-1))
+	cy := uint32(math.Min(math.Floor(y*c.n), c.n-1))
+	t = float64(Encode2D(cx, cy, c.precision)) / (c.n * c.n)
+	return math.Min(t, math.Nextafter(1, 0)), nil // Large values may round up to 1.
+}
@@ -0,0 +1,103 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContinuous(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	c, err := NewContinuous(4)
+	if err != nil {
+		t.Fatalf("NewContinuous(4) returned error: %s", err)
+	}
+
+	for d := 0; d < s.N*s.N; d++ {
+		x, y, _ := s.Map(d)
+		wantX, wantY := (float64(x)+0.5)/16, (float64(y)+0.5)/16
+
+		// Any t within the cell's part of the interval maps to its centre.
+		for _, f := range []float64{0, 0.5, 0.999} {
+			tf := (float64(d) + f) / 256
+			gotX, gotY, err := c.MapFloat(tf)
+			if err != nil || gotX != wantX || gotY != wantY {
+				t.Errorf("MapFloat(%f) = (%f, %f, %v) want (%f, %f, nil)", tf, gotX, gotY, err, wantX, wantY)
+			}
+		}
+
+		got, err := c.MapInverseFloat(wantX, wantY)
+		if want := float64(d) / 256; err != nil || got != want {
+			t.Errorf("MapInverseFloat(%f, %f) = (%f, %v) want (%f, nil)", wantX, wantY, got, err, want)
+		}
+	}
+}
+
+func TestContinuousPrecision(t *testing.T) {
+	for _, precision := range []int{0, 1, 10, 26, 27, MaxOrder64} {
+		c, err := NewContinuous(precision)
+		if err != nil {
+			t.Fatalf("NewContinuous(%d) returned error: %s", precision, err)
+		}
+		if got := c.Precision(); got != precision {
+			t.Errorf("Precision() = %d want %d", got, precision)
+		}
+
+		// Values close to 1 must stay within range.
+		last := math.Nextafter(1, 0)
+		x, y, err := c.MapFloat(last)
+		if err != nil || x < 0 || x >= 1 || y < 0 || y >= 1 {
+			t.Errorf("precision %d MapFloat(%v) = (%v, %v, %v) want a point within the unit square", precision, last, x, y, err)
+		}
+		if got, err := c.MapInverseFloat(last, last); err != nil || got < 0 || got >= 1 {
+			t.Errorf("precision %d MapInverseFloat(%v, %v) = (%v, %v) want a value within [0, 1)", precision, last, last, got, err)
+		}
+
+		// At precisions above 26, t cannot hold every cell, but nearby values must still round trip
+		// to nearby points.
+		for _, tf := range []float64{0, 0.1, 0.25, 0.7} {
+			x, y, _ := c.MapFloat(tf)
+			got, _ := c.MapInverseFloat(x, y)
+			if math.Abs(got-tf) > math.Ldexp(1, -2*precision)+1e-15 {
+				t.Errorf("precision %d MapInverseFloat(MapFloat(%v)) = %v", precision, tf, got)
+			}
+		}
+	}
+}
+
+func TestContinuousErrors(t *testing.T) {
+	if _, err := NewContinuous(-1); err != ErrNegativeOrder {
+		t.Errorf("NewContinuous(-1) = %v want %v", err, ErrNegativeOrder)
+	}
+	if _, err := NewContinuous(MaxOrder64 + 1); err != ErrTooLarge {
+		t.Errorf("NewContinuous(%d) = %v want %v", MaxOrder64+1, err, ErrTooLarge)
+	}
+
+	c, _ := NewContinuous(8)
+	for _, tf := range []float64{-0.1, 1, math.NaN(), math.Inf(1)} {
+		if _, _, err := c.MapFloat(tf); err != ErrOutOfRange {
+			t.Errorf("MapFloat(%v) = %v want %v", tf, err, ErrOutOfRange)
+		}
+	}
+	for _, p := range [][2]float64{{-0.1, 0}, {0, 1}, {math.NaN(), 0.5}} {
+		if _, err := c.MapInverseFloat(p[0], p[1]); err != ErrOutOfRange {
+			t.Errorf("MapInverseFloat(%v, %v) = %v want %v", p[0], p[1], err, ErrOutOfRange)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Region is a set of cells in the space of a curve, which can be covered with ranges on the curve
+// by a Coverer.
+type Region interface {
+	// ContainsCell returns true if the cell (x,y) is in the region.
+	ContainsCell(x, y int) bool
+}
+
+// RegionFunc is a Region given by a point-in-region predicate.
+type RegionFunc func(x, y int) bool
+
+// ContainsCell returns f(x, y).
+func (f RegionFunc) ContainsCell(x, y int) bool {
+	return f(x, y)
+}
+
+// MaskRegion returns a Region given by a rasterized mask, where mask[y][x] is true if the cell
+// (x,y) is in the region. Cells outside of the mask are not in the region.
+func MaskRegion(mask [][]bool) Region {
+	return RegionFunc(func(x, y int) bool {
+		return y < len(mask) && x < len(mask[y]) && mask[y][x]
+	})
+}
+
+// Coverer covers regions with ranges on a Hilbert curve, in the same way as the RegionCoverer of
+// the S2 geometry library. A covering is made of square sub-quadrants, where level 0 is the whole
+// space and each level splits the squares of the previous level into four, down to single cells
+// at level GetOrder(). Each square is a single range on the curve, and the covering always
+// includes every cell in the region, but may also include cells outside of it.
+type Coverer struct {
+	curve              *Hilbert
+	minLevel, maxLevel int
+	maxCells           int
+}
+
+// square is a sub-quadrant of the space at the given level, whose values on the curve are the
+// range [t, t+size*size-1], and whose cells are in the region if full is true.
+type square struct {
+	level, t, size int
+	full           bool
+}
+
+// NewCoverer returns a new Coverer for curve, which uses squares no larger than those at minLevel
+// and no smaller than those at maxLevel, and at most maxCells squares, unless more than that are
+// needed at minLevel. Fewer squares cover the region less precisely. ErrOutOfRange is returned if
+// the levels are not within [0, GetOrder()], or minLevel is more than maxLevel, and
+// ErrNotPositive if maxCells is less than one.
+func NewCoverer(curve *Hilbert, minLevel, maxLevel, maxCells int) (*Coverer, error) {
+	if minLevel < 0 || minLevel > maxLevel || maxLevel > curve.GetOrder() {
+		return nil, ErrOutOfRange
+	}
+	if maxCells < 1 {
+		return nil, ErrNotPositive
+	}
+
+	return &Coverer{
+		curve:    curve,
+		minLevel: minLevel,
+		maxLevel: maxLevel,
+		maxCells: maxCells,
+	}, nil
+}
+
+// Cover returns the sorted list of ranges on the curve covering r, with adjacent ranges merged.
+// The squares are refined from the largest down, and a square is only split into the quadrants
+// which contain part of r when that keeps the number of squares within maxCells. ContainsCell may
+// be called for every cell of the space, at each level.
+func (c *Coverer) Cover(r Region) []Range {
+	// Visiting the squares in a queue refines them a level at a time, so the largest squares are
+	// always split first.
+	var queue []square
+	size := c.curve.N >> c.minLevel
+	for t := 0; t < c.curve.N*c.curve.N; t += size * size {
+		if sq, ok := c.classify(r, c.minLevel, t, size); ok {
+			queue = append(queue, sq)
+		}
+	}
+
+	var covering []square
+	for len(queue) > 0 {
+		sq := queue[0]
+		queue = queue[1:]
+		if sq.full || sq.level == c.maxLevel {
+			covering = append(covering, sq)
+			continue
+		}
+
+		var children []square
+		childSize := sq.size / 2
+		for i := 0; i < 4; i++ {
+			if child, ok := c.classify(r, sq.level+1, sq.t+i*childSize*childSize, childSize); ok {
+				children = append(children, child)
+			}
+		}
+		if len(covering)+len(queue)+len(children) > c.maxCells {
+			covering = append(covering, sq)
+			continue
+		}
+		queue = append(queue, children...)
+	}
+
+	ranges := make([]Range, len(covering))
+	for i, sq := range covering {
+		ranges[i] = Range{sq.t, sq.t + sq.size*sq.size - 1}
+	}
+	return MergeRanges(ranges)
+}
+
+// classify returns the square of width size whose values on the curve start at t, and false if
+// none of its cells are in r.
+func (c *Coverer) classify(r Region, level, t, size int) (square, bool) {
+	// Sub-squares are always aligned to their size, so the corner can be found from any cell.
+	x0, y0, _ := c.curve.Map(t)
+	x0, y0 = x0/size*size, y0/size*size
+
+	count := 0
+	for y := y0; y < y0+size; y++ {
+		for x := x0; x < x0+size; x++ {
+			if r.ContainsCell(x, y) {
+				count++
+			}
+		}
+	}
+	return square{level: level, t: t, size: size, full: count == size*size}, count > 0
+}
@@ -0,0 +1,152 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+// coveredCells returns, indexed by [y][x], whether each cell of the space is in one of the ranges.
+func coveredCells(s *Hilbert, ranges []Range) [][]bool {
+	covered := make([][]bool, s.N)
+	for y := range covered {
+		covered[y] = make([]bool, s.N)
+	}
+	for _, r := range ranges {
+		for t := r.Lo; t <= r.Hi; t++ {
+			x, y, _ := s.Map(t)
+			covered[y][x] = true
+		}
+	}
+	return covered
+}
+
+func TestCoverer(t *testing.T) {
+	s, err := NewHilbert(32, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	disk := RegionFunc(func(x, y int) bool {
+		return (x-12)*(x-12)+(y-17)*(y-17) <= 81
+	})
+
+	for _, maxCells := range []int{1, 4, 8, 20, 100, 1 << 20} {
+		c, err := NewCoverer(s, 0, s.GetOrder(), maxCells)
+		if err != nil {
+			t.Fatalf("NewCoverer(0, %d, %d) returned error: %s", s.GetOrder(), maxCells, err)
+		}
+		ranges := c.Cover(disk)
+		if len(ranges) > maxCells {
+			t.Errorf("Cover() with maxCells=%d returned %d ranges", maxCells, len(ranges))
+		}
+
+		// Every cell of the disk must be covered, and with no limit, nothing else.
+		covered := coveredCells(s, ranges)
+		for y := 0; y < s.N; y++ {
+			for x := 0; x < s.N; x++ {
+				if disk(x, y) && !covered[y][x] {
+					t.Errorf("Cover() with maxCells=%d does not cover (%d, %d)", maxCells, x, y)
+				}
+				if maxCells == 1<<20 && !disk(x, y) && covered[y][x] {
+					t.Errorf("Cover() with maxCells=%d covers (%d, %d) outside of the disk", maxCells, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestCovererLevels(t *testing.T) {
+	s, err := NewHilbert(16, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	cell := RegionFunc(func(x, y int) bool { return x == 5 && y == 9 })
+	cellT, _ := s.MapInverse(5, 9)
+
+	testCases := []struct {
+		minLevel, maxLevel int
+		want               []Range
+	}{
+		{0, 4, []Range{{cellT, cellT}}},
+		{0, 0, []Range{{0, 255}}},
+		{0, 2, []Range{{cellT / 16 * 16, cellT/16*16 + 15}}},
+		{3, 3, []Range{{cellT / 4 * 4, cellT/4*4 + 3}}},
+	}
+	for _, tc := range testCases {
+		c, err := NewCoverer(s, tc.minLevel, tc.maxLevel, 8)
+		if err != nil {
+			t.Fatalf("NewCoverer(%d, %d, 8) returned error: %s", tc.minLevel, tc.maxLevel, err)
+		}
+		if got := c.Cover(cell); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("NewCoverer(%d, %d, 8).Cover((5, 9)) = %v want %v", tc.minLevel, tc.maxLevel, got, tc.want)
+		}
+	}
+
+	// Sixteen squares are needed at minLevel, more than maxCells, but they merge to one range.
+	c, _ := NewCoverer(s, 2, 4, 1)
+	if got := c.Cover(RegionFunc(func(x, y int) bool { return true })); !reflect.DeepEqual(got, []Range{{0, 255}}) {
+		t.Errorf("NewCoverer(2, 4, 1).Cover(everything) = %v want [{0 255}]", got)
+	}
+	if got := c.Cover(RegionFunc(func(x, y int) bool { return false })); len(got) != 0 {
+		t.Errorf("NewCoverer(2, 4, 1).Cover(nothing) = %v want []", got)
+	}
+}
+
+func TestMaskRegion(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	// A rectangle, and a mask smaller than the space.
+	mask := make([][]bool, 10)
+	for y := range mask {
+		mask[y] = make([]bool, 7)
+		if y >= 2 {
+			for x := 3; x < 7; x++ {
+				mask[y][x] = true
+			}
+		}
+	}
+
+	c, _ := NewCoverer(s, 0, s.GetOrder(), 1000)
+	want, _ := s.RangeQuery(3, 2, 6, 9)
+	if got := c.Cover(MaskRegion(mask)); !reflect.DeepEqual(got, want) {
+		t.Errorf("Cover(mask) = %v want %v", got, want)
+	}
+}
+
+func TestNewCovererErrors(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		minLevel, maxLevel, maxCells int
+		want                         error
+	}{
+		{-1, 4, 8, ErrOutOfRange},
+		{3, 2, 8, ErrOutOfRange},
+		{0, 5, 8, ErrOutOfRange},
+		{0, 4, 0, ErrNotPositive},
+	}
+	for _, tc := range testCases {
+		if _, err := NewCoverer(s, tc.minLevel, tc.maxLevel, tc.maxCells); err != tc.want {
+			t.Errorf("NewCoverer(%d, %d, %d) = %v want %v", tc.minLevel, tc.maxLevel, tc.maxCells, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"encoding/base32"
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// Column is a column which can be written by WriteCSV.
+type Column int
+
+// Supported columns.
+const (
+	ColumnT       Column = iota // The value on the curve
+	ColumnX                     // The x coordinate of the cell
+	ColumnY                     // The y coordinate of the cell
+	ColumnPacked                // The cell's row-major position in the space, y*N+x
+	ColumnHeading               // The direction the curve leaves the cell in, see Heading
+	ColumnKey                   // The value on the curve as a base32 key, see WriteCSV
+)
+
+var columnNames = [...]string{"t", "x", "y", "packed", "heading", "key"}
+
+// String returns the name of the column, as used in the header written by WriteCSV.
+func (c Column) String() string {
+	if c < 0 || int(c) >= len(columnNames) {
+		return "unknown"
+	}
+	return columnNames[c]
+}
+
+// keyEncoding is used by ColumnKey. The extended hex alphabet preserves the sort order of the
+// encoded bytes.
+var keyEncoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+
+// WriteCSV writes every cell on the curve to w as CSV, one row per cell in curve order, with the
+// given columns. The first row is a header of the column names. ColumnKey is the value on the
+// curve stored big-endian in ceil(2*GetOrder()/8) bytes, encoded with the extended hex base32
+// alphabet without padding, so keys sort in curve order. The rows are written as they are
+// generated, so the whole curve is never held in memory. ErrOutOfRange is returned for unknown
+// columns.
+func (s *Hilbert) WriteCSV(w io.Writer, cols []Column) error {
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		if c < 0 || int(c) >= len(columnNames) {
+			return ErrOutOfRange
+		}
+		header[i] = c.String()
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	progress := s.newProgress(s.N * s.N)
+	key := make([]byte, s.compositeIndexBytes())
+	row := make([]string, len(cols))
+	for t := 0; t < s.N*s.N; t++ {
+		x, y, _ := s.Map(t)
+		for i, c := range cols {
+			switch c {
+			case ColumnT:
+				row[i] = strconv.Itoa(t)
+			case ColumnX:
+				row[i] = strconv.Itoa(x)
+			case ColumnY:
+				row[i] = strconv.Itoa(y)
+			case ColumnPacked:
+				row[i] = strconv.Itoa(y*s.N + x)
+			case ColumnHeading:
+				h, _ := s.Heading(t)
+				row[i] = h.String()
+			case ColumnKey:
+				for j, v := len(key)-1, t; j >= 0; j, v = j-1, v>>8 {
+					key[j] = byte(v)
+				}
+				row[i] = keyEncoding.EncodeToString(key)
+			}
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+		progress.report(t + 1)
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
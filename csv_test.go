@@ -0,0 +1,90 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var buf bytes.Buffer
+	cols := []Column{ColumnT, ColumnX, ColumnY, ColumnPacked, ColumnHeading, ColumnKey}
+	if err := s.WriteCSV(&buf, cols); err != nil {
+		t.Fatalf("WriteCSV() returned error: %s", err)
+	}
+
+	want := `t,x,y,packed,heading,key
+0,0,0,0,down,00
+1,0,1,2,right,04
+2,1,1,3,up,08
+3,1,0,1,none,0C
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV() = %q want %q", got, want)
+	}
+}
+
+func TestWriteCSVKeysSort(t *testing.T) {
+	s, err := NewHilbert(64, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteCSV(&buf, []Column{ColumnKey, ColumnY}); err != nil {
+		t.Fatalf("WriteCSV() returned error: %s", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() returned error: %s", err)
+	}
+	if len(records) != s.N*s.N+1 {
+		t.Fatalf("WriteCSV() wrote %d rows want %d", len(records), s.N*s.N+1)
+	}
+
+	keys := make([]string, 0, s.N*s.N)
+	for _, r := range records[1:] {
+		keys = append(keys, r[0])
+	}
+	if !sort.StringsAreSorted(keys) {
+		t.Errorf("WriteCSV() keys are not sorted")
+	}
+}
+
+func TestWriteCSVErrors(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var buf bytes.Buffer
+	for _, c := range []Column{-1, 6} {
+		if err := s.WriteCSV(&buf, []Column{ColumnT, c}); err != ErrOutOfRange {
+			t.Errorf("WriteCSV(%d) = %v want %v", c, err, ErrOutOfRange)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("WriteCSV() with unknown column wrote %q", buf.String())
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "sort"
+
+// Decimate thins points to a spatially even subsample, by sorting them along the curve and
+// keeping every nth, starting with the first. As the curve keeps nearby cells together, this is
+// better distributed than dropping points at random. The kept points are returned in curve order,
+// or in their original order if originalOrder is true. ErrOutOfRange is returned if keepEveryNth
+// is not positive, or a point is outside of the space.
+func (s *Hilbert) Decimate(points [][2]int, keepEveryNth int, originalOrder bool) ([][2]int, error) {
+	if keepEveryNth <= 0 {
+		return nil, ErrOutOfRange
+	}
+
+	type point struct {
+		t, i int
+	}
+	sorted := make([]point, len(points))
+	for i, p := range points {
+		if !s.contains(p) {
+			return nil, ErrOutOfRange
+		}
+		t, _ := s.MapInverse(p[0], p[1])
+		sorted[i] = point{t, i}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].t < sorted[j].t
+	})
+
+	kept := make([]point, 0, (len(points)+keepEveryNth-1)/keepEveryNth)
+	for i := 0; i < len(sorted); i += keepEveryNth {
+		kept = append(kept, sorted[i])
+	}
+	if originalOrder {
+		sort.Slice(kept, func(i, j int) bool {
+			return kept[i].i < kept[j].i
+		})
+	}
+
+	result := make([][2]int, len(kept))
+	for i, p := range kept {
+		result[i] = points[p.i]
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDecimate(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	// In curve order these are t = 10, 0, 5, 2, 15, 7.
+	points := [][2]int{{3, 3}, {0, 0}, {0, 3}, {1, 1}, {3, 0}, {1, 2}}
+
+	testCases := []struct {
+		n             int
+		originalOrder bool
+		want          [][2]int
+	}{
+		{1, false, [][2]int{{0, 0}, {1, 1}, {0, 3}, {1, 2}, {3, 3}, {3, 0}}},
+		{1, true, points},
+		{2, false, [][2]int{{0, 0}, {0, 3}, {3, 3}}}, // t = 0, 5, 10
+		{2, true, [][2]int{{3, 3}, {0, 0}, {0, 3}}},
+		{4, false, [][2]int{{0, 0}, {3, 3}}}, // t = 0, 10
+		{10, true, [][2]int{{0, 0}}},
+	}
+
+	for _, tc := range testCases {
+		got, err := s.Decimate(points, tc.n, tc.originalOrder)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Decimate(%d, %t) = (%v, %v) want (%v, nil)", tc.n, tc.originalOrder, got, err, tc.want)
+		}
+	}
+
+	if got, err := s.Decimate(nil, 3, false); err != nil || len(got) != 0 {
+		t.Errorf("Decimate(nil, 3) = (%v, %v) want ([], nil)", got, err)
+	}
+	if _, err := s.Decimate(points, 0, false); err != ErrOutOfRange {
+		t.Errorf("Decimate(0) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := s.Decimate([][2]int{{4, 0}}, 1, false); err != ErrOutOfRange {
+		t.Errorf("Decimate({4, 0}) = %v want %v", err, ErrOutOfRange)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// MapDeltas returns the steps (dx,dy) between the cells of each consecutive pair of values in
+// the range [lo, hi] on the curve, so hi-lo steps in total. Each component of a step is in
+// {-1, 0, 1}, which makes the deltas small and compressible. Curves which jump between cells
+// that are not adjacent horizontally or vertically return ErrNotContinuous.
+func MapDeltas(s SpaceFilling, lo, hi int) ([][2]int8, error) {
+	if lo > hi {
+		return nil, ErrOutOfRange
+	}
+
+	x0, y0, err := s.Map(lo)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, err := s.Map(hi); err != nil {
+		return nil, err
+	}
+
+	deltas := make([][2]int8, 0, hi-lo)
+	for t := lo + 1; t <= hi; t++ {
+		x1, y1, _ := s.Map(t)
+		if headingBetween(x0, y0, x1, y1) == HeadingNone {
+			return nil, ErrNotContinuous
+		}
+		deltas = append(deltas, [2]int8{int8(x1 - x0), int8(y1 - y0)})
+		x0, y0 = x1, y1
+	}
+	return deltas, nil
+}
+
+// FromDeltas is the inverse of MapDeltas, returning the coordinates of the cells visited when
+// taking each step from start. The first coordinate is start, so len(deltas)+1 are returned.
+func FromDeltas(start [2]int, deltas [][2]int8) [][2]int {
+	points := make([][2]int, len(deltas)+1)
+	points[0] = start
+	for i, d := range deltas {
+		points[i+1] = [2]int{points[i][0] + int(d[0]), points[i][1] + int(d[1])}
+	}
+	return points
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapDeltas(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	got, err := MapDeltas(s, 0, 3)
+	if err != nil {
+		t.Fatalf("MapDeltas(0, 3) returned error: %s", err)
+	}
+	if want := [][2]int8{{0, 1}, {1, 0}, {0, -1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MapDeltas(0, 3) = %v want %v", got, want)
+	}
+
+	if got, _ := MapDeltas(s, 2, 2); len(got) != 0 {
+		t.Errorf("MapDeltas(2, 2) = %v want []", got)
+	}
+}
+
+func TestMapDeltasRoundTrip(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		s, err := NewHilbert(32, vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		lo, hi := 100, 900
+		deltas, err := MapDeltas(s, lo, hi)
+		if err != nil {
+			t.Fatalf("MapDeltas(%d, %d) returned error: %s", lo, hi, err)
+		}
+
+		x, y, _ := s.Map(lo)
+		for i, p := range FromDeltas([2]int{x, y}, deltas) {
+			wantX, wantY, _ := s.Map(lo + i)
+			if p != [2]int{wantX, wantY} {
+				t.Errorf("FromDeltas()[%d] = %v want [%d %d]", i, p, wantX, wantY)
+			}
+		}
+	}
+}
+
+func TestMapDeltasErrors(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		s       SpaceFilling
+		lo, hi  int
+		wantErr error
+	}{
+		{s, -1, 3, ErrOutOfRange},
+		{s, 0, 16, ErrOutOfRange},
+		{s, 3, 2, ErrOutOfRange},
+		{rowMajor{4, 4}, 0, 3, nil},
+		{rowMajor{4, 4}, 0, 4, ErrNotContinuous},
+	}
+
+	for _, tc := range testCases {
+		if _, err := MapDeltas(tc.s, tc.lo, tc.hi); err != tc.wantErr {
+			t.Errorf("MapDeltas(%v, %d, %d) = %q want %q", tc.s, tc.lo, tc.hi, err, tc.wantErr)
+		}
+	}
+}
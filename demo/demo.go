@@ -229,7 +229,7 @@ func mainDrawLogo(filename string, curve hilbert.SpaceFilling) error {
 func main() {
 
 	newHilbert := func(n int) hilbert.SpaceFilling {
-		s, err := hilbert.NewHilbert(int(math.Pow(2, float64(n))))
+		s, err := hilbert.NewHilbert(int(math.Pow(2, float64(n))), false)
 		if err != nil {
 			panic(fmt.Errorf("failed to create hilbert space: %s", err.Error()))
 		}
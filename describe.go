@@ -0,0 +1,113 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "fmt"
+
+// describer is implemented by curves which describe themselves for DescribeCurve.
+type describer interface {
+	Describe() string
+}
+
+// DescribeCurve returns a stable, human readable description of any curve, including its type,
+// dimensions and configuration, such as "Hilbert 16x16 horizontal". Unlike Fingerprint, it is
+// intended for people, such as in test failure messages. Curves outside of this package are
+// described by their Go type and dimensions.
+func DescribeCurve(c SpaceFilling) string {
+	if c == nil {
+		return "nil"
+	}
+	if d, ok := c.(describer); ok {
+		return d.Describe()
+	}
+	w, h := c.GetDimensions()
+	return fmt.Sprintf("%T %dx%d", c, w, h)
+}
+
+// Describe returns a description of the curve, see DescribeCurve. Equivalent curves, which map
+// every value to the same cell, have the same description.
+func (s *Hilbert) Describe() string {
+	layout, reversed := s.canonicalLayout()
+	d := fmt.Sprintf("Hilbert %dx%d %s", s.N, s.N, Orientation(layout&1))
+	if layout > 1 {
+		x, y := applyLayout(layout&3, layout&4 != 0, s.N, 0, 0)
+		d += fmt.Sprintf(" from (%d,%d)", x, y)
+	}
+	if reversed {
+		d += " reversed"
+	}
+	return d
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (p *Peano) Describe() string {
+	return fmt.Sprintf("Peano %dx%d", p.N, p.N)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Morton) Describe() string {
+	return fmt.Sprintf("Morton %dx%d", s.N, s.N)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Gray) Describe() string {
+	return fmt.Sprintf("Gray %dx%d", s.N, s.N)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Moore) Describe() string {
+	return fmt.Sprintf("Moore %dx%d", s.N, s.N)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *CompactHilbert) Describe() string {
+	return fmt.Sprintf("Compact Hilbert %dx%d", s.W, s.H)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Generalized) Describe() string {
+	return fmt.Sprintf("Generalized Hilbert %dx%d", s.W, s.H)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Tiled) Describe() string {
+	return fmt.Sprintf("Tiled Hilbert %dx%d", s.W, s.H)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Stack) Describe() string {
+	return fmt.Sprintf("Stack of %d %dx%d %s", s.Tiles, s.TileSize, s.TileSize, s.orientation)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Table) Describe() string {
+	return fmt.Sprintf("Table %dx%d", s.N, s.N)
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (c *CachedCurve) Describe() string {
+	return fmt.Sprintf("Cached %s", DescribeCurve(c.curve))
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Window) Describe() string {
+	return fmt.Sprintf("Window %dx%d at (%d,%d) of %s", s.w, s.h, s.ox, s.oy, s.curve.Describe())
+}
+
+// Describe returns a description of the curve, see DescribeCurve.
+func (s *Centered) Describe() string {
+	lo, _ := s.Bounds()
+	return fmt.Sprintf("Centered at (%d,%d) of %s", lo, lo, s.curve.Describe())
+}
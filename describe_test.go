@@ -0,0 +1,69 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestDescribeCurve(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(8, true)
+	rotated, _ := NewHilbert(8, true, WithTransform(Transform{Rotation: 2}))
+	p, _ := NewPeano(9)
+	m, _ := NewMorton(4)
+	moore, _ := NewMoore(8)
+	g, _ := NewGray(4)
+	compact, _ := NewCompactHilbert(8, 2)
+	generalized, _ := NewGeneralized(7, 5)
+	tiled, _ := NewTiledHilbert(8, 32)
+	stack, _ := NewStack(4, 3, Vertical)
+	table, _ := NewFromTable([]int{0, 3, 1, 2})
+	w, _ := NewHilbertWindow(5, 3, 10, 7, 4)
+
+	testCases := []struct {
+		c    SpaceFilling
+		want string
+	}{
+		{nil, "nil"},
+		{h, "Hilbert 16x16 horizontal"},
+		{h.Reversed(), "Hilbert 16x16 horizontal reversed"},
+		{v, "Hilbert 8x8 vertical"},
+		{rotated, "Hilbert 8x8 vertical from (7,7)"},
+		{p, "Peano 9x9"},
+		{m, "Morton 4x4"},
+		{moore, "Moore 8x8"},
+		{g, "Gray 4x4"},
+		{compact, "Compact Hilbert 8x2"},
+		{generalized, "Generalized Hilbert 7x5"},
+		{tiled, "Tiled Hilbert 8x32"},
+		{stack, "Stack of 3 4x4 vertical"},
+		{table, "Table 2x2"},
+		{NewCached(v), "Cached Hilbert 8x8 vertical"},
+		{w, "Window 7x4 at (3,10) of Hilbert 32x32 horizontal"},
+		{rowMajor{4, 3}, "hilbert.rowMajor 4x3"},
+	}
+
+	for _, tc := range testCases {
+		if got := DescribeCurve(tc.c); got != tc.want {
+			t.Errorf("DescribeCurve(%T) = %q want %q", tc.c, got, tc.want)
+		}
+	}
+}
+
+func TestDescribeCentered(t *testing.T) {
+	s, _ := NewCentered(16, false)
+	if got, want := s.Describe(), "Centered at (-8,-8) of Hilbert 16x16 horizontal"; got != want {
+		t.Errorf("Describe() = %q want %q", got, want)
+	}
+}
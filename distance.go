@@ -0,0 +1,78 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/bits"
+
+// Distance returns the distance along the curve between the cells (x1,y1) and (x2,y2), that is
+// |t1-t2| where t1 and t2 are their values on the curve. The levels above the one where the two
+// cells fall into different quadrants add the same digits to both values, so they are walked only
+// once, and only the levels below are walked for each cell. Distance therefore takes O(GetOrder())
+// time and does not allocate, walking each level at most twice, and cells which are close together
+// in the quadtree are cheaper. If Prewarm has been called it is two table lookups. ErrOutOfRange is
+// returned if either cell is not within the space.
+func (s *Hilbert) Distance(x1, y1, x2, y2 int) (int, error) {
+	if uint(x1) >= uint(s.N) || uint(y1) >= uint(s.N) || uint(x2) >= uint(s.N) || uint(y2) >= uint(s.N) {
+		var okX1, okY1, okX2, okY2 bool
+		x1, okX1 = s.fitCoord(x1)
+		y1, okY1 = s.fitCoord(y1)
+		x2, okX2 = s.fitCoord(x2)
+		y2, okY2 = s.fitCoord(y2)
+		if !okX1 || !okY1 || !okX2 || !okY2 {
+			return -1, ErrOutOfRange
+		}
+	}
+
+	if s.inverse != nil {
+		return absInt(int(s.inverse[y1*s.N+x1]) - int(s.inverse[y2*s.N+x2])), nil
+	}
+	if s.mirrored {
+		x1, x2 = s.N-1-x1, s.N-1-x2
+	}
+
+	// Walk the shared levels once, two at a time as in MapInverse, to find the state of the
+	// smallest quadrant containing both cells. Reversing the curve does not change the distance,
+	// so it can be ignored.
+	split := uint(bits.Len(uint((x1 ^ x2) | (y1 ^ y2))))
+	state := s.startState
+	shift := uint(s.GetOrder())
+	for shift >= split+2 {
+		shift -= 2
+		state = inverseStates2[(uint(state)<<4|uint(x1>>shift&3)<<2|uint(y1>>shift&3))&63] & 3
+	}
+	if shift > split {
+		shift--
+		state = inverseStates[int(state)<<2|(x1>>shift&1)<<1|y1>>shift&1] & 3
+	}
+
+	// Walk the rest of the levels for both cells together.
+	var t1, t2 int
+	state1, state2 := state, state
+	if shift&1 == 1 {
+		shift--
+		e1 := inverseStates[int(state1)<<2|(x1>>shift&1)<<1|y1>>shift&1]
+		e2 := inverseStates[int(state2)<<2|(x2>>shift&1)<<1|y2>>shift&1]
+		t1, t2 = int(e1>>2), int(e2>>2)
+		state1, state2 = e1&3, e2&3
+	}
+	for shift > 0 {
+		shift -= 2
+		e1 := inverseStates2[(uint(state1)<<4|uint(x1>>shift&3)<<2|uint(y1>>shift&3))&63]
+		e2 := inverseStates2[(uint(state2)<<4|uint(x2>>shift&3)<<2|uint(y2>>shift&3))&63]
+		t1, t2 = t1<<4|int(e1>>2), t2<<4|int(e2>>2)
+		state1, state2 = e1&3, e2&3
+	}
+	return absInt(t1 - t2), nil
+}
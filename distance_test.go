@@ -0,0 +1,60 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(8, true, WithTransform(Transform{Mirror: true, Rotation: 3}))
+	c, _ := NewHilbertCached(8, false)
+	one, _ := NewHilbert(1, false)
+	for _, s := range []*Hilbert{h, h.Reversed(), v, v.Reversed(), c, one} {
+		for t1 := 0; t1 < s.N*s.N; t1++ {
+			x1, y1, _ := s.Map(t1)
+			for t2 := 0; t2 < s.N*s.N; t2++ {
+				x2, y2, _ := s.Map(t2)
+				got, err := s.Distance(x1, y1, x2, y2)
+				if err != nil {
+					t.Fatalf("Distance(%d, %d, %d, %d) returned error: %s", x1, y1, x2, y2, err)
+				}
+				if want := absInt(t1 - t2); got != want {
+					t.Errorf("%v: Distance(%d, %d, %d, %d) = %d want %d", s.Config(), x1, y1, x2, y2, got, want)
+				}
+			}
+		}
+	}
+
+	for _, p := range [][4]int{{-1, 0, 0, 0}, {0, 16, 0, 0}, {0, 0, 16, 0}, {0, 0, 0, -1}} {
+		if _, err := h.Distance(p[0], p[1], p[2], p[3]); err != ErrOutOfRange {
+			t.Errorf("Distance(%d, %d, %d, %d) = %v want %v", p[0], p[1], p[2], p[3], err, ErrOutOfRange)
+		}
+	}
+
+	w, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsWrap))
+	if got, err := w.Distance(-1, 0, 3, 4); got != 0 || err != nil {
+		t.Errorf("Distance(-1, 0, 3, 4) = %d, %v want 0, <nil>", got, err)
+	}
+}
+
+func BenchmarkDistance(b *testing.B) {
+	s, err := NewHilbert(1<<16, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+	for n := 0; n < b.N; n++ {
+		s.Distance(n&0xffff, n>>3&0xffff, n>>1&0xffff, n>>5&0xffff)
+	}
+}
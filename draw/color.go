@@ -0,0 +1,58 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package draw is for rendering space-filling curves from the hilbert package.
+package draw
+
+import (
+	"image/color"
+	"math"
+)
+
+// ColorFunc returns the color to draw the cell at t, out of total cells on the curve. It allows
+// the position along the curve to be encoded as a color.
+type ColorFunc func(t, total int) color.Color
+
+// Rainbow is a ColorFunc which sweeps the hue from red, through green and blue, and back towards
+// red along the curve, at full saturation and value.
+func Rainbow(t, total int) color.Color {
+	h := 0.0
+	if total > 0 {
+		h = 6 * float64(t) / float64(total)
+	}
+
+	// Convert HSV, with h in [0, 6) and s = v = 1, to RGB.
+	x := uint8(math.Round(255 * (1 - math.Abs(math.Mod(h, 2)-1))))
+	switch int(h) {
+	case 0:
+		return color.RGBA{0xff, x, 0, 0xff}
+	case 1:
+		return color.RGBA{x, 0xff, 0, 0xff}
+	case 2:
+		return color.RGBA{0, 0xff, x, 0xff}
+	case 3:
+		return color.RGBA{0, x, 0xff, 0xff}
+	case 4:
+		return color.RGBA{x, 0, 0xff, 0xff}
+	}
+	return color.RGBA{0xff, 0, x, 0xff}
+}
+
+// Grayscale is a ColorFunc which fades from black at the start of the curve to white at the end.
+func Grayscale(t, total int) color.Color {
+	if total <= 1 {
+		return color.Gray{0}
+	}
+	return color.Gray{uint8(math.Round(255 * float64(t) / float64(total-1)))}
+}
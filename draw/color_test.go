@@ -0,0 +1,62 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"image/color"
+	"testing"
+)
+
+func TestRainbow(t *testing.T) {
+	testCases := []struct {
+		t, total int
+		want     color.Color
+	}{
+		{0, 6, color.RGBA{0xff, 0, 0, 0xff}},
+		{1, 6, color.RGBA{0xff, 0xff, 0, 0xff}},
+		{2, 6, color.RGBA{0, 0xff, 0, 0xff}},
+		{3, 6, color.RGBA{0, 0xff, 0xff, 0xff}},
+		{4, 6, color.RGBA{0, 0, 0xff, 0xff}},
+		{5, 6, color.RGBA{0xff, 0, 0xff, 0xff}},
+		{1, 12, color.RGBA{0xff, 0x80, 0, 0xff}},
+		{11, 12, color.RGBA{0xff, 0, 0x80, 0xff}},
+		{0, 1, color.RGBA{0xff, 0, 0, 0xff}},
+	}
+
+	for _, tc := range testCases {
+		if got := Rainbow(tc.t, tc.total); got != tc.want {
+			t.Errorf("Rainbow(%d, %d) = %v want %v", tc.t, tc.total, got, tc.want)
+		}
+	}
+}
+
+func TestGrayscale(t *testing.T) {
+	testCases := []struct {
+		t, total int
+		want     color.Color
+	}{
+		{0, 1, color.Gray{0}},
+		{0, 256, color.Gray{0}},
+		{128, 256, color.Gray{128}},
+		{255, 256, color.Gray{255}},
+		{1, 3, color.Gray{128}},
+	}
+
+	for _, tc := range testCases {
+		if got := Grayscale(tc.t, tc.total); got != tc.want {
+			t.Errorf("Grayscale(%d, %d) = %v want %v", tc.t, tc.total, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+	imagedraw "image/draw"
+	"strconv"
+
+	"github.com/google/hilbert"
+)
+
+// Option configures how DrawCurve renders a curve.
+type Option func(*config)
+
+// config holds the settings for DrawCurve, set from the defaults and then each Option.
+type config struct {
+	background color.Color
+	line       color.Color
+	colors     ColorFunc
+	lineWidth  int
+	labels     bool
+
+	// Used only by VisualizeReader.
+	coloring Coloring
+	maxSize  int
+}
+
+// WithBackground sets the color the image is filled with before drawing. The default is white.
+func WithBackground(c color.Color) Option {
+	return func(cfg *config) {
+		cfg.background = c
+	}
+}
+
+// WithLineColor sets the color of the line, and of the labels. The default is black.
+func WithLineColor(c color.Color) Option {
+	return func(cfg *config) {
+		cfg.line = c
+	}
+}
+
+// WithColors colors each segment of the line by its position along the curve, such as with
+// Rainbow, instead of drawing it in a single color. The segment from t to t+1 has the color of t.
+func WithColors(fn ColorFunc) Option {
+	return func(cfg *config) {
+		cfg.colors = fn
+	}
+}
+
+// WithLineWidth sets the width of the line in pixels. The default is an eighth of the cell size,
+// and at least one pixel.
+func WithLineWidth(width int) Option {
+	return func(cfg *config) {
+		cfg.lineWidth = width
+	}
+}
+
+// WithLabels draws the value of t in the top left corner of each cell, in a small fixed font. Any
+// label too wide for its cell is left out, so cells must be at least 7 pixels for any to be drawn.
+func WithLabels() Option {
+	return func(cfg *config) {
+		cfg.labels = true
+	}
+}
+
+// DrawCurve renders the path of the curve through its cells as a line joining the centers of
+// consecutive cells, with each cell cellSize pixels square. A cellSize less than 1 is treated as
+// 1. Any value which the curve fails to map breaks the line, instead of failing the drawing.
+func DrawCurve(s hilbert.SpaceFilling, cellSize int, opts ...Option) image.Image {
+	cellSize = max(cellSize, 1)
+	cfg := config{
+		background: color.White,
+		line:       color.Black,
+		lineWidth:  max(cellSize/8, 1),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	w, h := s.GetDimensions()
+	img := image.NewRGBA(image.Rect(0, 0, w*cellSize, h*cellSize))
+	imagedraw.Draw(img, img.Bounds(), image.NewUniform(cfg.background), image.Point{}, imagedraw.Src)
+
+	total := w * h
+	prev, havePrev := image.Point{}, false
+	for t := 0; t < total; t++ {
+		x, y, err := s.Map(t)
+		if err != nil {
+			havePrev = false
+			continue
+		}
+		p := image.Pt(x*cellSize+cellSize/2, y*cellSize+cellSize/2)
+
+		// The line is started with a dot, so a cell on its own, such as the only cell of a curve,
+		// is still drawn.
+		from, fromT := prev, t-1
+		if !havePrev {
+			from, fromT = p, t
+		}
+		c := cfg.line
+		if cfg.colors != nil {
+			c = cfg.colors(fromT, total)
+		}
+		drawLine(img, from, p, cfg.lineWidth, c)
+		prev, havePrev = p, true
+	}
+
+	// Labels are drawn last, so the line never covers them.
+	if cfg.labels {
+		for t := 0; t < total; t++ {
+			if x, y, err := s.Map(t); err == nil {
+				drawLabel(img, image.Pt(x*cellSize, y*cellSize), cellSize, strconv.Itoa(t), cfg.line)
+			}
+		}
+	}
+	return img
+}
+
+// drawLine draws a straight line from a to b, width pixels wide, by stepping one pixel at a time
+// along the longer axis and filling a square at each step.
+func drawLine(img *image.RGBA, a, b image.Point, width int, c color.Color) {
+	d := b.Sub(a)
+	steps := max(abs(d.X), abs(d.Y))
+	u := image.NewUniform(c)
+	for i := 0; i <= steps; i++ {
+		p := a
+		if steps > 0 {
+			p = a.Add(image.Pt(roundDiv(d.X*i, steps), roundDiv(d.Y*i, steps)))
+		}
+		r := image.Rect(p.X-width/2, p.Y-width/2, p.X-width/2+width, p.Y-width/2+width)
+		imagedraw.Draw(img, r, u, image.Point{}, imagedraw.Over)
+	}
+}
+
+// abs returns the absolute value of v.
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// roundDiv returns n/d rounded to the nearest integer, with halves rounded away from zero. d must
+// be positive.
+func roundDiv(n, d int) int {
+	if n < 0 {
+		return -((-n + d/2) / d)
+	}
+	return (n + d/2) / d
+}
+
+// glyphs is a 3x5 pixel font of the digits 0 to 9, each row being 3 bits with the leftmost pixel
+// in the highest bit.
+var glyphs = [10][5]uint8{
+	{7, 5, 5, 5, 7}, // 0
+	{2, 6, 2, 2, 7}, // 1
+	{7, 1, 7, 4, 7}, // 2
+	{7, 1, 7, 1, 7}, // 3
+	{5, 5, 7, 1, 1}, // 4
+	{7, 4, 7, 1, 7}, // 5
+	{7, 4, 7, 5, 7}, // 6
+	{7, 1, 1, 1, 1}, // 7
+	{7, 5, 7, 5, 7}, // 8
+	{7, 5, 7, 1, 7}, // 9
+}
+
+// Sizes of the glyphs, and the margin around a label, in pixels.
+const (
+	glyphWidth   = 3
+	glyphHeight  = 5
+	glyphSpacing = 1
+	labelMargin  = 1
+)
+
+// drawLabel draws the digits of label with the top left of the cell of the given size at
+// origin, unless it does not fit within the cell.
+func drawLabel(img *image.RGBA, origin image.Point, cellSize int, label string, c color.Color) {
+	width := len(label)*(glyphWidth+glyphSpacing) - glyphSpacing
+	if 2*labelMargin+width > cellSize || 2*labelMargin+glyphHeight > cellSize {
+		return
+	}
+
+	for i, r := range label {
+		glyph := glyphs[r-'0']
+		left := origin.X + labelMargin + i*(glyphWidth+glyphSpacing)
+		for row, bits := range glyph {
+			for col := 0; col < glyphWidth; col++ {
+				if bits>>uint(glyphWidth-1-col)&1 == 1 {
+					img.Set(left+col, origin.Y+labelMargin+row, c)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/google/hilbert"
+)
+
+// same returns true if the two colors are the same once converted to RGBA.
+func same(a, b color.Color) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return ar == br && ag == bg && ab == bb && aa == ba
+}
+
+func TestDrawCurve(t *testing.T) {
+	s, err := hilbert.NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	img := DrawCurve(s, 8)
+	if got, want := img.Bounds(), image.Rect(0, 0, 32, 32); got != want {
+		t.Fatalf("DrawCurve(%s, 8).Bounds() = %v want %v", hilbert.DescribeCurve(s), got, want)
+	}
+
+	// Every cell center is on the line, and half way between consecutive cells is too.
+	for d := 0; d < 16; d++ {
+		x, y, _ := s.Map(d)
+		if c := img.At(x*8+4, y*8+4); !same(c, color.Black) {
+			t.Errorf("DrawCurve() center of cell %d at (%d, %d) = %v want %v", d, x, y, c, color.Black)
+		}
+		if d > 0 {
+			px, py, _ := s.Map(d - 1)
+			if c := img.At(4*(x+px)+4, 4*(y+py)+4); !same(c, color.Black) {
+				t.Errorf("DrawCurve() between cells %d and %d = %v want %v", d-1, d, c, color.Black)
+			}
+		}
+	}
+
+	// The first and last cells are not consecutive, so are not joined.
+	sx, sy, ex, ey := s.Endpoints()
+	if c := img.At(4*(sx+ex)+4, 4*(sy+ey)+4); !same(c, color.White) {
+		t.Errorf("DrawCurve() between the endpoints = %v want %v", c, color.White)
+	}
+	if c := img.At(0, 0); !same(c, color.White) {
+		t.Errorf("DrawCurve() corner = %v want %v", c, color.White)
+	}
+}
+
+func TestDrawCurveOptions(t *testing.T) {
+	s, _ := hilbert.NewHilbert(2, false)
+	background := color.RGBA{0x11, 0x22, 0x33, 0xff}
+	img := DrawCurve(s, 8, WithBackground(background), WithColors(Grayscale), WithLineWidth(3), WithLabels())
+
+	if c := img.At(7, 7); !same(c, background) {
+		t.Errorf("DrawCurve() background = %v want %v", c, background)
+	}
+
+	// The segment from cell 0 is drawn in its color, and is 3 pixels wide.
+	x0, y0, _ := s.Map(0)
+	x1, y1, _ := s.Map(1)
+	mx, my := 4*(x0+x1)+4, 4*(y0+y1)+4
+	for _, p := range []image.Point{{mx, my}, {mx - 1, my - 1}, {mx + 1, my + 1}} {
+		if c := img.At(p.X, p.Y); !same(c, Grayscale(0, 4)) {
+			t.Errorf("DrawCurve() segment at %v = %v want %v", p, c, Grayscale(0, 4))
+		}
+	}
+
+	// The label of cell 3 is drawn in the line color, starting one pixel in from the corner.
+	x3, y3, _ := s.Map(3)
+	for row, bits := range glyphs[3] {
+		for col := 0; col < glyphWidth; col++ {
+			want := color.Color(background)
+			if bits>>uint(glyphWidth-1-col)&1 == 1 {
+				want = color.Black
+			}
+			if c := img.At(x3*8+1+col, y3*8+1+row); !same(c, want) {
+				t.Errorf("DrawCurve() label pixel (%d, %d) of cell 3 = %v want %v", col, row, c, want)
+			}
+		}
+	}
+}
+
+func TestDrawCurveLabelsTooWide(t *testing.T) {
+	s, _ := hilbert.NewHilbert(4, false)
+
+	// At 7 pixels a cell fits one digit but not two, so only the cells below 10 are labelled.
+	img := DrawCurve(s, 7, WithLabels(), WithLineWidth(0))
+	for d := 0; d < 16; d++ {
+		x, y, _ := s.Map(d)
+		labelled := false
+		for i := 0; i < glyphWidth*glyphHeight; i++ {
+			labelled = labelled || same(img.At(x*7+1+i%glyphWidth, y*7+1+i/glyphWidth), color.Black)
+		}
+		if want := d < 10; labelled != want {
+			t.Errorf("DrawCurve() cell %d labelled = %t want %t", d, labelled, want)
+		}
+	}
+}
+
+func TestDrawCurveSingleCell(t *testing.T) {
+	s, _ := hilbert.NewHilbert(1, false)
+	img := DrawCurve(s, 0)
+	if got, want := img.Bounds(), image.Rect(0, 0, 1, 1); got != want {
+		t.Fatalf("DrawCurve(N=1, 0).Bounds() = %v want %v", got, want)
+	}
+	if c := img.At(0, 0); !same(c, color.Black) {
+		t.Errorf("DrawCurve(N=1).At(0, 0) = %v want %v", c, color.Black)
+	}
+}
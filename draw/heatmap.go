@@ -0,0 +1,83 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+	imagedraw "image/draw"
+	"math"
+
+	"github.com/google/hilbert"
+)
+
+// heatmapLevels is the number of colors of the ramp used by Heatmap.
+const heatmapLevels = 256
+
+// Heatmap renders values laid out along the curve, so values[t] colors the cell at t, with each
+// cell cellSize pixels square. Nearby values are then drawn close together, showing the structure
+// of the data, as in binvis. The values are scaled between their minimum and maximum to t in
+// [0, 255] out of 256, which is passed to ramp, so any ColorFunc such as Grayscale can be used.
+// Cells past the end of values, or whose value is NaN or infinite, are left as the background,
+// which is set with WithBackground, and the other options are ignored. hilbert.ErrInvalidLength
+// is returned if there are more values than cells.
+func Heatmap(s hilbert.SpaceFilling, values []float64, ramp ColorFunc, cellSize int, opts ...Option) (image.Image, error) {
+	w, h := s.GetDimensions()
+	if len(values) > w*h {
+		return nil, hilbert.ErrInvalidLength
+	}
+	cellSize = max(cellSize, 1)
+	cfg := config{background: color.White}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if finite(v) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	scale := 0.0
+	if hi > lo {
+		scale = (heatmapLevels - 1) / (hi - lo)
+	}
+
+	var palette [heatmapLevels]color.RGBA
+	for i := range palette {
+		palette[i] = color.RGBAModel.Convert(ramp(i, heatmapLevels)).(color.RGBA)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w*cellSize, h*cellSize))
+	imagedraw.Draw(img, img.Bounds(), image.NewUniform(cfg.background), image.Point{}, imagedraw.Src)
+	for t, v := range values {
+		x, y, err := s.Map(t)
+		if err != nil || !finite(v) {
+			continue
+		}
+		c := palette[int(math.Round((v-lo)*scale))]
+		for py := y * cellSize; py < (y+1)*cellSize; py++ {
+			for px := x * cellSize; px < (x+1)*cellSize; px++ {
+				img.SetRGBA(px, py, c)
+			}
+		}
+	}
+	return img, nil
+}
+
+// finite returns true if v is neither NaN nor infinite.
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
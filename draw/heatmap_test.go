@@ -0,0 +1,77 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/google/hilbert"
+)
+
+func TestHeatmap(t *testing.T) {
+	s, err := hilbert.NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	values := []float64{10, 20, 30, math.NaN(), 10, 15, math.Inf(1)}
+	img, err := Heatmap(s, values, Grayscale, 2)
+	if err != nil {
+		t.Fatalf("Heatmap() returned error: %s", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 8, 8); got != want {
+		t.Fatalf("Heatmap().Bounds() = %v want %v", got, want)
+	}
+
+	// The values are scaled from 10 to 30, so 20 is half way, and 15 a quarter of the way.
+	want := []color.Color{
+		color.Gray{0}, color.Gray{128}, color.Gray{255}, color.White,
+		color.Gray{0}, color.Gray{64}, color.White, color.White,
+	}
+	for d, w := range want {
+		x, y, _ := s.Map(d)
+		for _, p := range []image.Point{{2 * x, 2 * y}, {2*x + 1, 2*y + 1}} {
+			if c := img.At(p.X, p.Y); !same(c, w) {
+				t.Errorf("Heatmap() cell %d at %v = %v want %v", d, p, c, w)
+			}
+		}
+	}
+}
+
+func TestHeatmapConstant(t *testing.T) {
+	s, _ := hilbert.NewHilbert(2, false)
+	background := color.RGBA{0, 0, 0xff, 0xff}
+	img, err := Heatmap(s, []float64{5, 5}, Rainbow, 1, WithBackground(background))
+	if err != nil {
+		t.Fatalf("Heatmap() returned error: %s", err)
+	}
+
+	for d, w := range []color.Color{Rainbow(0, heatmapLevels), Rainbow(0, heatmapLevels), background, background} {
+		x, y, _ := s.Map(d)
+		if c := img.At(x, y); !same(c, w) {
+			t.Errorf("Heatmap() cell %d = %v want %v", d, c, w)
+		}
+	}
+}
+
+func TestHeatmapTooLong(t *testing.T) {
+	s, _ := hilbert.NewHilbert(2, false)
+	if _, err := Heatmap(s, make([]float64, 5), Grayscale, 1); err != hilbert.ErrInvalidLength {
+		t.Errorf("Heatmap(5 values) = %v want %v", err, hilbert.ErrInvalidLength)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+
+	"github.com/google/hilbert"
+)
+
+// WriteSVG writes the path of the curve through its cells to w as an SVG image, the vector
+// equivalent of DrawCurve with the same options, which stays sharp and small however large the
+// curve is. The drawing is in units of cells, shown cellSize pixels square. By default the line is
+// a single path of relative moves, with consecutive moves in the same direction merged, but
+// WithColors writes each segment as its own path. Labels are drawn with WithLabels, in a font a
+// quarter of the cell high, and unlike DrawCurve they are never dropped for being too small.
+func WriteSVG(w io.Writer, s hilbert.SpaceFilling, cellSize int, opts ...Option) error {
+	cellSize = max(cellSize, 1)
+	cfg := config{
+		background: color.White,
+		line:       color.Black,
+		lineWidth:  max(cellSize/8, 1),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	// Write everything, then check the error once, which bufio keeps until Flush.
+	bw := bufio.NewWriter(w)
+	width, height := s.GetDimensions()
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width*cellSize, height*cellSize, width, height)
+	fmt.Fprintf(bw, `<rect width="%d" height="%d"%s/>`+"\n", width, height, svgPaint("fill", cfg.background))
+	fmt.Fprintf(bw, `<g fill="none" stroke-width="%s" stroke-linecap="square" stroke-linejoin="miter">`+"\n",
+		svgNumber(float64(cfg.lineWidth)/float64(cellSize)))
+
+	total := width * height
+	if cfg.colors == nil {
+		writeSVGPath(bw, s, total, cfg.line)
+	} else {
+		writeSVGSegments(bw, s, total, cfg.colors)
+	}
+	bw.WriteString("</g>\n")
+
+	if cfg.labels {
+		fmt.Fprintf(bw, `<g font-family="monospace" font-size="0.25"%s>`+"\n", svgPaint("fill", cfg.line))
+		for t := 0; t < total; t++ {
+			if x, y, err := s.Map(t); err == nil {
+				fmt.Fprintf(bw, `<text x="%s" y="%s">%d</text>`+"\n", svgNumber(float64(x)+0.05), svgNumber(float64(y)+0.25), t)
+			}
+		}
+		bw.WriteString("</g>\n")
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
+
+// writeSVGPath writes the whole curve as a single path in the color c. Any value which the curve
+// fails to map starts a new subpath.
+func writeSVGPath(w *bufio.Writer, s hilbert.SpaceFilling, total int, c color.Color) {
+	fmt.Fprintf(w, `<path%s d="`, svgPaint("stroke", c))
+
+	// A move is only written once the next one is in a different direction, so runs of moves in
+	// the same direction are merged into one.
+	var px, py, dx, dy, run int
+	started, moved := false, false
+	flush := func() {
+		switch {
+		case run == 0:
+			if started && !moved {
+				// A cell on its own, such as the only cell of a curve, is drawn as a dot.
+				w.WriteString("h0")
+			}
+		case dy == 0:
+			fmt.Fprintf(w, "h%d", dx*run)
+		case dx == 0:
+			fmt.Fprintf(w, "v%d", dy*run)
+		default:
+			fmt.Fprintf(w, "l%d %d", dx*run, dy*run)
+		}
+		run = 0
+	}
+	for t := 0; t < total; t++ {
+		x, y, err := s.Map(t)
+		if err != nil {
+			flush()
+			started = false
+			continue
+		}
+		if !started {
+			fmt.Fprintf(w, "M%s %s", svgNumber(float64(x)+0.5), svgNumber(float64(y)+0.5))
+			started, moved = true, false
+		} else if ndx, ndy := x-px, y-py; run == 0 || ndx != dx || ndy != dy {
+			if run > 0 {
+				flush()
+			}
+			dx, dy, run, moved = ndx, ndy, 1, true
+		} else {
+			run++
+		}
+		px, py = x, y
+	}
+	flush()
+	w.WriteString(`"/>` + "\n")
+}
+
+// writeSVGSegments writes each segment of the curve, from t to t+1, as a separate path colored by
+// colors.
+func writeSVGSegments(w *bufio.Writer, s hilbert.SpaceFilling, total int, colors ColorFunc) {
+	px, py, perr := s.Map(0)
+	if total == 1 && perr == nil {
+		// The only cell, drawn as a dot.
+		fmt.Fprintf(w, `<path%s d="M%s %sh0"/>`+"\n", svgPaint("stroke", colors(0, total)),
+			svgNumber(float64(px)+0.5), svgNumber(float64(py)+0.5))
+	}
+	for t := 1; t < total; t++ {
+		x, y, err := s.Map(t)
+		if err == nil && perr == nil {
+			fmt.Fprintf(w, `<path%s d="M%s %sl%d %d"/>`+"\n", svgPaint("stroke", colors(t-1, total)),
+				svgNumber(float64(px)+0.5), svgNumber(float64(py)+0.5), x-px, y-py)
+		}
+		px, py, perr = x, y, err
+	}
+}
+
+// svgPaint returns the attribute setting the paint attr, such as fill or stroke, to c, along with
+// its opacity if it is not opaque.
+func svgPaint(attr string, c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	paint := fmt.Sprintf(` %s="#%02x%02x%02x"`, attr, n.R, n.G, n.B)
+	if n.A != 0xff {
+		paint += fmt.Sprintf(` %s-opacity="%s"`, attr, svgNumber(float64(n.A)/0xff))
+	}
+	return paint
+}
+
+// svgNumber formats v as briefly as possible, to at most 4 decimal places.
+func svgNumber(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 4, 64)
+	for s[len(s)-1] == '0' {
+		s = s[:len(s)-1]
+	}
+	if s[len(s)-1] == '.' {
+		s = s[:len(s)-1]
+	}
+	return s
+}
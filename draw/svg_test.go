@@ -0,0 +1,131 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"image/color"
+	"testing"
+
+	"github.com/google/hilbert"
+)
+
+// svgImage is the part of the output of WriteSVG checked by the tests.
+type svgImage struct {
+	Width   int    `xml:"width,attr"`
+	Height  int    `xml:"height,attr"`
+	ViewBox string `xml:"viewBox,attr"`
+	Paths   []struct {
+		Stroke string `xml:"stroke,attr"`
+		D      string `xml:"d,attr"`
+	} `xml:"g>path"`
+	Labels []string `xml:"g>text"`
+}
+
+// parseSVG writes the curve with WriteSVG and parses the result.
+func parseSVG(t *testing.T, s hilbert.SpaceFilling, cellSize int, opts ...Option) svgImage {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, s, cellSize, opts...); err != nil {
+		t.Fatalf("WriteSVG() returned error: %s", err)
+	}
+	var img svgImage
+	if err := xml.Unmarshal(buf.Bytes(), &img); err != nil {
+		t.Fatalf("WriteSVG() wrote invalid XML: %s\n%s", err, buf.String())
+	}
+	return img
+}
+
+// gappy is a curve which fails to map the values in gaps.
+type gappy struct {
+	hilbert.SpaceFilling
+	gaps map[int]bool
+}
+
+func (g gappy) Map(t int) (int, int, error) {
+	if g.gaps[t] {
+		return -1, -1, errors.New("gap")
+	}
+	return g.SpaceFilling.Map(t)
+}
+
+func TestWriteSVG(t *testing.T) {
+	s, err := hilbert.NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	img := parseSVG(t, s, 10)
+	if img.Width != 40 || img.Height != 40 || img.ViewBox != "0 0 4 4" {
+		t.Errorf("WriteSVG() size = %d, %d, %q want 40, 40, %q", img.Width, img.Height, img.ViewBox, "0 0 4 4")
+	}
+	want := "M0.5 0.5h1v1h-1v2h1v-1h1v1h1v-2h-1v-1h1"
+	if len(img.Paths) != 1 || img.Paths[0].D != want || img.Paths[0].Stroke != "#000000" {
+		t.Errorf("WriteSVG() paths = %+v want one black path %q", img.Paths, want)
+	}
+	if len(img.Labels) != 0 {
+		t.Errorf("WriteSVG() labels = %q want none", img.Labels)
+	}
+}
+
+func TestWriteSVGOptions(t *testing.T) {
+	s, _ := hilbert.NewHilbert(2, false)
+	img := parseSVG(t, s, 10, WithColors(Grayscale), WithLabels())
+
+	want := []struct{ stroke, d string }{
+		{"#000000", "M0.5 0.5l0 1"},
+		{"#555555", "M0.5 1.5l1 0"},
+		{"#aaaaaa", "M1.5 1.5l0 -1"},
+	}
+	if len(img.Paths) != len(want) {
+		t.Fatalf("WriteSVG() wrote %d paths want %d", len(img.Paths), len(want))
+	}
+	for i, w := range want {
+		if p := img.Paths[i]; p.Stroke != w.stroke || p.D != w.d {
+			t.Errorf("WriteSVG() path %d = (%s, %q) want (%s, %q)", i, p.Stroke, p.D, w.stroke, w.d)
+		}
+	}
+	if got := img.Labels; len(got) != 4 || got[0] != "0" || got[3] != "3" {
+		t.Errorf("WriteSVG() labels = %q want [0 1 2 3]", got)
+	}
+}
+
+func TestWriteSVGGaps(t *testing.T) {
+	s, _ := hilbert.NewHilbert(4, false)
+
+	// The gap at 3 breaks the line, and leaves 4 on its own between 3 and 5.
+	img := parseSVG(t, gappy{s, map[int]bool{3: true, 5: true}}, 10)
+	want := "M0.5 0.5h1v1M0.5 2.5h0M1.5 3.5v-1h1v1h1v-2h-1v-1h1"
+	if len(img.Paths) != 1 || img.Paths[0].D != want {
+		t.Errorf("WriteSVG() paths = %+v want %q", img.Paths, want)
+	}
+}
+
+func TestSVGPaint(t *testing.T) {
+	testCases := []struct {
+		c    color.Color
+		want string
+	}{
+		{color.White, ` fill="#ffffff"`},
+		{color.RGBA{0x11, 0x22, 0x33, 0xff}, ` fill="#112233"`},
+		{color.NRGBA{0xff, 0, 0, 0x80}, ` fill="#ff0000" fill-opacity="0.502"`},
+	}
+	for _, tc := range testCases {
+		if got := svgPaint("fill", tc.c); got != tc.want {
+			t.Errorf("svgPaint(fill, %v) = %q want %q", tc.c, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"image"
+	"image/color"
+	imagedraw "image/draw"
+	"io"
+	"math"
+
+	"github.com/google/hilbert"
+)
+
+// Coloring is how VisualizeReader colors the bytes of a stream.
+type Coloring int
+
+// Supported colorings.
+const (
+	// ByteClass colors each byte by its class: black for 0x00, white for 0xff, blue for printable
+	// ASCII, green for ASCII control characters, and red for everything else. This is the default.
+	ByteClass Coloring = iota
+
+	// Entropy colors each byte by the Shannon entropy of the bytes around it, scaled to [0, 1] and
+	// passed through the ColorFunc set with WithColors, or Grayscale by default, so compressed or
+	// encrypted regions stand out from code and data.
+	Entropy
+)
+
+// Colors of each class of byte for ByteClass.
+var (
+	zeroColor      = color.RGBA{0, 0, 0, 0xff}
+	maxColor       = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	printableColor = color.RGBA{0x37, 0x7e, 0xb8, 0xff}
+	controlColor   = color.RGBA{0x4d, 0xaf, 0x4a, 0xff}
+	otherColor     = color.RGBA{0xe4, 0x1a, 0x1c, 0xff}
+)
+
+// defaultMaxSize is the default largest width of the image made by VisualizeReader.
+const defaultMaxSize = 512
+
+// entropyWindow is the least number of bytes the entropy of each cell is measured over.
+const entropyWindow = 32
+
+// WithColoring sets how VisualizeReader colors the bytes of the stream.
+func WithColoring(c Coloring) Option {
+	return func(cfg *config) {
+		cfg.coloring = c
+	}
+}
+
+// WithMaxSize sets the largest width and height of the image made by VisualizeReader, which is
+// 512 by default. It is rounded down to a power of two.
+func WithMaxSize(n int) Option {
+	return func(cfg *config) {
+		cfg.maxSize = n
+	}
+}
+
+// VisualizeReader reads all of r and lays its bytes out along a Hilbert curve, one pixel per cell,
+// so that bytes close together in the stream stay close together in the image, colored as set by
+// WithColoring. The curve is the smallest which fits every byte, up to the size set by
+// WithMaxSize; longer streams are split into equal blocks of consecutive bytes, one per cell,
+// where ByteClass averages the colors of the block and Entropy measures the block as a whole.
+// Cells past the end of the stream are left as the background, set by WithBackground.
+func VisualizeReader(r io.Reader, opts ...Option) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := config{
+		background: color.White,
+		colors:     Grayscale,
+		maxSize:    defaultMaxSize,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	n := 1
+	for n*n < len(data) && 2*n <= cfg.maxSize {
+		n *= 2
+	}
+	curve, _ := hilbert.NewHilbert(n, false)
+	block := max((len(data)+n*n-1)/(n*n), 1)
+
+	img := image.NewRGBA(image.Rect(0, 0, n, n))
+	imagedraw.Draw(img, img.Bounds(), image.NewUniform(cfg.background), image.Point{}, imagedraw.Src)
+	for t := 0; t*block < len(data); t++ {
+		lo, hi := t*block, min((t+1)*block, len(data))
+
+		var c color.RGBA
+		switch cfg.coloring {
+		case Entropy:
+			// Small blocks are widened, around their center, to give a meaningful measure.
+			if hi-lo < entropyWindow {
+				lo = max(min((lo+hi-entropyWindow)/2, len(data)-entropyWindow), 0)
+				hi = min(lo+entropyWindow, len(data))
+			}
+			e := entropy(data[lo:hi])
+			c = color.RGBAModel.Convert(cfg.colors(int(math.Round(e*(heatmapLevels-1))), heatmapLevels)).(color.RGBA)
+		default:
+			c = classColor(data[lo:hi])
+		}
+
+		x, y := curve.MustMap(t)
+		img.SetRGBA(x, y, c)
+	}
+	return img, nil
+}
+
+// byteClassColor returns the color of b for ByteClass.
+func byteClassColor(b byte) color.RGBA {
+	switch {
+	case b == 0:
+		return zeroColor
+	case b == 0xff:
+		return maxColor
+	case b >= 0x20 && b < 0x7f:
+		return printableColor
+	case b < 0x80:
+		return controlColor
+	}
+	return otherColor
+}
+
+// classColor returns the average color, for ByteClass, of the bytes in block.
+func classColor(block []byte) color.RGBA {
+	if len(block) == 1 {
+		return byteClassColor(block[0])
+	}
+
+	var r, g, b int
+	for _, v := range block {
+		c := byteClassColor(v)
+		r, g, b = r+int(c.R), g+int(c.G), b+int(c.B)
+	}
+	n := len(block)
+	return color.RGBA{uint8((r + n/2) / n), uint8((g + n/2) / n), uint8((b + n/2) / n), 0xff}
+}
+
+// entropy returns the Shannon entropy of the bytes in block, divided by the largest possible for
+// a block of its length, so it is in [0, 1].
+func entropy(block []byte) float64 {
+	if len(block) < 2 {
+		return 0
+	}
+
+	var counts [256]int
+	for _, v := range block {
+		counts[v]++
+	}
+	h := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(block))
+			h -= p * math.Log2(p)
+		}
+	}
+	return h / math.Log2(math.Min(float64(len(block)), 256))
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package draw
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+	"testing/iotest"
+
+	"github.com/google/hilbert"
+)
+
+func TestVisualizeReaderByteClass(t *testing.T) {
+	img, err := VisualizeReader(bytes.NewReader([]byte{0, 0xff, 'A', '\n', 0x80}))
+	if err != nil {
+		t.Fatalf("VisualizeReader() returned error: %s", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 4, 4); got != want {
+		t.Fatalf("VisualizeReader().Bounds() = %v want %v", got, want)
+	}
+
+	s, _ := hilbert.NewHilbert(4, false)
+	want := []color.Color{zeroColor, maxColor, printableColor, controlColor, otherColor, color.White}
+	for d, w := range want {
+		x, y, _ := s.Map(d)
+		if c := img.At(x, y); !same(c, w) {
+			t.Errorf("VisualizeReader() cell %d = %v want %v", d, c, w)
+		}
+	}
+}
+
+func TestVisualizeReaderBlocks(t *testing.T) {
+	// Three bytes in a single cell are averaged.
+	img, err := VisualizeReader(bytes.NewReader([]byte{0, 0xff, 0xff}), WithMaxSize(1))
+	if err != nil {
+		t.Fatalf("VisualizeReader() returned error: %s", err)
+	}
+	if got, want := img.Bounds(), image.Rect(0, 0, 1, 1); got != want {
+		t.Fatalf("VisualizeReader().Bounds() = %v want %v", got, want)
+	}
+	if c, want := img.At(0, 0), (color.RGBA{0xaa, 0xaa, 0xaa, 0xff}); !same(c, want) {
+		t.Errorf("VisualizeReader() = %v want %v", c, want)
+	}
+
+	// The size is rounded down to a power of two, and 9 bytes over 4 cells makes blocks of 3.
+	img, _ = VisualizeReader(bytes.NewReader(make([]byte, 9)), WithMaxSize(3), WithBackground(color.Transparent))
+	if got, want := img.Bounds(), image.Rect(0, 0, 2, 2); got != want {
+		t.Fatalf("VisualizeReader(WithMaxSize(3)).Bounds() = %v want %v", got, want)
+	}
+	s, _ := hilbert.NewHilbert(2, false)
+	for d, w := range []color.Color{zeroColor, zeroColor, zeroColor, color.Transparent} {
+		x, y, _ := s.Map(d)
+		if c := img.At(x, y); !same(c, w) {
+			t.Errorf("VisualizeReader(WithMaxSize(3)) cell %d = %v want %v", d, c, w)
+		}
+	}
+}
+
+func TestVisualizeReaderEntropy(t *testing.T) {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+
+	testCases := []struct {
+		name string
+		data []byte
+		want color.Color
+	}{
+		{"zeros", make([]byte, 64), color.Gray{0}},
+		{"every byte", all, color.Gray{255}},
+		{"two values", bytes.Repeat([]byte{1, 2}, 128), color.Gray{32}},
+	}
+	for _, tc := range testCases {
+		img, err := VisualizeReader(bytes.NewReader(tc.data), WithColoring(Entropy), WithMaxSize(1))
+		if err != nil {
+			t.Fatalf("VisualizeReader(%s) returned error: %s", tc.name, err)
+		}
+		if c := img.At(0, 0); !same(c, tc.want) {
+			t.Errorf("VisualizeReader(%s) = %v want %v", tc.name, c, tc.want)
+		}
+	}
+
+	// Each byte on its own is measured with the bytes around it.
+	img, _ := VisualizeReader(bytes.NewReader(all[:64]), WithColoring(Entropy), WithColors(Rainbow))
+	if c, want := img.At(0, 0), Rainbow(heatmapLevels-1, heatmapLevels); !same(c, want) {
+		t.Errorf("VisualizeReader(64 distinct bytes) first cell = %v want %v", c, want)
+	}
+}
+
+func TestVisualizeReaderError(t *testing.T) {
+	want := errors.New("read failed")
+	if _, err := VisualizeReader(iotest.ErrReader(want)); err != want {
+		t.Errorf("VisualizeReader() error = %v want %v", err, want)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	testCases := []struct {
+		block []byte
+		want  float64
+	}{
+		{nil, 0},
+		{[]byte{7}, 0},
+		{[]byte{1, 2}, 1},
+		{[]byte{1, 1, 2, 2}, 0.5},
+		{[]byte{1, 2, 3, 4}, 1},
+	}
+	for _, tc := range testCases {
+		if got := entropy(tc.block); math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("entropy(%v) = %v want %v", tc.block, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Edge is one of the four edges of a square space. As with images, y increases downwards, so
+// the top edge is y = 0.
+type Edge int
+
+// Valid edges, in clockwise order.
+const (
+	EdgeTop Edge = iota
+	EdgeRight
+	EdgeBottom
+	EdgeLeft
+)
+
+var edgeNames = [...]string{"top", "right", "bottom", "left"}
+
+// String returns the lower case name of the edge, e.g. "top".
+func (e Edge) String() string {
+	if e < 0 || int(e) >= len(edgeNames) {
+		return "unknown"
+	}
+	return edgeNames[e]
+}
+
+// ConnectingOrientation returns the orientation, and if the curve should be reversed, for a tile
+// whose curve continues from a neighbouring tile of the same size and layout, joined along the
+// given edge of this tile. The returned curve's Map(0) is on the edge, adjacent to the last cell
+// of the neighbour's curve. Horizontal curves connect along their left edge, and vertical curves
+// along their top edge, with reversed curves connecting along the opposite edges. ErrOutOfRange
+// is returned for an unknown edge.
+func ConnectingOrientation(edge Edge) (o Orientation, reversed bool, err error) {
+	switch edge {
+	case EdgeTop:
+		return Vertical, false, nil
+	case EdgeRight:
+		return Horizontal, true, nil
+	case EdgeBottom:
+		return Vertical, true, nil
+	case EdgeLeft:
+		return Horizontal, false, nil
+	}
+	return Horizontal, false, ErrOutOfRange
+}
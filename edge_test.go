@@ -0,0 +1,72 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestConnectingOrientation(t *testing.T) {
+	// The offset of the neighbouring tile across each edge.
+	offsets := map[Edge][2]int{
+		EdgeTop:    {0, -1},
+		EdgeRight:  {1, 0},
+		EdgeBottom: {0, 1},
+		EdgeLeft:   {-1, 0},
+	}
+
+	for _, n := range []int{2, 4, 16} {
+		for edge, offset := range offsets {
+			o, reversed, err := ConnectingOrientation(edge)
+			if err != nil {
+				t.Errorf("ConnectingOrientation(%s) failed: %s", edge, err)
+				continue
+			}
+
+			s, _ := NewHilbert(n, o == Vertical)
+			if reversed {
+				s = s.Reversed()
+			}
+
+			sx, sy, ex, ey := s.Endpoints()
+			ex, ey = ex+offset[0]*n, ey+offset[1]*n // Last cell of the neighbour
+			if headingBetween(ex, ey, sx, sy) == HeadingNone {
+				t.Errorf("ConnectingOrientation(%s) = (%s, %t) starts at (%d, %d) not adjacent to (%d, %d)", edge, o, reversed, sx, sy, ex, ey)
+			}
+		}
+	}
+
+	if _, _, err := ConnectingOrientation(Edge(4)); err != ErrOutOfRange {
+		t.Errorf("ConnectingOrientation(4) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestEdgeString(t *testing.T) {
+	testCases := []struct {
+		e    Edge
+		want string
+	}{
+		{EdgeTop, "top"},
+		{EdgeRight, "right"},
+		{EdgeBottom, "bottom"},
+		{EdgeLeft, "left"},
+		{Edge(-1), "unknown"},
+		{Edge(4), "unknown"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.e.String(); got != tc.want {
+			t.Errorf("Edge(%d).String() = %q want %q", tc.e, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// MaxOrder64 is the largest order supported by Encode2D and Decode2D, where the values on the
+// curve fill a uint64.
+const MaxOrder64 = 32
+
+// checkOrder panics if order is not in the range supported by Encode2D and Decode2D.
+func checkOrder(order int) {
+	if order < 0 || order > MaxOrder64 {
+		panic("hilbert: order must be in the range [0, 32]")
+	}
+}
+
+// Encode2D returns the value on the Hilbert curve of the given order for the cell (x,y), the same
+// as NewHilbert(1<<order, false).MapInverse(x, y), without needing to create a Hilbert or check
+// for errors. order must be in the range [0, 32], and bits of x and y above order are ignored.
+func Encode2D(x, y uint32, order int) uint64 {
+	checkOrder(order)
+	if bitwiseCore && order >= bitwiseMinOrder {
+		return encodeBits(x, y, uint(order))
+	}
+
+	var h uint64
+	state := uint8(0)
+	for shift := uint(order); shift > 0; shift-- {
+		e := inverseStates[state<<2|uint8(x>>(shift-1)&1)<<1|uint8(y>>(shift-1)&1)]
+		h = h<<2 | uint64(e>>2)
+		state = e & 3
+	}
+	return h
+}
+
+// Decode2D is the inverse of Encode2D, returning the cell for the value h on the Hilbert curve of
+// the given order. order must be in the range [0, 32], and bits of h above 2*order are ignored.
+func Decode2D(h uint64, order int) (x, y uint32) {
+	checkOrder(order)
+	if bitwiseCore && order >= bitwiseMinOrder {
+		return decodeBits(h, uint(order))
+	}
+
+	state := uint8(0)
+	for shift := uint(2 * order); shift > 0; shift -= 2 {
+		e := forwardStates[state<<2|uint8(h>>(shift-2)&3)]
+		x = x<<1 | uint32(e>>3)
+		y = y<<1 | uint32(e>>2&1)
+		state = e & 3
+	}
+	return x, y
+}
+
+// Hilbert64 returns the value on the order 32 Hilbert curve, which covers every uint32 x and y,
+// for the cell (x,y). It is the same as Encode2D(x, y, 32), but skips checking the order, and
+// steps two levels at a time where the levels are walked.
+func Hilbert64(x, y uint32) uint64 {
+	if bitwiseCore {
+		return encodeBits(x, y, 32)
+	}
+
+	var h uint64
+	state := uint(0)
+	for shift := 30; shift >= 0; shift -= 2 {
+		e := inverseStates2[(state<<4|uint(x>>uint(shift)&3)<<2|uint(y>>uint(shift)&3))&63]
+		h = h<<4 | uint64(e>>2)
+		state = uint(e & 3)
+	}
+	return h
+}
+
+// Point64 is the inverse of Hilbert64, returning the cell for the value h on the order 32 Hilbert
+// curve.
+func Point64(h uint64) (x, y uint32) {
+	if bitwiseCore {
+		return decodeBits(h, 32)
+	}
+
+	state := uint(0)
+	for shift := 60; shift >= 0; shift -= 4 {
+		e := forwardStates2[(state<<4|uint(h>>uint(shift)&15))&63]
+		x = x<<2 | uint32(e>>4)
+		y = y<<2 | uint32(e>>2&3)
+		state = uint(e & 3)
+	}
+	return x, y
+}
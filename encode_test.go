@@ -0,0 +1,150 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestForwardStates(t *testing.T) {
+	for state := uint8(0); state < 4; state++ {
+		for q := uint8(0); q < 4; q++ {
+			e := inverseStates[state<<2|q]
+			want := q<<2 | e&3
+			if got := forwardStates[state<<2|e>>2]; got != want {
+				t.Errorf("forwardStates[%d] = %d want %d", state<<2|e>>2, got, want)
+			}
+		}
+	}
+}
+
+func TestEncode2DMatchesHilbert(t *testing.T) {
+	for order := 0; order <= 6; order++ {
+		s, err := NewHilbert(1<<uint(order), false)
+		if err != nil {
+			t.Fatalf("NewHilbert(%d) failed: %s", 1<<uint(order), err)
+		}
+
+		for d := 0; d < s.N*s.N; d++ {
+			wantX, wantY, _ := s.Map(d)
+			x, y := Decode2D(uint64(d), order)
+			if int(x) != wantX || int(y) != wantY {
+				t.Errorf("Decode2D(%d, %d) = (%d, %d) want (%d, %d)", d, order, x, y, wantX, wantY)
+			}
+			if got := Encode2D(x, y, order); got != uint64(d) {
+				t.Errorf("Encode2D(%d, %d, %d) = %d want %d", x, y, order, got, d)
+			}
+		}
+	}
+}
+
+func TestEncode2DLargeOrders(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, order := range []int{16, 31, 32} {
+		b, err := NewHilbertBig(order, false)
+		if err != nil {
+			t.Fatalf("NewHilbertBig(%d) failed: %s", order, err)
+		}
+		mask := uint32(1<<uint(order) - 1)
+
+		for i := 0; i < 100; i++ {
+			x, y := r.Uint32()&mask, r.Uint32()&mask
+			h := Encode2D(x, y, order)
+
+			want, _ := b.MapInverseBig(bigUint(uint64(x)), bigUint(uint64(y)))
+			if want.Uint64() != h {
+				t.Errorf("Encode2D(%d, %d, %d) = %d want %v", x, y, order, h, want)
+			}
+			if gotX, gotY := Decode2D(h, order); gotX != x || gotY != y {
+				t.Errorf("Decode2D(%d, %d) = (%d, %d) want (%d, %d)", h, order, gotX, gotY, x, y)
+			}
+		}
+	}
+}
+
+func TestEncode2DPanics(t *testing.T) {
+	for _, order := range []int{-1, 33} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Encode2D(0, 0, %d) did not panic", order)
+				}
+			}()
+			Encode2D(0, 0, order)
+		}()
+	}
+}
+
+func TestHilbert64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	points := [][2]uint32{{0, 0}, {0, math.MaxUint32}, {math.MaxUint32, 0}, {math.MaxUint32, math.MaxUint32}}
+	for i := 0; i < 1000; i++ {
+		points = append(points, [2]uint32{r.Uint32(), r.Uint32()})
+	}
+	for _, p := range points {
+		h := Hilbert64(p[0], p[1])
+		if want := Encode2D(p[0], p[1], 32); h != want {
+			t.Errorf("Hilbert64(%d, %d) = %d want %d", p[0], p[1], h, want)
+		}
+		if x, y := Point64(h); x != p[0] || y != p[1] {
+			t.Errorf("Point64(%d) = (%d, %d) want (%d, %d)", h, x, y, p[0], p[1])
+		}
+	}
+
+	if x, y := Point64(math.MaxUint64); x != math.MaxUint32 || y != 0 {
+		t.Errorf("Point64(MaxUint64) = (%d, %d) want (%d, 0)", x, y, uint32(math.MaxUint32))
+	}
+}
+
+func FuzzHilbert64(f *testing.F) {
+	f.Add(uint32(0), uint32(0), uint64(0))
+	f.Add(uint32(math.MaxUint32), uint32(math.MaxUint32), uint64(math.MaxUint64))
+	f.Fuzz(func(t *testing.T, x, y uint32, h uint64) {
+		if gotX, gotY := Point64(Hilbert64(x, y)); gotX != x || gotY != y {
+			t.Errorf("Point64(Hilbert64(%d, %d)) = (%d, %d)", x, y, gotX, gotY)
+		}
+		if x, y := Point64(h); Hilbert64(x, y) != h {
+			t.Errorf("Hilbert64(Point64(%d)) = %d", h, Hilbert64(x, y))
+		}
+	})
+}
+
+func BenchmarkEncode2D(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for x := uint32(0); x < benchmarkN; x++ {
+			for y := uint32(0); y < benchmarkN; y++ {
+				Encode2D(x, y, 5)
+			}
+		}
+	}
+}
+
+func BenchmarkDecode2D(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for d := uint64(0); d < benchmarkN*benchmarkN; d++ {
+			Decode2D(d, 5)
+		}
+	}
+}
+
+func BenchmarkHilbert64(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Hilbert64(uint32(i), uint32(i)*2654435761)
+	}
+}
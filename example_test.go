@@ -16,6 +16,7 @@ package hilbert_test
 
 import (
 	"fmt"
+	"math/big"
 
 	"github.com/google/hilbert"
 )
@@ -23,7 +24,7 @@ import (
 func Example() {
 
 	// Create a Hilbert curve for mapping to and from a 16 by 16 space.
-	s, _ := hilbert.NewHilbert(16)
+	s, _ := hilbert.NewHilbert(16, false)
 
 	// Create a Peano curve for mapping to and from a 27 by 27 space.
 	//s, _ := hilbert.NewPeano(27)
@@ -40,3 +41,32 @@ func Example() {
 	// Output:
 	// x = 4, y = 12, t = 96
 }
+
+func ExampleSpaceFilling() {
+	h, _ := hilbert.NewHilbert(4, false)
+	p, _ := hilbert.NewPeano(3)
+
+	// Code written against SpaceFilling works with any of the curves.
+	for _, s := range []hilbert.SpaceFilling{h, p} {
+		x, y, _ := s.Map(2)
+		fmt.Printf("%s: t = 2 is at (%d,%d)\n", hilbert.DescribeCurve(s), x, y)
+	}
+
+	// Output:
+	// Hilbert 4x4 horizontal: t = 2 is at (1,1)
+	// Peano 3x3: t = 2 is at (0,2)
+}
+
+func ExampleHilbertBig() {
+	// An order 64 curve covers a 2^64 by 2^64 space, so maps every 128-bit key.
+	s, _ := hilbert.NewHilbertBig(64, false)
+
+	key, _ := new(big.Int).SetString("ffffffffffffffffffffffffffffffff", 16)
+	x, y, _ := s.MapBig(key)
+	t, _ := s.MapInverseBig(x, y)
+
+	fmt.Printf("x = %x, y = %x, t = %x\n", x, y, t)
+
+	// Output:
+	// x = ffffffffffffffff, y = 0, t = ffffffffffffffffffffffffffffffff
+}
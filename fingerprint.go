@@ -0,0 +1,55 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+)
+
+// fingerprint returns the hex encoded 64-bit FNV-1a hash of the curve type and its parameters.
+// The parameters are hashed as fixed-width big-endian values, so the result does not depend on
+// the architecture.
+func fingerprint(kind string, params ...uint64) string {
+	h := fnv.New64a()
+	h.Write([]byte(kind))
+	var buf [8]byte
+	for _, p := range params {
+		binary.BigEndian.PutUint64(buf[:], p)
+		h.Write(buf[:])
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Fingerprint returns a short string identifying the configuration of the curve, suitable for use
+// as a cache key. Curves which map every value to the same cell have the same fingerprint, however
+// they were configured, such as NewHilbert(n, true) and the horizontal curve moved onto it by
+// WithTransform. The fingerprint is stable across runs and architectures.
+func (s *Hilbert) Fingerprint() string {
+	layout, reversed := s.canonicalLayout()
+	orientation := uint64(layout & 1)
+	if layout > 1 {
+		return fingerprint("hilbert", uint64(s.N), orientation, uint64(b2i(reversed)), uint64(layout))
+	}
+	return fingerprint("hilbert", uint64(s.N), orientation, uint64(b2i(reversed)))
+}
+
+// Fingerprint returns a short string identifying the configuration of the curve, suitable for use
+// as a cache key. Curves with the same N have the same fingerprint, which is stable across runs
+// and architectures.
+func (p *Peano) Fingerprint() string {
+	return fingerprint("peano", uint64(p.N))
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestFingerprint(t *testing.T) {
+	h16, _ := NewHilbert(16, false)
+	h16b, _ := NewHilbert(16, false)
+	h16v, _ := NewHilbert(16, true)
+	h32, _ := NewHilbert(32, false)
+	p9, _ := NewPeano(9)
+	p9b, _ := NewPeano(9)
+	p27, _ := NewPeano(27)
+
+	// Pin the values, as they must be stable across releases.
+	if got, want := h16.Fingerprint(), "c09ead1d9b3128c9"; got != want {
+		t.Errorf("NewHilbert(16, false).Fingerprint() = %q want %q", got, want)
+	}
+	if got, want := p9.Fingerprint(), "980ff8b78c734acd"; got != want {
+		t.Errorf("NewPeano(9).Fingerprint() = %q want %q", got, want)
+	}
+
+	if h16.Fingerprint() != h16b.Fingerprint() || h16.Fingerprint() != h16.Reversed().Reversed().Fingerprint() {
+		t.Errorf("Fingerprint() differs for equivalent curves")
+	}
+	if p9.Fingerprint() != p9b.Fingerprint() {
+		t.Errorf("Fingerprint() differs for equivalent Peano curves")
+	}
+
+	distinct := []string{
+		h16.Fingerprint(),
+		h16v.Fingerprint(),
+		h16.Reversed().Fingerprint(),
+		h16v.Reversed().Fingerprint(),
+		h32.Fingerprint(),
+		p9.Fingerprint(),
+		p27.Fingerprint(),
+	}
+	seen := make(map[string]int)
+	for i, f := range distinct {
+		if j, ok := seen[f]; ok {
+			t.Errorf("Fingerprint() of curves %d and %d are both %q", j, i, f)
+		}
+		seen[f] = i
+	}
+}
+
+func TestFingerprintEquivalentLayouts(t *testing.T) {
+	for _, n := range []int{1, 2, 8, 16} {
+		// Group the 16 combinations of layout and direction by the cells they map to.
+		groups := make(map[string][]*Hilbert)
+		for _, reversed := range []bool{false, true} {
+			for _, mirror := range []bool{false, true} {
+				for rotation := 0; rotation < 4; rotation++ {
+					s, _ := NewHilbert(n, false, WithTransform(Transform{Mirror: mirror, Rotation: rotation, Reversed: reversed}))
+					var cells []byte
+					for d := 0; d < n*n; d++ {
+						x, y, _ := s.Map(d)
+						cells = append(cells, byte(x), byte(y))
+					}
+					groups[string(cells)] = append(groups[string(cells)], s)
+				}
+			}
+		}
+		want := 8
+		if n == 1 {
+			want = 1
+		}
+		if len(groups) != want {
+			t.Errorf("NewHilbert(%d) has %d distinct layouts want %d", n, len(groups), want)
+		}
+
+		fingerprints := make(map[string]bool)
+		descriptions := make(map[string]bool)
+		for _, group := range groups {
+			f, d := group[0].Fingerprint(), group[0].Describe()
+			for _, s := range group[1:] {
+				if s.Fingerprint() != f || s.Describe() != d {
+					t.Errorf("NewHilbert(%d) with transform %+v = (%q, %q) want (%q, %q) of the same curve with %+v",
+						n, s.Transform(), s.Fingerprint(), s.Describe(), f, d, group[0].Transform())
+				}
+			}
+			if fingerprints[f] || descriptions[d] {
+				t.Errorf("NewHilbert(%d) = (%q, %q) for different curves", n, f, d)
+			}
+			fingerprints[f], descriptions[d] = true, true
+		}
+	}
+
+	h, _ := NewHilbert(16, false, WithTransform(Transform{Mirror: true, Rotation: 3}))
+	v, _ := NewHilbert(16, true)
+	if h.Fingerprint() != v.Fingerprint() || h.Describe() != v.Describe() {
+		t.Errorf("NewHilbert(16, false, WithTransform(Transform{Mirror: true, Rotation: 3})) = (%q, %q) want (%q, %q)",
+			h.Fingerprint(), h.Describe(), v.Fingerprint(), v.Describe())
+	}
+	if d := v.Describe(); d != "Hilbert 16x16 vertical" {
+		t.Errorf("NewHilbert(16, true).Describe() = %q want %q", d, "Hilbert 16x16 vertical")
+	}
+	one, _ := NewHilbert(1, true, WithTransform(Transform{Rotation: 1}))
+	if d := one.Describe(); d != "Hilbert 1x1 horizontal" {
+		t.Errorf("NewHilbert(1, true).Describe() = %q want %q", d, "Hilbert 1x1 horizontal")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Generalized represents a generalized Hilbert curve, also known as "Gilbert", over a rectangle of
+// any width and height, using Jakub Červený's algorithm. For a square whose side is a power of two
+// it is the same as the horizontal Hilbert curve. Other sizes keep most of the locality, and every
+// step is to an adjacent cell, apart from a single diagonal step in some rectangles with an odd
+// width and an even height, or the other way around. Implements SpaceFilling interface.
+type Generalized struct {
+	W, H int // The width and height of the space.
+}
+
+// NewGeneralized returns a Generalized curve over a w by h space.
+func NewGeneralized(w, h int) (*Generalized, error) {
+	if w <= 0 || h <= 0 {
+		return nil, ErrNotPositive
+	}
+	if w > math.MaxInt/h {
+		return nil, ErrTooLarge
+	}
+	return &Generalized{W: w, H: h}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *Generalized) GetDimensions() (int, int) {
+	return s.W, s.H
+}
+
+// gilbertBlock is a rectangle visited by the curve, starting at (x,y). a is the major axis,
+// along which the curve travels across the block, and b is the minor axis. Each vector is along
+// the x or y axis, and has the length of that side of the block.
+type gilbertBlock struct {
+	x, y, ax, ay, bx, by int
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// size returns the length of the major and minor axes of the block.
+func (b gilbertBlock) size() (w, h int) {
+	return absInt(b.ax + b.ay), absInt(b.bx + b.by)
+}
+
+// contains returns true if the cell (x,y) is within the block.
+func (b gilbertBlock) contains(x, y int) bool {
+	x1 := b.x + b.ax - sign(b.ax) + b.bx - sign(b.bx)
+	y1 := b.y + b.ay - sign(b.ay) + b.by - sign(b.by)
+	return x >= min(b.x, x1) && x <= max(b.x, x1) && y >= min(b.y, y1) && y <= max(b.y, y1)
+}
+
+// split divides a block, which is at least 2 cells in both directions, into the two or three
+// blocks the curve visits in turn.
+func (b gilbertBlock) split(blocks *[3]gilbertBlock) []gilbertBlock {
+	w, h := b.size()
+	dax, day := sign(b.ax), sign(b.ay)
+	dbx, dby := sign(b.bx), sign(b.by)
+
+	// Halve the axes, rounding down, as >> does for negative values.
+	ax2, ay2 := b.ax>>1, b.ay>>1
+	bx2, by2 := b.bx>>1, b.by>>1
+	w2, h2 := absInt(ax2+ay2), absInt(bx2+by2)
+
+	if 2*w > 3*h {
+		// Long case, split in two along the major axis, preferring an even length first.
+		if w2%2 == 1 && w > 2 {
+			ax2, ay2 = ax2+dax, ay2+day
+		}
+		blocks[0] = gilbertBlock{b.x, b.y, ax2, ay2, b.bx, b.by}
+		blocks[1] = gilbertBlock{b.x + ax2, b.y + ay2, b.ax - ax2, b.ay - ay2, b.bx, b.by}
+		return blocks[:2]
+	}
+
+	// Standard case, one step up, one long step across and one step down.
+	if h2%2 == 1 && h > 2 {
+		bx2, by2 = bx2+dbx, by2+dby
+	}
+	blocks[0] = gilbertBlock{b.x, b.y, bx2, by2, ax2, ay2}
+	blocks[1] = gilbertBlock{b.x + bx2, b.y + by2, b.ax, b.ay, b.bx - bx2, b.by - by2}
+	blocks[2] = gilbertBlock{
+		b.x + (b.ax - dax) + (bx2 - dbx), b.y + (b.ay - day) + (by2 - dby),
+		-bx2, -by2, -(b.ax - ax2), -(b.ay - ay2),
+	}
+	return blocks[:3]
+}
+
+// root returns the block covering the whole space.
+func (s *Generalized) root() gilbertBlock {
+	if s.W >= s.H {
+		return gilbertBlock{0, 0, s.W, 0, 0, s.H}
+	}
+	return gilbertBlock{0, 0, 0, s.H, s.W, 0}
+}
+
+// Map transforms a one dimension value, t, in the range [0, W*H-1] to coordinates on the curve in
+// the two-dimension space, where x is within [0,W-1] and y is within [0,H-1].
+func (s *Generalized) Map(t int) (x, y int, err error) {
+	if t < 0 || t >= s.W*s.H {
+		return -1, -1, ErrOutOfRange
+	}
+
+	// Descend into the block containing t, until it is a single row or column.
+	var blocks [3]gilbertBlock
+	b := s.root()
+	for {
+		w, h := b.size()
+		if h == 1 {
+			return b.x + sign(b.ax)*t, b.y + sign(b.ay)*t, nil
+		}
+		if w == 1 {
+			return b.x + sign(b.bx)*t, b.y + sign(b.by)*t, nil
+		}
+
+		for _, sub := range b.split(&blocks) {
+			sw, sh := sub.size()
+			if t < sw*sh {
+				b = sub
+				break
+			}
+			t -= sw * sh
+		}
+	}
+}
+
+// MapInverse transform coordinates on the curve from (x,y) to t.
+func (s *Generalized) MapInverse(x, y int) (t int, err error) {
+	if x < 0 || x >= s.W || y < 0 || y >= s.H {
+		return -1, ErrOutOfRange
+	}
+
+	var blocks [3]gilbertBlock
+	b := s.root()
+	for {
+		w, h := b.size()
+		if h == 1 || w == 1 {
+			return t + absInt(x-b.x) + absInt(y-b.y), nil
+		}
+
+		for _, sub := range b.split(&blocks) {
+			if sub.contains(x, y) {
+				b = sub
+				break
+			}
+			sw, sh := sub.size()
+			t += sw * sh
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewGeneralizedErrors(t *testing.T) {
+	testCases := []struct {
+		w, h int
+		want error
+	}{
+		{0, 4, ErrNotPositive},
+		{4, -1, ErrNotPositive},
+		{math.MaxInt, 2, ErrTooLarge},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewGeneralized(tc.w, tc.h)
+		if s != nil || err != tc.want {
+			t.Errorf("NewGeneralized(%d, %d) = (%+v, %q) did not fail want (?, %q)", tc.w, tc.h, s, err, tc.want)
+		}
+	}
+}
+
+func TestGeneralizedAllMapValues(t *testing.T) {
+	for w := 1; w <= 20; w++ {
+		for h := 1; h <= 20; h++ {
+			s, err := NewGeneralized(w, h)
+			if err != nil {
+				t.Fatalf("NewGeneralized(%d, %d) failed: %s", w, h, err)
+			}
+
+			seen := make(map[[2]int]bool)
+			diagonals := 0
+			px, py := 0, 0
+			for d := 0; d < w*h; d++ {
+				x, y, err := s.Map(d)
+				if err != nil || x < 0 || x >= w || y < 0 || y >= h || seen[[2]int{x, y}] {
+					t.Fatalf("%dx%d Map(%d) = (%d, %d, %v) is out of range or repeated", w, h, d, x, y, err)
+				}
+				seen[[2]int{x, y}] = true
+
+				if got, err := s.MapInverse(x, y); err != nil || got != d {
+					t.Fatalf("%dx%d Failed Map(%d) -> MapInverse(%d, %d) -> %d", w, h, d, x, y, got)
+				}
+
+				// Every step is to an adjacent cell, or at most one diagonal step.
+				if dx, dy := absInt(x-px), absInt(y-py); d > 0 && dx+dy != 1 {
+					if dx != 1 || dy != 1 {
+						t.Errorf("%dx%d Map(%d) = (%d, %d) jumped from (%d, %d)", w, h, d, x, y, px, py)
+					}
+					diagonals++
+				}
+				px, py = x, y
+			}
+			if diagonals > 1 || (diagonals == 1 && w%2 == h%2) {
+				t.Errorf("%dx%d has %d diagonal steps", w, h, diagonals)
+			}
+		}
+	}
+}
+
+func TestGeneralizedMatchesHilbert(t *testing.T) {
+	for _, n := range []int{1, 2, 4, 8, 64} {
+		s, _ := NewGeneralized(n, n)
+		h, _ := NewHilbert(n, false)
+		for d := 0; d < n*n; d++ {
+			x, y, _ := s.Map(d)
+			wantX, wantY, _ := h.Map(d)
+			if x != wantX || y != wantY {
+				t.Errorf("N=%d Map(%d) = (%d, %d) want (%d, %d)", n, d, x, y, wantX, wantY)
+			}
+		}
+	}
+}
+
+func TestGeneralizedRangeErrors(t *testing.T) {
+	s, _ := NewGeneralized(7, 5)
+	for _, d := range []int{-1, 35} {
+		if _, _, err := s.Map(d); err != ErrOutOfRange {
+			t.Errorf("Map(%d) = %q want %q", d, err, ErrOutOfRange)
+		}
+	}
+	for _, p := range [][2]int{{-1, 0}, {0, -1}, {7, 0}, {0, 5}} {
+		if _, err := s.MapInverse(p[0], p[1]); err != ErrOutOfRange {
+			t.Errorf("MapInverse(%d, %d) = %q want %q", p[0], p[1], err, ErrOutOfRange)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"image"
+	"iter"
+)
+
+// Generator yields the cells of a Hilbert curve in order, the same as calling Map for each value
+// in turn, but carrying state between calls so each step takes constant amortized time, instead
+// of time proportional to the order of the curve. It uses memory proportional to the order, and
+// is not safe for concurrent use.
+type Generator struct {
+	order    int
+	reversed bool
+	started  bool
+	done     bool
+
+	digits []uint8 // Base-4 digits of the current value, most significant first
+	states []uint8 // states[i] is the state before digits[i], and the last is unused
+	x, y   int
+	mirror int // N-1 if the curve is mirrored, which x is xored with, or else 0
+}
+
+// Generator returns a new Generator for the curve, starting at t = 0.
+func (s *Hilbert) Generator() *Generator {
+	order := s.GetOrder()
+	g := &Generator{
+		order:    order,
+		reversed: s.reversed,
+		digits:   make([]uint8, order),
+		states:   make([]uint8, order+1),
+	}
+	g.states[0] = s.startState
+	if s.mirrored {
+		g.mirror = s.N - 1
+	}
+	if s.reversed {
+		for i := range g.digits {
+			g.digits[i] = 3
+		}
+	}
+	return g
+}
+
+// Next returns the coordinates of the next cell on the curve, or false once every cell has been
+// returned.
+func (g *Generator) Next() (x, y int, ok bool) {
+	if g.done {
+		return -1, -1, false
+	}
+	if !g.started {
+		g.started = true
+		g.update(0)
+		return g.x ^ g.mirror, g.y, true
+	}
+
+	// Step the digits, counting up, or down if the curve is reversed, and only recompute the
+	// levels which changed. Three quarters of steps only change the last digit.
+	last, step := uint8(3), uint8(1)
+	if g.reversed {
+		last, step = 0, 3 // Adding 3 mod 4 is the same as subtracting 1
+	}
+	i := g.order - 1
+	for i >= 0 && g.digits[i] == last {
+		g.digits[i] = (g.digits[i] + step) & 3
+		i--
+	}
+	if i < 0 {
+		g.done = true
+		return -1, -1, false
+	}
+	g.digits[i] = (g.digits[i] + step) & 3
+	g.update(i)
+	return g.x ^ g.mirror, g.y, true
+}
+
+// update recomputes the coordinates from the digits, starting at level i.
+func (g *Generator) update(i int) {
+	for ; i < g.order; i++ {
+		e := forwardStates[g.states[i]<<2|g.digits[i]]
+		bit := uint(g.order - 1 - i)
+		g.x = g.x&^(1<<bit) | int(e>>3)<<bit
+		g.y = g.y&^(1<<bit) | int(e>>2&1)<<bit
+		g.states[i+1] = e & 3
+	}
+}
+
+// All returns an iterator over every cell of the curve, yielding t and the coordinates of the
+// cell in curve order. It uses a Generator, so each step takes constant amortized time.
+func (s *Hilbert) All() iter.Seq2[int, image.Point] {
+	return func(yield func(int, image.Point) bool) {
+		g := s.Generator()
+		for t := 0; ; t++ {
+			x, y, ok := g.Next()
+			if !ok || !yield(t, image.Point{x, y}) {
+				return
+			}
+		}
+	}
+}
+
+// AllPoints is like Hilbert.All, but for any curve. Curves with an All method of their own are
+// iterated with it, and others by calling Map for each value in turn.
+func AllPoints(c SpaceFilling) iter.Seq2[int, image.Point] {
+	if a, ok := c.(interface {
+		All() iter.Seq2[int, image.Point]
+	}); ok {
+		return a.All()
+	}
+	return func(yield func(int, image.Point) bool) {
+		w, h := c.GetDimensions()
+		for t := 0; t < w*h; t++ {
+			x, y, err := c.Map(t)
+			if err != nil || !yield(t, image.Point{x, y}) {
+				return
+			}
+		}
+	}
+}
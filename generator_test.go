@@ -0,0 +1,116 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"image"
+	"testing"
+)
+
+func TestGenerator(t *testing.T) {
+	for n := 1; n <= 64; n *= 2 {
+		h, _ := NewHilbert(n, false)
+		v, _ := NewHilbert(n, true)
+		for _, s := range []*Hilbert{h, h.Reversed(), v, v.Reversed()} {
+			g := s.Generator()
+			for d := 0; d < n*n; d++ {
+				wantX, wantY, _ := s.Map(d)
+				x, y, ok := g.Next()
+				if !ok || x != wantX || y != wantY {
+					t.Errorf("%s Next() at %d = (%d, %d, %t) want (%d, %d, true)", s.Fingerprint(), d, x, y, ok, wantX, wantY)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				if x, y, ok := g.Next(); ok {
+					t.Errorf("%s Next() after the end = (%d, %d, %t) want (-1, -1, false)", s.Fingerprint(), x, y, ok)
+				}
+			}
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(8, true)
+	for _, s := range []*Hilbert{h, v.Reversed()} {
+		want := 0
+		for d, p := range s.All() {
+			x, y, _ := s.Map(want)
+			if d != want || p != (image.Point{x, y}) {
+				t.Errorf("%s All() yielded (%d, %v) want (%d, (%d,%d))", s.Fingerprint(), d, p, want, x, y)
+			}
+			want++
+		}
+		if want != s.N*s.N {
+			t.Errorf("%s All() yielded %d cells want %d", s.Fingerprint(), want, s.N*s.N)
+		}
+	}
+
+	// Stopping early must not yield any more cells.
+	count := 0
+	for d := range h.All() {
+		count++
+		if d == 9 {
+			break
+		}
+	}
+	if count != 10 {
+		t.Errorf("All() yielded %d cells before break want 10", count)
+	}
+}
+
+func TestAllPoints(t *testing.T) {
+	h, _ := NewHilbert(4, false)
+	p, _ := NewPeano(9)
+	for _, s := range []SpaceFilling{h, p} {
+		w, hgt := s.GetDimensions()
+		want := 0
+		for d, pt := range AllPoints(s) {
+			x, y, _ := s.Map(want)
+			if d != want || pt != (image.Point{x, y}) {
+				t.Errorf("AllPoints(%s) yielded (%d, %v) want (%d, (%d,%d))", DescribeCurve(s), d, pt, want, x, y)
+			}
+			want++
+		}
+		if want != w*hgt {
+			t.Errorf("AllPoints(%s) yielded %d cells want %d", DescribeCurve(s), want, w*hgt)
+		}
+	}
+}
+
+func BenchmarkAll(b *testing.B) {
+	s, err := NewHilbert(benchmarkN, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range s.All() {
+		}
+	}
+}
+
+func BenchmarkGenerator(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s, err := NewHilbert(benchmarkN, false)
+		if err != nil {
+			b.Fatalf("Failed to create hibert space: %s", err)
+		}
+		g := s.Generator()
+		for _, _, ok := g.Next(); ok; _, _, ok = g.Next() {
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Projection is how a Geo maps latitude and longitude onto the space of its curve.
+type Projection int
+
+// Supported projections.
+const (
+	// PlateCarree maps latitude and longitude linearly, so every cell covers the same number of
+	// degrees.
+	PlateCarree Projection = iota
+
+	// WebMercator uses the spherical Mercator projection of web maps, which covers latitudes
+	// within MaxMercatorLatitude of the equator, so the space is square.
+	WebMercator
+)
+
+// MaxMercatorLatitude is the largest latitude, in degrees, which WebMercator can project.
+const MaxMercatorLatitude = 85.05112877980659
+
+var projectionNames = [...]string{"plate carrée", "web mercator"}
+
+// String returns the lower case name of the projection, e.g. "web mercator".
+func (p Projection) String() string {
+	if p < 0 || int(p) >= len(projectionNames) {
+		return "unknown"
+	}
+	return projectionNames[p]
+}
+
+// Geo maps WGS84 latitudes and longitudes, in degrees, to and from a horizontal Hilbert curve, as
+// a replacement for geohash with better locality. Longitude increases with x, from -180 at x = 0,
+// and latitude increases with y, from the most southern latitude at y = 0.
+type Geo struct {
+	curve      *Hilbert
+	projection Projection
+}
+
+// NewGeo returns a new Geo using a curve of the given order, so the world is divided into
+// 2^order by 2^order cells. ErrNegativeOrder is returned if order is negative, ErrTooLarge if it
+// is more than MaxOrder, and ErrOutOfRange if the projection is not supported.
+func NewGeo(order int, projection Projection) (*Geo, error) {
+	if order < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if order > MaxOrder {
+		return nil, ErrTooLarge
+	}
+	if projection != PlateCarree && projection != WebMercator {
+		return nil, ErrOutOfRange
+	}
+
+	curve, _ := NewHilbert(1<<uint(order), false)
+	return &Geo{curve: curve, projection: projection}, nil
+}
+
+// Curve returns the Hilbert curve the positions are mapped onto.
+func (g *Geo) Curve() *Hilbert {
+	return g.curve
+}
+
+// Index returns the value on the curve of the cell containing the position. Longitude 180 and the
+// most northern latitude are in the last column and row of cells. ErrNotFinite is returned if the
+// position is not finite, and ErrOutOfRange if it is not within the projection.
+func (g *Geo) Index(lat, lng float64) (int, error) {
+	if math.IsNaN(lat) || math.IsInf(lat, 0) || math.IsNaN(lng) || math.IsInf(lng, 0) {
+		return -1, ErrNotFinite
+	}
+	maxLat := 90.0
+	if g.projection == WebMercator {
+		maxLat = MaxMercatorLatitude
+	}
+	if lat < -maxLat || lat > maxLat || lng < -180 || lng > 180 {
+		return -1, ErrOutOfRange
+	}
+
+	// Clamp, as positions on the maximum edges, or rounding, give N.
+	n := float64(g.curve.N)
+	x := int(math.Min(math.Floor((lng+180)/360*n), n-1))
+	y := int(math.Min(math.Max(math.Floor(g.project(lat)*n), 0), n-1))
+	return g.curve.MapInverse(x, y)
+}
+
+// Bounds returns the bounding box, in degrees, of the cell at t on the curve.
+func (g *Geo) Bounds(t int) (minLat, minLng, maxLat, maxLng float64, err error) {
+	x, y, err := g.curve.Map(t)
+	if err != nil {
+		return math.NaN(), math.NaN(), math.NaN(), math.NaN(), err
+	}
+
+	n := float64(g.curve.N)
+	minLng, maxLng = float64(x)/n*360-180, float64(x+1)/n*360-180
+	minLat, maxLat = g.unproject(float64(y)/n), g.unproject(float64(y+1)/n)
+	return minLat, minLng, maxLat, maxLng, nil
+}
+
+// project returns the position of lat within [0, 1] from south to north.
+func (g *Geo) project(lat float64) float64 {
+	if g.projection == WebMercator {
+		phi := lat * math.Pi / 180
+		return 0.5 + math.Log(math.Tan(math.Pi/4+phi/2))/(2*math.Pi)
+	}
+	return (lat + 90) / 180
+}
+
+// unproject is the inverse of project.
+func (g *Geo) unproject(v float64) float64 {
+	if g.projection == WebMercator {
+		return math.Atan(math.Sinh(2*math.Pi*(v-0.5))) * 180 / math.Pi
+	}
+	return v*180 - 90
+}
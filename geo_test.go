@@ -0,0 +1,121 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGeo(t *testing.T) {
+	for _, p := range []Projection{PlateCarree, WebMercator} {
+		g, err := NewGeo(6, p)
+		if err != nil {
+			t.Fatalf("NewGeo(6, %s) returned error: %s", p, err)
+		}
+
+		// Every cell's bounds must tile the world, and the centre of each must map back to it.
+		n := g.Curve().N
+		area := 0.0
+		for d := 0; d < n*n; d++ {
+			minLat, minLng, maxLat, maxLng, err := g.Bounds(d)
+			if err != nil {
+				t.Fatalf("%s Bounds(%d) returned error: %s", p, d, err)
+			}
+			area += (maxLng - minLng) * (g.project(maxLat) - g.project(minLat))
+			if got, err := g.Index((minLat+maxLat)/2, (minLng+maxLng)/2); err != nil || got != d {
+				t.Errorf("%s Index(centre of Bounds(%d)) = (%d, %v) want (%d, nil)", p, d, got, err, d)
+			}
+			// The Mercator projection is not exact enough to map the corner back to the same cell.
+			if got, _ := g.Index(minLat, minLng); p == PlateCarree && got != d {
+				t.Errorf("%s Index(%f, %f) = %d want %d, the cell with that minimum corner", p, minLat, minLng, got, d)
+			}
+		}
+		if math.Abs(area-360) > 1e-9 {
+			t.Errorf("%s cells cover %f want 360", p, area)
+		}
+	}
+}
+
+func TestGeoIndex(t *testing.T) {
+	pc, _ := NewGeo(1, PlateCarree)
+	wm, _ := NewGeo(1, WebMercator)
+
+	testCases := []struct {
+		g        *Geo
+		lat, lng float64
+		want     int
+	}{
+		{pc, -90, -180, 0},
+		{pc, 45, -90, 1},
+		{pc, 0, 0, 2},
+		{pc, 90, 180, 2},
+		{pc, -45, 90, 3},
+		{wm, MaxMercatorLatitude, 180, 2},
+		{wm, -MaxMercatorLatitude, -180, 0},
+	}
+	for _, tc := range testCases {
+		if got, err := tc.g.Index(tc.lat, tc.lng); err != nil || got != tc.want {
+			t.Errorf("%s Index(%f, %f) = (%d, %v) want (%d, nil)", tc.g.projection, tc.lat, tc.lng, got, err, tc.want)
+		}
+	}
+
+	minLat, minLng, maxLat, maxLng, _ := wm.Bounds(2)
+	if minLat != 0 || minLng != 0 || math.Abs(maxLat-MaxMercatorLatitude) > 1e-9 || maxLng != 180 {
+		t.Errorf("WebMercator Bounds(2) = (%f, %f, %f, %f) want (0, 0, %f, 180)", minLat, minLng, maxLat, maxLng, MaxMercatorLatitude)
+	}
+}
+
+func TestGeoErrors(t *testing.T) {
+	if _, err := NewGeo(-1, PlateCarree); err != ErrNegativeOrder {
+		t.Errorf("NewGeo(-1) = %v want %v", err, ErrNegativeOrder)
+	}
+	if _, err := NewGeo(MaxOrder+1, PlateCarree); err != ErrTooLarge {
+		t.Errorf("NewGeo(MaxOrder+1) = %v want %v", err, ErrTooLarge)
+	}
+	if _, err := NewGeo(4, Projection(2)); err != ErrOutOfRange {
+		t.Errorf("NewGeo(4, Projection(2)) = %v want %v", err, ErrOutOfRange)
+	}
+
+	pc, _ := NewGeo(4, PlateCarree)
+	wm, _ := NewGeo(4, WebMercator)
+	testCases := []struct {
+		g        *Geo
+		lat, lng float64
+		want     error
+	}{
+		{pc, math.NaN(), 0, ErrNotFinite},
+		{pc, 0, math.Inf(1), ErrNotFinite},
+		{pc, 90.5, 0, ErrOutOfRange},
+		{pc, 0, -180.5, ErrOutOfRange},
+		{wm, 86, 0, ErrOutOfRange},
+	}
+	for _, tc := range testCases {
+		if _, err := tc.g.Index(tc.lat, tc.lng); err != tc.want {
+			t.Errorf("%s Index(%f, %f) = %v want %v", tc.g.projection, tc.lat, tc.lng, err, tc.want)
+		}
+	}
+	if _, _, _, _, err := pc.Bounds(256); err != ErrOutOfRange {
+		t.Errorf("Bounds(256) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestProjectionString(t *testing.T) {
+	for p, want := range map[Projection]string{PlateCarree: "plate carrée", WebMercator: "web mercator", -1: "unknown"} {
+		if got := p.String(); got != want {
+			t.Errorf("Projection(%d).String() = %q want %q", p, got, want)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+)
+
+// ToGeoJSONPoints writes the curve to w as a GeoJSON FeatureCollection, with one Point feature
+// per cell in curve order. Each feature has a "t" property holding its value on the curve, and a
+// "heading" property holding the direction to the next cell. transform places the cell (x,y)
+// at a longitude and latitude, if nil the coordinates are used as is.
+//
+// The features are written as they are generated, so the whole collection is never held in
+// memory.
+func (s *Hilbert) ToGeoJSONPoints(w io.Writer, transform func(x, y int) (lon, lat float64)) error {
+	if transform == nil {
+		transform = func(x, y int) (float64, float64) {
+			return float64(x), float64(y)
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"type":"FeatureCollection","features":[`)
+
+	var buf []byte
+	total := s.N * s.N
+	progress := s.newProgress(total)
+	x, y, _ := s.Map(0)
+	for t := 0; t < total; t++ {
+		heading := HeadingNone
+		nx, ny := x, y
+		if t+1 < total {
+			nx, ny, _ = s.Map(t + 1)
+			heading = headingBetween(x, y, nx, ny)
+		}
+
+		lon, lat := transform(x, y)
+		if math.IsNaN(lon) || math.IsInf(lon, 0) || math.IsNaN(lat) || math.IsInf(lat, 0) {
+			return ErrNotFinite
+		}
+
+		buf = buf[:0]
+		if t > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `{"type":"Feature","geometry":{"type":"Point","coordinates":[`...)
+		buf = strconv.AppendFloat(buf, lon, 'g', -1, 64)
+		buf = append(buf, ',')
+		buf = strconv.AppendFloat(buf, lat, 'g', -1, 64)
+		buf = append(buf, `]},"properties":{"t":`...)
+		buf = strconv.AppendInt(buf, int64(t), 10)
+		buf = append(buf, `,"heading":"`...)
+		buf = append(buf, heading.String()...)
+		buf = append(buf, `"}}`...)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+
+		progress.report(t + 1)
+		x, y = nx, ny
+	}
+
+	bw.WriteString("]}\n")
+	return bw.Flush()
+}
@@ -0,0 +1,98 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestToGeoJSONPoints(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var buf bytes.Buffer
+	transform := func(x, y int) (float64, float64) {
+		return float64(x) * 0.5, -float64(y)
+	}
+	if err := s.ToGeoJSONPoints(&buf, transform); err != nil {
+		t.Fatalf("ToGeoJSONPoints() returned error: %s", err)
+	}
+
+	want := `{"type":"FeatureCollection","features":[` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[0,-0]},"properties":{"t":0,"heading":"down"}},` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[0,-1]},"properties":{"t":1,"heading":"right"}},` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[0.5,-1]},"properties":{"t":2,"heading":"up"}},` +
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[0.5,-0]},"properties":{"t":3,"heading":"none"}}` +
+		"]}\n"
+	if got := buf.String(); got != want {
+		t.Errorf("ToGeoJSONPoints() = %s want %s", got, want)
+	}
+}
+
+func TestToGeoJSONPointsValid(t *testing.T) {
+	s, err := NewHilbert(16, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.ToGeoJSONPoints(&buf, nil); err != nil {
+		t.Fatalf("ToGeoJSONPoints() returned error: %s", err)
+	}
+
+	var fc struct {
+		Type     string
+		Features []struct {
+			Geometry struct {
+				Coordinates []float64
+			}
+			Properties struct {
+				T int
+			}
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &fc); err != nil {
+		t.Fatalf("ToGeoJSONPoints() produced invalid JSON: %s", err)
+	}
+	if fc.Type != "FeatureCollection" || len(fc.Features) != s.N*s.N {
+		t.Fatalf("ToGeoJSONPoints() = %q with %d features want FeatureCollection with %d", fc.Type, len(fc.Features), s.N*s.N)
+	}
+	for d, f := range fc.Features {
+		x, y, _ := s.Map(d)
+		c := f.Geometry.Coordinates
+		if f.Properties.T != d || len(c) != 2 || c[0] != float64(x) || c[1] != float64(y) {
+			t.Errorf("feature %d = (t: %d, %v) want (t: %d, [%d %d])", d, f.Properties.T, c, d, x, y)
+		}
+	}
+}
+
+func TestToGeoJSONPointsNotFinite(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	transform := func(x, y int) (float64, float64) {
+		return math.NaN(), 0
+	}
+	if err := s.ToGeoJSONPoints(&bytes.Buffer{}, transform); err != ErrNotFinite {
+		t.Errorf("ToGeoJSONPoints() = %q want %q", err, ErrNotFinite)
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Gray represents a 2D Gray-code curve of order N for mapping to and from. The bits of the
+// coordinates are interleaved as for Morton, but the cells are visited in the order of the
+// reflected binary Gray code of t, so consecutive cells differ in a single bit of one coordinate.
+// This is almost as cheap to compute as a Morton curve, and avoids its diagonal jumps, though
+// consecutive cells are not always adjacent as on a Hilbert curve. Implements SpaceFilling
+// interface.
+type Gray struct {
+	N int // Always a power of two, and is the width/height of the space.
+}
+
+// NewGray returns a new Gray-code space filling curve which maps integers to and from the curve.
+// n must be a power of two. ErrTooLarge is returned if n*n would not fit in an int.
+func NewGray(n int) (*Gray, error) {
+	if n <= 0 {
+		return nil, ErrNotPositive
+	}
+	if (n & (n - 1)) != 0 {
+		return nil, ErrNotPowerOfTwo
+	}
+	if _, ok := mulChecked(n, n); !ok {
+		return nil, ErrTooLarge
+	}
+	return &Gray{
+		N: n,
+	}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *Gray) GetDimensions() (int, int) {
+	return s.N, s.N
+}
+
+// Map transforms a one dimension value, t, in the range [0, n^2-1] to coordinates on the
+// Gray-code curve in the two-dimension space, where x and y are within [0,n-1].
+func (s *Gray) Map(t int) (x, y int, err error) {
+	if t < 0 || t >= s.N*s.N {
+		return -1, -1, ErrOutOfRange
+	}
+	m := Morton{N: s.N}
+	return m.Map(t ^ t>>1)
+}
+
+// MapInverse transform coordinates on the Gray-code curve from (x,y) to t.
+func (s *Gray) MapInverse(x, y int) (t int, err error) {
+	m := Morton{N: s.N}
+	if t, err = m.MapInverse(x, y); err != nil {
+		return -1, err
+	}
+
+	// Undo the Gray code, each bit of t being the XOR of all the bits of the code above it.
+	for shift := uint(1); t>>shift != 0; shift <<= 1 {
+		t ^= t >> shift
+	}
+	return t, nil
+}
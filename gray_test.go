@@ -0,0 +1,109 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestGrayNewErrors(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want error
+	}{
+		{-1, ErrNotPositive},
+		{0, ErrNotPositive},
+		{3, ErrNotPowerOfTwo},
+		{6, ErrNotPowerOfTwo},
+		{1 << uint(bits.UintSize/2), ErrTooLarge},
+	} {
+		s, err := NewGray(tc.n)
+		if s != nil || err != tc.want {
+			t.Errorf("NewGray(%d) = (%+v, %q) did not fail want (?, %q)", tc.n, s, err, tc.want)
+		}
+	}
+}
+
+func TestGrayMap(t *testing.T) {
+	s, err := NewGray(4)
+	if err != nil {
+		t.Fatalf("NewGray(4) failed: %s", err)
+	}
+
+	// A U shape in each quadrant, with the quadrants themselves visited in a U.
+	want := [][2]int{
+		{0, 0}, {0, 1}, {1, 1}, {1, 0},
+		{1, 2}, {1, 3}, {0, 3}, {0, 2},
+		{2, 2}, {2, 3}, {3, 3}, {3, 2},
+		{3, 0}, {3, 1}, {2, 1}, {2, 0},
+	}
+	for d, p := range want {
+		x, y, err := s.Map(d)
+		if err != nil || x != p[0] || y != p[1] {
+			t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, p[0], p[1])
+		}
+		if got, err := s.MapInverse(p[0], p[1]); err != nil || got != d {
+			t.Errorf("MapInverse(%d, %d) = (%d, %v) want (%d, nil)", p[0], p[1], got, err, d)
+		}
+	}
+
+	if _, _, err := s.Map(16); err != ErrOutOfRange {
+		t.Errorf("Map(16) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, _, err := s.Map(-1); err != ErrOutOfRange {
+		t.Errorf("Map(-1) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := s.MapInverse(4, 0); err != ErrOutOfRange {
+		t.Errorf("MapInverse(4, 0) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestGrayAllMapValues(t *testing.T) {
+	s, err := NewGray(64)
+	if err != nil {
+		t.Fatalf("NewGray(64) failed: %s", err)
+	}
+
+	px, py, _ := s.Map(0)
+	for d := 0; d < s.N*s.N; d++ {
+		x, y, err := s.Map(d)
+		if err != nil {
+			t.Errorf("Map(%d) returned error: %s", d, err)
+		}
+		if got, err := s.MapInverse(x, y); err != nil || got != d {
+			t.Errorf("Failed Map(%d) -> MapInverse(%d, %d) -> %d", d, x, y, got)
+		}
+
+		// Consecutive cells differ in exactly one bit of one coordinate.
+		if d > 0 && bits.OnesCount(uint(x^px))+bits.OnesCount(uint(y^py)) != 1 {
+			t.Errorf("Map(%d) = (%d, %d) and Map(%d) = (%d, %d) differ in more than one bit", d-1, px, py, d, x, y)
+		}
+		px, py = x, y
+	}
+}
+
+func TestGrayLocality(t *testing.T) {
+	g, _ := NewGray(32)
+	m, _ := NewMorton(32)
+
+	if gs, ms := MaxStretch(g), MaxStretch(m); gs >= ms {
+		t.Errorf("MaxStretch(Gray(32)) = %f want less than Morton(32) %f", gs, ms)
+	}
+	gr, _ := LocalityRatio(g, 10000, 1)
+	if mr, _ := LocalityRatio(m, 10000, 1); gr >= mr {
+		t.Errorf("LocalityRatio(Gray(32)) = %f want less than Morton(32) %f", gr, mr)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Heading is the direction the curve travels in when moving from one cell to the next. As with
+// images, x increases to the right and y increases downwards.
+type Heading int
+
+// Valid headings. HeadingNone is used for the last cell on the curve, which has no next cell.
+const (
+	HeadingNone Heading = iota
+	HeadingRight
+	HeadingDown
+	HeadingLeft
+	HeadingUp
+)
+
+var headingNames = [...]string{"none", "right", "down", "left", "up"}
+
+// String returns the lower case name of the heading, e.g. "right".
+func (h Heading) String() string {
+	if h < 0 || int(h) >= len(headingNames) {
+		return "unknown"
+	}
+	return headingNames[h]
+}
+
+// headingBetween returns the heading of the move from (x0,y0) to (x1,y1), or HeadingNone if the
+// two cells are not adjacent.
+func headingBetween(x0, y0, x1, y1 int) Heading {
+	switch {
+	case x1 == x0+1 && y1 == y0:
+		return HeadingRight
+	case x1 == x0-1 && y1 == y0:
+		return HeadingLeft
+	case x1 == x0 && y1 == y0+1:
+		return HeadingDown
+	case x1 == x0 && y1 == y0-1:
+		return HeadingUp
+	}
+	return HeadingNone
+}
+
+// Heading returns the direction the Hilbert curve travels in when leaving the cell at t. The last
+// cell, t = n^2-1, returns HeadingNone.
+func (s *Hilbert) Heading(t int) (Heading, error) {
+	x0, y0, err := s.Map(t)
+	if err != nil {
+		return HeadingNone, err
+	}
+	if t == s.N*s.N-1 {
+		return HeadingNone, nil
+	}
+	x1, y1, _ := s.Map(t + 1)
+	return headingBetween(x0, y0, x1, y1), nil
+}
+
+// QuadConnectivity describes how the four quadrants of a sub-square are joined at the given level
+// of the recursion, where level 0 is the whole space, and each level splits the squares of the
+// previous level into four quadrants, down to level GetOrder()-1. The sub-square examined is the
+// one containing the start of the curve. The first three headings are the moves from the last
+// cell of each quadrant, in curve order, to the first cell of the next. The fourth is the move
+// out of the sub-square to the next sub-square of the same size, or HeadingNone at level 0.
+// ErrOrderTooSmall is returned for N=1, which has no levels.
+func (s *Hilbert) QuadConnectivity(level int) ([4]Heading, error) {
+	var headings [4]Heading
+	if s.GetOrder() < 1 {
+		return headings, ErrOrderTooSmall
+	}
+	if level < 0 || level >= s.GetOrder() {
+		return headings, ErrOutOfRange
+	}
+
+	q := 1 << uint(2*(s.GetOrder()-level-1)) // Number of cells in each quadrant
+	for i := range headings {
+		t := (i + 1) * q
+		if t >= s.N*s.N {
+			break
+		}
+		x0, y0, _ := s.Map(t - 1)
+		x1, y1, _ := s.Map(t)
+		headings[i] = headingBetween(x0, y0, x1, y1)
+	}
+	return headings, nil
+}
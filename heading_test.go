@@ -0,0 +1,118 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestHeading(t *testing.T) {
+	testCases := []struct {
+		vertical bool
+		want     []Heading
+	}{
+		{false, []Heading{HeadingDown, HeadingRight, HeadingUp, HeadingNone}},
+		{true, []Heading{HeadingRight, HeadingDown, HeadingLeft, HeadingNone}},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(2, tc.vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		for d, want := range tc.want {
+			got, err := s.Heading(d)
+			if err != nil {
+				t.Errorf("Heading(%d) returned error: %s", d, err)
+			}
+			if got != want {
+				t.Errorf("NewHilbert(2, %t).Heading(%d) = %s want %s", tc.vertical, d, got, want)
+			}
+		}
+	}
+}
+
+func TestHeadingAllValues(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for d := 0; d < s.N*s.N-1; d++ {
+		if h, _ := s.Heading(d); h == HeadingNone {
+			t.Errorf("Heading(%d) = %s, want a move to an adjacent cell", d, h)
+		}
+	}
+
+	if _, err := s.Heading(s.N * s.N); err != ErrOutOfRange {
+		t.Errorf("Heading(%d) = %q want %q", s.N*s.N, err, ErrOutOfRange)
+	}
+}
+
+func TestHeadingString(t *testing.T) {
+	testCases := []struct {
+		h    Heading
+		want string
+	}{
+		{HeadingNone, "none"},
+		{HeadingRight, "right"},
+		{HeadingDown, "down"},
+		{HeadingLeft, "left"},
+		{HeadingUp, "up"},
+		{Heading(-1), "unknown"},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.h.String(); got != tc.want {
+			t.Errorf("Heading(%d).String() = %q want %q", int(tc.h), got, tc.want)
+		}
+	}
+}
+
+func TestQuadConnectivity(t *testing.T) {
+	testCases := []struct {
+		vertical bool
+		level    int
+		want     [4]Heading
+	}{
+		{false, 0, [4]Heading{HeadingDown, HeadingRight, HeadingUp, HeadingNone}},
+		{false, 1, [4]Heading{HeadingRight, HeadingDown, HeadingLeft, HeadingDown}},
+		{false, 2, [4]Heading{HeadingDown, HeadingRight, HeadingUp, HeadingRight}},
+		{false, 3, [4]Heading{HeadingRight, HeadingDown, HeadingLeft, HeadingDown}},
+		{true, 0, [4]Heading{HeadingRight, HeadingDown, HeadingLeft, HeadingNone}},
+		{true, 1, [4]Heading{HeadingDown, HeadingRight, HeadingUp, HeadingRight}},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(16, tc.vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		got, err := s.QuadConnectivity(tc.level)
+		if err != nil {
+			t.Errorf("QuadConnectivity(%d) returned error: %s", tc.level, err)
+		}
+		if got != tc.want {
+			t.Errorf("NewHilbert(16, %t).QuadConnectivity(%d) = %v want %v", tc.vertical, tc.level, got, tc.want)
+		}
+	}
+
+	s, _ := NewHilbert(16, false)
+	for _, level := range []int{-1, 4} {
+		if _, err := s.QuadConnectivity(level); err != ErrOutOfRange {
+			t.Errorf("QuadConnectivity(%d) = %q want %q", level, err, ErrOutOfRange)
+		}
+	}
+}
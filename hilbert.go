@@ -16,20 +16,72 @@
 // curves.
 package hilbert
 
+import "math/bits"
+
 // Hilbert represents a 2D Hilbert space of order N for mapping to and from.
 // Implements SpaceFilling interface.
 type Hilbert struct {
 	N                  int
 	verticalCompatible bool
+	reversed           bool
+	bounds             BoundsPolicy
+	progress           ProgressFunc
+
+	// startState is the state of the whole space in forwardStates and inverseStates. Vertical
+	// curves are the transpose of horizontal ones, so start transposed, which avoids transforming
+	// the coordinates on every call.
+	startState uint8
+
+	// mirrored reflects the walk left to right, mapping x to N-1-x. Along with the four start
+	// states this allows all eight symmetries of the square, see WithTransform.
+	mirrored bool
+
+	// Lookup tables built by Prewarm, or nil. forward maps t to y*N+x, and inverse maps y*N+x
+	// back to t.
+	forward []uint32
+	inverse []uint32
+}
+
+// Orientation describes how a curve is laid out in the space.
+type Orientation int
+
+// Supported orientations.
+const (
+	// Horizontal curves start at (0,0) and end at (N-1,0), the shape of the letter U. Multiple
+	// square curves can be placed side by side and maintain the locality property.
+	Horizontal Orientation = iota
+
+	// Vertical curves start at (0,0) and end at (0,N-1), the shape of a backwards letter C.
+	// Multiple square curves can be vertically stacked and maintain the locality property.
+	Vertical
+)
+
+var orientationNames = [...]string{"horizontal", "vertical"}
+
+// String returns the lower case name of the orientation, e.g. "horizontal".
+func (o Orientation) String() string {
+	if o < 0 || int(o) >= len(orientationNames) {
+		return "unknown"
+	}
+	return orientationNames[o]
 }
 
+// MaxOrder is the largest order of Hilbert curve, so N is at most 2^MaxOrder, that can be created by
+// NewHilbert. Every value on the curve, up to N*N-1, then fits in an int: this is 31 when int is
+// 64 bits, and 15 when it is 32 bits. Encode2D and Hilbert64 support up to order 32 using uint64
+// values on any platform.
+const MaxOrder = bits.UintSize/2 - 1
+
 // NewHilbert returns a Hilbert space which maps integers to and from the curve.
 // n must be a power of two. If verticalCompatible is true, the Hilbert curve
 // will be rotated 90 degrees and rotated around the Y-axis. In other words
 // instead of the Hilbert curve representing the shaper of the letter U, it will
 // look like a backwards letter C. This allows multiple square Hilbert curves to
-// be vertically stacked and maintain the Hilbert locality property.
-func NewHilbert(n int, verticalCompatible bool) (*Hilbert, error) {
+// be vertically stacked and maintain the Hilbert locality property. Further options,
+// such as WithBoundsPolicy, or WithTransform to rotate or mirror the curve into any other
+// layout, may be given in opts. ErrTooLarge is returned if n is more than
+// 2^MaxOrder, as N*N would overflow an int.
+func NewHilbert(n int, verticalCompatible bool, opts ...Option) (*Hilbert, error) {
 	if n <= 0 {
 		return nil, ErrNotPositive
 	}
@@ -39,10 +91,19 @@ func NewHilbert(n int, verticalCompatible bool) (*Hilbert, error) {
 		return nil, ErrNotPowerOfTwo
 	}
 
-	return &Hilbert{
+	if n > 1<<MaxOrder {
+		return nil, ErrTooLarge
+	}
+
+	s := &Hilbert{
 		N:                  n,
 		verticalCompatible: verticalCompatible,
-	}, nil
+		startState:         uint8(b2i(verticalCompatible)),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
 }
 
 // GetDimensions returns the width and height of the 2D space.
@@ -50,73 +111,261 @@ func (s *Hilbert) GetDimensions() (int, int) {
 	return s.N, s.N
 }
 
+// Orientation returns the orientation of the curve, Vertical if it starts and ends in the same
+// column, and Horizontal if it starts and ends in the same row.
+func (s *Hilbert) Orientation() Orientation {
+	if s.startState&1 == 1 {
+		return Vertical
+	}
+	return Horizontal
+}
+
+// Reversed returns a copy of the curve traversed in the opposite direction, so Map(t) on the
+// returned curve is the same as Map(N*N-1-t) on the original. Reversing a reversed curve returns
+// it to the original direction. Lookup tables built by Prewarm are not copied.
+func (s *Hilbert) Reversed() *Hilbert {
+	return &Hilbert{
+		N:                  s.N,
+		verticalCompatible: s.verticalCompatible,
+		reversed:           !s.reversed,
+		bounds:             s.bounds,
+		progress:           s.progress,
+		startState:         s.startState,
+		mirrored:           s.mirrored,
+	}
+}
+
+// IsReversed returns true if the curve is traversed in the opposite direction, see Reversed.
+func (s *Hilbert) IsReversed() bool {
+	return s.reversed
+}
+
+// IsVerticalCompatible returns true if the curve was created with verticalCompatible set.
+func (s *Hilbert) IsVerticalCompatible() bool {
+	return s.verticalCompatible
+}
+
+// GetOrder returns the order of the curve, that is the number of times the space is recursively
+// subdivided into quadrants. N is always 2^order.
+func (s *Hilbert) GetOrder() int {
+	return bits.TrailingZeros(uint(s.N))
+}
+
 // Map transforms a one dimension value, t, in the range [0, n^2-1] to coordinates on the Hilbert
 // curve in the two-dimension space, where x and y are within [0,n-1].
 func (s *Hilbert) Map(t int) (x, y int, err error) {
 	if t < 0 || t >= s.N*s.N {
-		return -1, -1, ErrOutOfRange
-	}
-
-	for i := 1; i < s.N; i = i * 2 {
-		rx := t&2 == 2
-		ry := t&1 == 1
-		if rx {
-			ry = !ry
+		var ok bool
+		if t, ok = s.bounds.fit(t, s.N*s.N); !ok {
+			return -1, -1, ErrOutOfRange
 		}
+	}
 
-		x, y = s.rotate(i, x, y, rx, ry)
+	x, y = s.mapValid(t)
+	return x, y, nil
+}
 
-		if rx {
-			x = x + i
-		}
-		if ry {
-			y = y + i
+// MustMap is like Map, but panics instead of returning an error, for hot loops where t is known
+// to be valid.
+func (s *Hilbert) MustMap(t int) (x, y int) {
+	if uint(t) >= uint(s.N*s.N) {
+		x, y, err := s.Map(t)
+		if err != nil {
+			panic("hilbert: MustMap: " + err.Error())
 		}
+		return x, y
+	}
+	return s.mapValid(t)
+}
 
-		t /= 4
+// mapValid is Map for a value of t already known to be within [0, n^2-1].
+func (s *Hilbert) mapValid(t int) (x, y int) {
+	if s.forward != nil {
+		p := int(s.forward[t])
+		return p % s.N, p / s.N
 	}
 
-	if s.verticalCompatible {
-		// Rotate 90 degrees counter clockwise: swap x and y, then adjust y to match rotation.
-		x, y = y, s.N-1-x
+	if s.reversed {
+		t = s.N*s.N - 1 - t
+	}
+	if order := s.GetOrder(); bitwiseCore && order >= bitwiseMinOrder {
+		bx, by := decodeBits(uint64(t), uint(order))
+		return applyLayout(s.startState, s.mirrored, s.N, int(bx), int(by))
+	}
+
+	// Walk down the levels, as in MapInverse, taking the next base-4 digit of t each time. An odd
+	// level is done first, so the rest can be done two at a time.
+	state := s.startState
+	shift := uint(2 * s.GetOrder())
+	if shift&2 == 2 {
+		shift -= 2
+		e := forwardStates[state<<2|uint8(t>>shift&3)]
+		x, y = int(e>>3), int(e>>2&1)
+		state = e & 3
+	}
+	for shift > 0 {
+		shift -= 4
+		e := forwardStates2[(uint(state)<<4|uint(t>>shift&15))&63]
+		x = x<<2 | int(e>>4)
+		y = y<<2 | int(e>>2&3)
+		state = e & 3
+	}
 
-		// Reflect around the X-axis: flip y.
-		y = s.N - 1 - y
+	if s.mirrored {
+		x = s.N - 1 - x
 	}
+	return
+}
 
+// Endpoints returns the coordinates of the first and last cells on the curve, that is Map(0) and
+// Map(N*N-1).
+func (s *Hilbert) Endpoints() (startX, startY, endX, endY int) {
+	startX, startY, _ = s.Map(0)
+	endX, endY, _ = s.Map(s.N*s.N - 1)
 	return
 }
 
+// Center returns the central cell of the space and its value on the curve. N is always even,
+// apart from N=1, so there is no single middle cell; (N/2, N/2) is used, the one of the four cells
+// around the center point with the largest coordinates.
+func (s *Hilbert) Center() (x, y, t int) {
+	x, y = s.N/2, s.N/2
+	t, _ = s.MapInverse(x, y)
+	return x, y, t
+}
+
+// CenterIndex returns the value on the curve of the central cell, see Center.
+func (s *Hilbert) CenterIndex() int {
+	_, _, t := s.Center()
+	return t
+}
+
+// inverseStates is the state-transition table used by MapInverse. Each state is how the current
+// quadrant is transformed relative to the space, bit 0 being set if it is transposed and bit 1 if
+// it is flipped in both x and y. The table is indexed by state<<2 | xbit<<1 | ybit, where xbit and
+// ybit are the next bits of the coordinates, and holds digit<<2 | next state, where digit is the
+// next base-4 digit of t.
+var inverseStates = [16]uint8{
+	1, 4, 15, 8, // Identity
+	0, 14, 5, 9, // Transposed
+	10, 13, 6, 3, // Flipped
+	11, 7, 12, 2, // Transposed and flipped
+}
+
+// inverseStates2 is the same as inverseStates, but steps two levels at a time. It is indexed by
+// state<<4 | xbits<<2 | ybits, and holds digits<<2 | next state.
+var inverseStates2 [64]uint8
+
+// forwardStates is the inverse of inverseStates, used by Map. It is indexed by state<<2 | digit,
+// where digit is the next base-4 digit of t, and holds xbit<<3 | ybit<<2 | next state.
+var forwardStates = [16]uint8{
+	1, 4, 12, 11, // Identity
+	0, 9, 13, 6, // Transposed
+	15, 10, 2, 5, // Flipped
+	14, 7, 3, 8, // Transposed and flipped
+}
+
+// forwardStates2 is the same as forwardStates, but steps two levels at a time. It is indexed by
+// state<<4 | digits, and holds xbits<<4 | ybits<<2 | next state.
+var forwardStates2 [64]uint8
+
+func init() {
+	for i := range forwardStates2 {
+		state, digits := i>>4, i&15
+
+		e1 := forwardStates[state<<2|digits>>2]
+		e2 := forwardStates[int(e1&3)<<2|digits&3]
+		xbits, ybits := e1>>3<<1|e2>>3, (e1>>2&1)<<1|e2>>2&1
+		forwardStates2[i] = (xbits<<2|ybits)<<2 | e2&3
+	}
+
+	for i := range inverseStates2 {
+		state, xbits, ybits := i>>4, i>>2&3, i&3
+
+		e1 := inverseStates[state<<2|(xbits>>1)<<1|ybits>>1]
+		e2 := inverseStates[int(e1&3)<<2|(xbits&1)<<1|ybits&1]
+		inverseStates2[i] = (e1>>2<<2|e2>>2)<<2 | e2&3
+	}
+}
+
 // MapInverse transform coordinates on Hilbert curve from (x,y) to t.
 func (s *Hilbert) MapInverse(x, y int) (t int, err error) {
 	if x < 0 || x >= s.N || y < 0 || y >= s.N {
-		return -1, ErrOutOfRange
+		var okX, okY bool
+		x, okX = s.fitCoord(x)
+		y, okY = s.fitCoord(y)
+		if !okX || !okY {
+			return -1, ErrOutOfRange
+		}
 	}
 
-	if s.verticalCompatible {
-		// Reverse the X-axis reflection.
-		y = s.N - 1 - y
+	return s.mapInverseValid(x, y), nil
+}
 
-		// Reverse the 90-degree counter-clockwise rotation.
-		x, y = s.N-1-y, x
+// MustMapInverse is like MapInverse, but panics instead of returning an error, for hot loops
+// where x and y are known to be valid.
+func (s *Hilbert) MustMapInverse(x, y int) int {
+	if uint(x) >= uint(s.N) || uint(y) >= uint(s.N) {
+		t, err := s.MapInverse(x, y)
+		if err != nil {
+			panic("hilbert: MustMapInverse: " + err.Error())
+		}
+		return t
 	}
+	return s.mapInverseValid(x, y)
+}
 
-	for i := s.N / 2; i > 0; i = i / 2 {
-		rx := (x & i) > 0
-		ry := (y & i) > 0
-
-		a := 0
-		if rx {
-			a = 3
+// mapInverseValid is MapInverse for coordinates already known to be within [0,n-1].
+func (s *Hilbert) mapInverseValid(x, y int) (t int) {
+	if s.inverse != nil {
+		return int(s.inverse[y*s.N+x])
+	}
+	if s.mirrored {
+		x = s.N - 1 - x
+	}
+	if order := s.GetOrder(); bitwiseCore && order >= bitwiseMinOrder {
+		// The transpose and flip of the start state undo themselves.
+		x, y = applyLayout(s.startState, false, s.N, x, y)
+		t = int(encodeBits(uint32(x), uint32(y), uint(order)))
+		if s.reversed {
+			t = s.N*s.N - 1 - t
 		}
-		t += i * i * (a ^ b2i(ry))
+		return t
+	}
 
-		x, y = s.rotate(i, x, y, rx, ry)
+	// Walk down the levels, tracking how the remaining quadrants are transformed, instead of
+	// rotating the coordinates themselves. An odd level is done first, so the rest can be done
+	// two at a time.
+	state := s.startState
+	shift := uint(s.GetOrder())
+	if shift&1 == 1 {
+		shift--
+		e := inverseStates[int(state)<<2|(x>>shift&1)<<1|y>>shift&1]
+		t = int(e >> 2)
+		state = e & 3
+	}
+	for shift > 0 {
+		shift -= 2
+		e := inverseStates2[(uint(state)<<4|uint(x>>shift&3)<<2|uint(y>>shift&3))&63]
+		t = t<<4 | int(e>>2)
+		state = e & 3
+	}
+
+	if s.reversed {
+		t = s.N*s.N - 1 - t
 	}
 
 	return
 }
 
+// fitCoord applies the bounds policy to a coordinate, if it is outside of the space.
+func (s *Hilbert) fitCoord(v int) (int, bool) {
+	if v >= 0 && v < s.N {
+		return v, true
+	}
+	return s.bounds.fit(v, s.N)
+}
+
 // rotate rotates and flips the quadrant appropriately.
 func (s *Hilbert) rotate(n, x, y int, rx, ry bool) (int, int) {
 	if !ry {
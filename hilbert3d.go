@@ -0,0 +1,93 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/bits"
+
+// Hilbert3D represents a 3D Hilbert space of order N for mapping to and from, such as for
+// ordering voxels. It uses Skilling's algorithm, see AxesToTranspose.
+type Hilbert3D struct {
+	N int // Always a power of two, and is the width/height/depth of the space.
+}
+
+// NewHilbert3D returns a 3D Hilbert space which maps integers to and from the curve. n must be a
+// power of two. ErrTooLarge is returned if N*N*N would not fit in an int.
+func NewHilbert3D(n int) (*Hilbert3D, error) {
+	if n <= 0 {
+		return nil, ErrNotPositive
+	}
+	if (n & (n - 1)) != 0 {
+		return nil, ErrNotPowerOfTwo
+	}
+	if 3*bits.TrailingZeros(uint(n)) >= bits.UintSize-1 {
+		return nil, ErrTooLarge
+	}
+	return &Hilbert3D{
+		N: n,
+	}, nil
+}
+
+// GetDimensions returns the width, height and depth of the 3D space.
+func (s *Hilbert3D) GetDimensions() (x, y, z int) {
+	return s.N, s.N, s.N
+}
+
+// GetOrder returns the order of the curve, the number of bits in each coordinate. N is always
+// 2^order.
+func (s *Hilbert3D) GetOrder() int {
+	return bits.TrailingZeros(uint(s.N))
+}
+
+// Map transforms a one dimension value, t, in the range [0, n^3-1] to coordinates on the Hilbert
+// curve in the three-dimension space, where x, y and z are within [0,n-1].
+func (s *Hilbert3D) Map(t int) (x, y, z int, err error) {
+	if t < 0 || t >= s.N*s.N*s.N {
+		return -1, -1, -1, ErrOutOfRange
+	}
+
+	order := s.GetOrder()
+	if order == 0 {
+		return 0, 0, 0, nil
+	}
+
+	// Spread the bits of t across the transpose, most significant first.
+	var axes [3]int
+	for i := 0; i < 3*order; i++ {
+		axes[2-i%3] |= (t >> uint(i) & 1) << uint(i/3)
+	}
+	TransposeToAxes(axes[:], order)
+	return axes[0], axes[1], axes[2], nil
+}
+
+// MapInverse transform coordinates on the Hilbert curve from (x,y,z) to t.
+func (s *Hilbert3D) MapInverse(x, y, z int) (t int, err error) {
+	if x < 0 || x >= s.N || y < 0 || y >= s.N || z < 0 || z >= s.N {
+		return -1, ErrOutOfRange
+	}
+
+	order := s.GetOrder()
+	if order == 0 {
+		return 0, nil
+	}
+
+	axes := [3]int{x, y, z}
+	AxesToTranspose(axes[:], order)
+	for i := order - 1; i >= 0; i-- {
+		for _, v := range axes {
+			t = t<<1 | v>>uint(i)&1
+		}
+	}
+	return t, nil
+}
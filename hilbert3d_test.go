@@ -0,0 +1,93 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestNewHilbert3DErrors(t *testing.T) {
+	testCases := []struct {
+		n    int
+		want error
+	}{
+		{-1, ErrNotPositive},
+		{0, ErrNotPositive},
+		{3, ErrNotPowerOfTwo},
+		{1 << 30, ErrTooLarge},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert3D(tc.n)
+		if s != nil || err != tc.want {
+			t.Errorf("NewHilbert3D(%d) = (%+v, %q) did not fail want (?, %q)", tc.n, s, err, tc.want)
+		}
+	}
+}
+
+func TestHilbert3DRangeErrors(t *testing.T) {
+	s, err := NewHilbert3D(4)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, d := range []int{-1, 64} {
+		if _, _, _, err := s.Map(d); err != ErrOutOfRange {
+			t.Errorf("Map(%d) = %q want %q", d, err, ErrOutOfRange)
+		}
+	}
+	for _, p := range [][3]int{{-1, 0, 0}, {0, -1, 0}, {0, 0, -1}, {4, 0, 0}, {0, 4, 0}, {0, 0, 4}} {
+		if _, err := s.MapInverse(p[0], p[1], p[2]); err != ErrOutOfRange {
+			t.Errorf("MapInverse(%d, %d, %d) = %q want %q", p[0], p[1], p[2], err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestHilbert3DAllMapValues(t *testing.T) {
+	for _, n := range []int{1, 2, 4, 16} {
+		s, err := NewHilbert3D(n)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		seen := make(map[[3]int]bool)
+		var prev [3]int
+		for d := 0; d < n*n*n; d++ {
+			x, y, z, err := s.Map(d)
+			if err != nil {
+				t.Fatalf("Map(%d) returned error: %s", d, err)
+			}
+			p := [3]int{x, y, z}
+			if x < 0 || x >= n || y < 0 || y >= n || z < 0 || z >= n || seen[p] {
+				t.Errorf("N=%d Map(%d) = %v is out of range or repeated", n, d, p)
+			}
+			seen[p] = true
+
+			// Consecutive cells differ by one step along a single axis.
+			if d > 0 {
+				steps := 0
+				for i := range p {
+					steps += max(p[i]-prev[i], prev[i]-p[i])
+				}
+				if steps != 1 {
+					t.Errorf("N=%d Map(%d) = %v is not next to Map(%d) = %v", n, d, p, d-1, prev)
+				}
+			}
+			prev = p
+
+			if got, err := s.MapInverse(x, y, z); err != nil || got != d {
+				t.Errorf("Failed Map(%d) -> MapInverse(%d, %d, %d) -> %d", d, x, y, z, got)
+			}
+		}
+	}
+}
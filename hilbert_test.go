@@ -54,6 +54,7 @@ func TestNewErrors(t *testing.T) {
 		{0, ErrNotPositive},
 		{3, ErrNotPowerOfTwo},
 		{5, ErrNotPowerOfTwo},
+		{1 << (MaxOrder + 1), ErrTooLarge},
 	}
 
 	for _, tc := range newTestCases {
@@ -64,6 +65,33 @@ func TestNewErrors(t *testing.T) {
 	}
 }
 
+func TestMaxOrder(t *testing.T) {
+	s, err := NewHilbert(1<<MaxOrder, false)
+	if err != nil {
+		t.Fatalf("NewHilbert(1<<MaxOrder) failed: %s", err)
+	}
+
+	// The last value on the curve must not overflow.
+	last := s.N*s.N - 1
+	if last <= 0 {
+		t.Fatalf("N*N-1 = %d overflowed", last)
+	}
+	x, y, err := s.Map(last)
+	if err != nil || x != s.N-1 || y != 0 {
+		t.Errorf("Map(%d) = (%d, %d, %v) want (%d, 0, nil)", last, x, y, err, s.N-1)
+	}
+	if got, err := s.MapInverse(s.N-1, 0); err != nil || got != last {
+		t.Errorf("MapInverse(%d, 0) = (%d, %v) want (%d, nil)", s.N-1, got, err, last)
+	}
+	want := int(Encode2D(uint32(s.N-1), uint32(s.N-1), MaxOrder))
+	if got, _ := s.MapInverse(s.N-1, s.N-1); got != want {
+		t.Errorf("MapInverse(%d, %d) = %d want %d", s.N-1, s.N-1, got, want)
+	}
+	if _, _, err := s.Map(last + 1); err != ErrOutOfRange {
+		t.Errorf("Map(%d) = %v want %v", last+1, err, ErrOutOfRange)
+	}
+}
+
 func TestMapRangeErrors(t *testing.T) {
 	var mapRangeTestCases = []struct {
 		d       int
@@ -249,6 +277,237 @@ func TestVerticalCompat(t *testing.T) {
 	}
 }
 
+func TestOrientation(t *testing.T) {
+	testCases := []struct {
+		vertical bool
+		want     Orientation
+		wantName string
+		wantPath [][2]int
+	}{
+		{false, Horizontal, "horizontal", [][2]int{{0, 0}, {0, 1}, {1, 1}, {1, 0}}}, // U
+		{true, Vertical, "vertical", [][2]int{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},      // Backwards C
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(2, tc.vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		if got := s.IsVerticalCompatible(); got != tc.vertical {
+			t.Errorf("NewHilbert(2, %t).IsVerticalCompatible() = %t want %t", tc.vertical, got, tc.vertical)
+		}
+		if got := s.Orientation(); got != tc.want || got.String() != tc.wantName {
+			t.Errorf("NewHilbert(2, %t).Orientation() = %s want %s", tc.vertical, got, tc.wantName)
+		}
+		for d, want := range tc.wantPath {
+			x, y, _ := s.Map(d)
+			if x != want[0] || y != want[1] {
+				t.Errorf("NewHilbert(2, %t).Map(%d) = (%d, %d) want (%d, %d)", tc.vertical, d, x, y, want[0], want[1])
+			}
+		}
+	}
+
+	if got := Orientation(-1).String(); got != "unknown" {
+		t.Errorf("Orientation(-1).String() = %q want %q", got, "unknown")
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	testCases := []struct {
+		n        int
+		vertical bool
+		want     [4]int
+	}{
+		{1, false, [4]int{0, 0, 0, 0}},
+		{1, true, [4]int{0, 0, 0, 0}},
+		{2, false, [4]int{0, 0, 1, 0}},
+		{2, true, [4]int{0, 0, 0, 1}},
+		{16, false, [4]int{0, 0, 15, 0}},
+		{16, true, [4]int{0, 0, 0, 15}},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(tc.n, tc.vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		sx, sy, ex, ey := s.Endpoints()
+		if got := [4]int{sx, sy, ex, ey}; got != tc.want {
+			t.Errorf("NewHilbert(%d, %t).Endpoints() = %v want %v", tc.n, tc.vertical, got, tc.want)
+		}
+	}
+}
+
+func TestCenter(t *testing.T) {
+	testCases := []struct {
+		n        int
+		vertical bool
+		want     [3]int
+	}{
+		{1, false, [3]int{0, 0, 0}},
+		{2, false, [3]int{1, 1, 2}},
+		{2, true, [3]int{1, 1, 2}},
+		{4, false, [3]int{2, 2, 8}},
+		{4, true, [3]int{2, 2, 8}},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(tc.n, tc.vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		x, y, d := s.Center()
+		if got := [3]int{x, y, d}; got != tc.want {
+			t.Errorf("NewHilbert(%d, %t).Center() = %v want %v", tc.n, tc.vertical, got, tc.want)
+		}
+		if got := s.CenterIndex(); got != tc.want[2] {
+			t.Errorf("NewHilbert(%d, %t).CenterIndex() = %d want %d", tc.n, tc.vertical, got, tc.want[2])
+		}
+	}
+}
+
+func TestReversed(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		s, err := NewHilbert(16, vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		r := s.Reversed()
+		if s.IsReversed() || !r.IsReversed() || r.Reversed().IsReversed() {
+			t.Errorf("IsReversed() = %t, Reversed().IsReversed() = %t, Reversed().Reversed().IsReversed() = %t want false, true, false",
+				s.IsReversed(), r.IsReversed(), r.Reversed().IsReversed())
+		}
+		if r.IsVerticalCompatible() != vertical {
+			t.Errorf("Reversed().IsVerticalCompatible() = %t want %t", r.IsVerticalCompatible(), vertical)
+		}
+
+		for d := 0; d < s.N*s.N; d++ {
+			wantX, wantY, _ := s.Map(s.N*s.N - 1 - d)
+			x, y, err := r.Map(d)
+			if err != nil {
+				t.Errorf("Reversed().Map(%d) returned error: %s", d, err)
+			}
+			if x != wantX || y != wantY {
+				t.Errorf("Reversed().Map(%d) = (%d, %d) want (%d, %d)", d, x, y, wantX, wantY)
+			}
+
+			got, err := r.MapInverse(x, y)
+			if err != nil {
+				t.Errorf("Reversed().MapInverse(%d, %d) returned error: %s", x, y, err)
+			}
+			if got != d {
+				t.Errorf("Reversed().MapInverse(%d, %d) = %d want %d", x, y, got, d)
+			}
+		}
+
+		if got := JumpCount(r); got != 0 {
+			t.Errorf("JumpCount(Reversed()) = %d want 0", got)
+		}
+		sx, sy, ex, ey := r.Endpoints()
+		if wsx, wsy, wex, wey := s.Endpoints(); sx != wex || sy != wey || ex != wsx || ey != wsy {
+			t.Errorf("Reversed().Endpoints() = (%d, %d, %d, %d) want (%d, %d, %d, %d)", sx, sy, ex, ey, wex, wey, wsx, wsy)
+		}
+	}
+}
+
+// mapReference is the original Map algorithm, which rotates the coordinates at each level and
+// then transforms vertical curves, kept to check and benchmark the state-transition
+// implementation against.
+func mapReference(s *Hilbert, t int) (x, y int) {
+	if s.reversed {
+		t = s.N*s.N - 1 - t
+	}
+
+	for i := 1; i < s.N; i = i * 2 {
+		rx := t&2 == 2
+		ry := t&1 == 1
+		if rx {
+			ry = !ry
+		}
+
+		x, y = s.rotate(i, x, y, rx, ry)
+
+		if rx {
+			x = x + i
+		}
+		if ry {
+			y = y + i
+		}
+
+		t /= 4
+	}
+
+	if s.verticalCompatible {
+		x, y = y, s.N-1-x
+		y = s.N - 1 - y
+	}
+	return x, y
+}
+
+func TestMapMatchesReference(t *testing.T) {
+	for n := 1; n <= 128; n *= 2 {
+		h, _ := NewHilbert(n, false)
+		v, _ := NewHilbert(n, true)
+		for _, s := range []*Hilbert{h, h.Reversed(), v, v.Reversed()} {
+			for d := 0; d < n*n; d++ {
+				wantX, wantY := mapReference(s, d)
+				if x, y, _ := s.Map(d); x != wantX || y != wantY {
+					t.Errorf("NewHilbert(%d, %t).Map(%d) = (%d, %d) want (%d, %d)", n, s.verticalCompatible, d, x, y, wantX, wantY)
+				}
+			}
+		}
+	}
+}
+
+// mapInverseReference is the original MapInverse algorithm, which rotates the coordinates at each
+// level, kept to check and benchmark the state-transition implementation against.
+func mapInverseReference(s *Hilbert, x, y int) int {
+	if s.verticalCompatible {
+		y = s.N - 1 - y
+		x, y = s.N-1-y, x
+	}
+
+	t := 0
+	for i := s.N / 2; i > 0; i = i / 2 {
+		rx := (x & i) > 0
+		ry := (y & i) > 0
+
+		a := 0
+		if rx {
+			a = 3
+		}
+		t += i * i * (a ^ b2i(ry))
+
+		x, y = s.rotate(i, x, y, rx, ry)
+	}
+
+	if s.reversed {
+		t = s.N*s.N - 1 - t
+	}
+	return t
+}
+
+func TestMapInverseMatchesReference(t *testing.T) {
+	for n := 1; n <= 128; n *= 2 {
+		h, _ := NewHilbert(n, false)
+		v, _ := NewHilbert(n, true)
+		for _, s := range []*Hilbert{h, h.Reversed(), v, v.Reversed()} {
+			for x := 0; x < n; x++ {
+				for y := 0; y < n; y++ {
+					want := mapInverseReference(s, x, y)
+					if got, _ := s.MapInverse(x, y); got != want {
+						t.Errorf("NewHilbert(%d, %t).MapInverse(%d, %d) = %d want %d", n, s.verticalCompatible, x, y, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
 func TestAllMapValues(t *testing.T) {
 	s, err := NewHilbert(16, false)
 	if err != nil {
@@ -275,6 +534,60 @@ func TestAllMapValues(t *testing.T) {
 	}
 }
 
+func TestMustMap(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		s, err := NewHilbert(16, vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		for d := 0; d < s.N*s.N; d++ {
+			x, y, _ := s.Map(d)
+			if gotX, gotY := s.MustMap(d); gotX != x || gotY != y {
+				t.Errorf("MustMap(%d) = (%d, %d) want (%d, %d)", d, gotX, gotY, x, y)
+			}
+			if got := s.MustMapInverse(x, y); got != d {
+				t.Errorf("MustMapInverse(%d, %d) = %d want %d", x, y, got, d)
+			}
+		}
+	}
+
+	// Values outside of the space are still handled by the bounds policy.
+	s, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsClamp))
+	if x, y := s.MustMap(100); x != 3 || y != 0 {
+		t.Errorf("MustMap(100) with BoundsClamp = (%d, %d) want (3, 0)", x, y)
+	}
+	if got := s.MustMapInverse(-1, 5); got != 5 {
+		t.Errorf("MustMapInverse(-1, 5) with BoundsClamp = %d want 5", got)
+	}
+}
+
+func TestMustMapPanics(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, f := range []struct {
+		name string
+		call func()
+	}{
+		{"MustMap(16)", func() { s.MustMap(16) }},
+		{"MustMap(-1)", func() { s.MustMap(-1) }},
+		{"MustMapInverse(4, 0)", func() { s.MustMapInverse(4, 0) }},
+		{"MustMapInverse(0, -1)", func() { s.MustMapInverse(0, -1) }},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", f.name)
+				}
+			}()
+			f.call()
+		}()
+	}
+}
+
 func BenchmarkMap(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		s, err := NewHilbert(benchmarkN, false)
@@ -325,6 +638,30 @@ func BenchmarkMapRandomVertical(b *testing.B) {
 	}
 }
 
+func BenchmarkMapReference(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s, err := NewHilbert(benchmarkN, false)
+		if err != nil {
+			b.Fatalf("Failed to create hibert space: %s", err)
+		}
+		for d := 0; d < benchmarkN*benchmarkN; d++ {
+			mapReference(s, d)
+		}
+	}
+}
+
+func BenchmarkMustMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s, err := NewHilbert(benchmarkN, false)
+		if err != nil {
+			b.Fatalf("Failed to create hibert space: %s", err)
+		}
+		for d := 0; d < benchmarkN*benchmarkN; d++ {
+			s.MustMap(d)
+		}
+	}
+}
+
 func BenchmarkMapInverse(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		s, err := NewHilbert(benchmarkN, false)
@@ -353,3 +690,33 @@ func BenchmarkMapInverseVertical(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkMustMapInverse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s, err := NewHilbert(benchmarkN, false)
+		if err != nil {
+			b.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		for x := 0; x < benchmarkN; x++ {
+			for y := 0; y < benchmarkN; y++ {
+				s.MustMapInverse(x, y)
+			}
+		}
+	}
+}
+
+func BenchmarkMapInverseReference(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s, err := NewHilbert(benchmarkN, false)
+		if err != nil {
+			b.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		for x := 0; x < benchmarkN; x++ {
+			for y := 0; y < benchmarkN; y++ {
+				mapInverseReference(s, x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package hilbertio converts streams of records between values on a space-filling curve from the
+// hilbert package and coordinates in its space, one record at a time, so files of any size can be
+// converted without holding them in memory.
+package hilbertio
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/hilbert"
+)
+
+// ErrBadRecord is returned by ConvertStream when a record can not be parsed.
+var ErrBadRecord = errors.New("record is malformed")
+
+// Format is the format of the records read and written by ConvertStream.
+type Format int
+
+// Supported formats.
+const (
+	CSV    Format = iota // Comma-separated values, one record per line
+	NDJSON               // Newline-delimited JSON, one object per line
+)
+
+// Direction is the direction in which ConvertStream converts records.
+type Direction int
+
+// Supported directions.
+const (
+	Forward Direction = iota // From values on the curve, t, to coordinates, x and y
+	Inverse                  // From coordinates, x and y, to values on the curve, t
+)
+
+// ConvertStream reads records from r, converts each with s in the direction dir, and writes them
+// to w in the same format, one record at a time.
+//
+// For CSV, Forward replaces the first field of each record, t, with the two fields x and y, and
+// Inverse replaces the first two fields, x and y, with t. Any further fields are kept after the
+// converted ones. If the first record can not be parsed it is taken to be a header, and the names
+// of the converted columns are replaced in the same way.
+//
+// For NDJSON, each line is an object, to which Forward adds the members "x" and "y" from the
+// member "t", and Inverse adds "t" from "x" and "y". Other members are kept, but are written in
+// sorted order. Blank lines are skipped.
+//
+// ErrBadRecord is returned if a record can not be parsed, hilbert.ErrOutOfRange if the format or
+// direction is not supported, and otherwise the first error from s, r or w.
+func ConvertStream(r io.Reader, w io.Writer, s hilbert.SpaceFilling, format Format, dir Direction) error {
+	if dir != Forward && dir != Inverse {
+		return hilbert.ErrOutOfRange
+	}
+	switch format {
+	case CSV:
+		return convertCSV(r, w, s, dir)
+	case NDJSON:
+		return convertNDJSON(r, w, s, dir)
+	}
+	return hilbert.ErrOutOfRange
+}
+
+// inputNames and outputNames are the names of the fields read and written in each direction.
+var (
+	inputNames  = [...][]string{Forward: {"t"}, Inverse: {"x", "y"}}
+	outputNames = [...][]string{Forward: {"x", "y"}, Inverse: {"t"}}
+)
+
+// convert converts the values of the fields named by inputNames[dir] to those of the fields named
+// by outputNames[dir].
+func convert(s hilbert.SpaceFilling, dir Direction, in []int) ([]int, error) {
+	if dir == Forward {
+		x, y, err := s.Map(in[0])
+		return []int{x, y}, err
+	}
+	t, err := s.MapInverse(in[0], in[1])
+	return []int{t}, err
+}
+
+// convertCSV is ConvertStream for CSV.
+func convertCSV(r io.Reader, w io.Writer, s hilbert.SpaceFilling, dir Direction) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+	cw := csv.NewWriter(w)
+
+	n := len(inputNames[dir])
+	in := make([]int, n)
+	var out []string
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if len(record) < n {
+			return ErrBadRecord
+		}
+
+		parsed := true
+		for i, f := range record[:n] {
+			if in[i], err = strconv.Atoi(strings.TrimSpace(f)); err != nil {
+				parsed = false
+			}
+		}
+		out = out[:0]
+		switch {
+		case parsed:
+			values, err := convert(s, dir, in)
+			if err != nil {
+				return err
+			}
+			for _, v := range values {
+				out = append(out, strconv.Itoa(v))
+			}
+		case first:
+			out = append(out, outputNames[dir]...)
+		default:
+			return ErrBadRecord
+		}
+		if err := cw.Write(append(out, record[n:]...)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// convertNDJSON is ConvertStream for NDJSON.
+func convertNDJSON(r io.Reader, w io.Writer, s hilbert.SpaceFilling, dir Direction) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+
+	in := make([]int, len(inputNames[dir]))
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			var obj map[string]json.RawMessage
+			if json.Unmarshal(line, &obj) != nil || obj == nil {
+				return ErrBadRecord
+			}
+			for i, name := range inputNames[dir] {
+				if json.Unmarshal(obj[name], &in[i]) != nil {
+					return ErrBadRecord
+				}
+			}
+			values, err := convert(s, dir, in)
+			if err != nil {
+				return err
+			}
+			for i, name := range outputNames[dir] {
+				obj[name] = json.RawMessage(strconv.Itoa(values[i]))
+			}
+
+			// Marshalling can not fail, as every value is already valid JSON.
+			b, _ := json.Marshal(obj)
+			if _, err := bw.Write(append(b, '\n')); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,146 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbertio
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/hilbert"
+)
+
+func TestConvertStream(t *testing.T) {
+	s, err := hilbert.NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	tests := []struct {
+		format Format
+		dir    Direction
+		in     string
+		want   string
+	}{
+		{CSV, Forward, "", ""},
+		{CSV, Forward, "0\n1\n15\n", "0,0\n1,0\n3,0\n"},
+		{CSV, Forward, "t,name\n2,a\n 3 ,\"b,c\"\n", "x,y,name\n1,1,a\n0,1,\"b,c\"\n"},
+		{CSV, Inverse, "0,0\n1,0\n3,0\n", "0\n1\n15\n"},
+		{CSV, Inverse, "x,y,name\n1,1,a\n", "t,name\n2,a\n"},
+		{NDJSON, Forward, "", ""},
+		{NDJSON, Forward, "{\"t\":2}\n\n{\"t\":3,\"name\":\"b\"}", "{\"t\":2,\"x\":1,\"y\":1}\n{\"name\":\"b\",\"t\":3,\"x\":0,\"y\":1}\n"},
+		{NDJSON, Inverse, "{\"x\":3,\"y\":0}\n{\"y\":1,\"x\":0,\"t\":99}\n", "{\"t\":15,\"x\":3,\"y\":0}\n{\"t\":3,\"x\":0,\"y\":1}\n"},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := ConvertStream(strings.NewReader(tt.in), &buf, s, tt.format, tt.dir); err != nil {
+			t.Errorf("ConvertStream(%q, %d, %d) returned error: %s", tt.in, tt.format, tt.dir, err)
+			continue
+		}
+		if got := buf.String(); got != tt.want {
+			t.Errorf("ConvertStream(%q, %d, %d) = %q want %q", tt.in, tt.format, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestConvertStreamRoundTrip(t *testing.T) {
+	s, err := hilbert.NewHilbert(32, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, format := range []Format{CSV, NDJSON} {
+		var src bytes.Buffer
+		for d := 0; d < s.N*s.N; d++ {
+			if format == CSV {
+				src.WriteString(strconv.Itoa(d) + "\n")
+			} else {
+				src.WriteString("{\"t\":" + strconv.Itoa(d) + "}\n")
+			}
+		}
+		var coords, back bytes.Buffer
+		if err := ConvertStream(&src, &coords, s, format, Forward); err != nil {
+			t.Fatalf("ConvertStream(%d, Forward) returned error: %s", format, err)
+		}
+		if err := ConvertStream(&coords, &back, s, format, Inverse); err != nil {
+			t.Fatalf("ConvertStream(%d, Inverse) returned error: %s", format, err)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(back.String(), "\n"), "\n")
+		if len(lines) != s.N*s.N {
+			t.Fatalf("ConvertStream(%d) returned %d records want %d", format, len(lines), s.N*s.N)
+		}
+		for d, line := range lines {
+			x, y, _ := s.Map(d)
+			want := strconv.Itoa(d)
+			if format == NDJSON {
+				want = "{\"t\":" + want + ",\"x\":" + strconv.Itoa(x) + ",\"y\":" + strconv.Itoa(y) + "}"
+			}
+			if line != want {
+				t.Errorf("ConvertStream(%d) record %d = %q want %q", format, d, line, want)
+			}
+		}
+	}
+}
+
+func TestConvertStreamErrors(t *testing.T) {
+	s, err := hilbert.NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	tests := []struct {
+		format Format
+		dir    Direction
+		in     string
+		want   error
+	}{
+		{CSV, Forward, "0\nfoo\n", ErrBadRecord},
+		{CSV, Forward, "16\n", hilbert.ErrOutOfRange},
+		{CSV, Inverse, "1\n", ErrBadRecord},
+		{CSV, Inverse, "x,y\n1,y\n", ErrBadRecord},
+		{CSV, Inverse, "4,0\n", hilbert.ErrOutOfRange},
+		{NDJSON, Forward, "{\"t\":1\n", ErrBadRecord},
+		{NDJSON, Forward, "null\n", ErrBadRecord},
+		{NDJSON, Forward, "{\"x\":1}\n", ErrBadRecord},
+		{NDJSON, Forward, "{\"t\":1.5}\n", ErrBadRecord},
+		{NDJSON, Inverse, "{\"x\":1}\n", ErrBadRecord},
+		{NDJSON, Inverse, "{\"x\":1,\"y\":-1}\n", hilbert.ErrOutOfRange},
+		{Format(2), Forward, "0\n", hilbert.ErrOutOfRange},
+		{CSV, Direction(2), "0\n", hilbert.ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := ConvertStream(strings.NewReader(tt.in), &buf, s, tt.format, tt.dir); err != tt.want {
+			t.Errorf("ConvertStream(%q, %d, %d) = %v want %v", tt.in, tt.format, tt.dir, err, tt.want)
+		}
+	}
+
+	want := errors.New("write failed")
+	if err := ConvertStream(strings.NewReader("{\"t\":1}\n"), failingWriter{want}, s, NDJSON, Forward); err != want {
+		t.Errorf("ConvertStream() = %v want %v", err, want)
+	}
+}
+
+// failingWriter is an io.Writer which always fails with err.
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write(p []byte) (int, error) {
+	return 0, w.err
+}
@@ -0,0 +1,95 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/big"
+
+// HilbertND represents a Hilbert space in any number of dimensions, each 2^bits wide, using
+// Skilling's algorithm, see AxesToTranspose. As the index has dims*bits bits, which can easily
+// be more than 64, it is a big.Int.
+type HilbertND struct {
+	dims int
+	bits int
+}
+
+// NewHilbertND returns a Hilbert space of dims dimensions, with bitsPerDim bits in each
+// coordinate, so each dimension is 2^bitsPerDim wide. bitsPerDim must be at most 64.
+func NewHilbertND(dims int, bitsPerDim int) (*HilbertND, error) {
+	if dims <= 0 {
+		return nil, ErrNotPositive
+	}
+	if bitsPerDim < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if bitsPerDim > 64 {
+		return nil, ErrTooLarge
+	}
+
+	return &HilbertND{
+		dims: dims,
+		bits: bitsPerDim,
+	}, nil
+}
+
+// Dims returns the number of dimensions of the space.
+func (s *HilbertND) Dims() int {
+	return s.dims
+}
+
+// GetOrder returns the number of bits in each coordinate.
+func (s *HilbertND) GetOrder() int {
+	return s.bits
+}
+
+// Map transforms a one dimension value, t, in the range [0, 2^(dims*bits)-1] to coordinates on
+// the Hilbert curve, each within [0, 2^bits-1].
+func (s *HilbertND) Map(t *big.Int) ([]uint64, error) {
+	if t == nil || !inRange(t, s.dims*s.bits) {
+		return nil, ErrOutOfRange
+	}
+
+	// Spread the bits of t across the transpose, most significant first.
+	coords := make([]uint64, s.dims)
+	for i := 0; i < s.dims*s.bits; i++ {
+		coords[s.dims-1-i%s.dims] |= uint64(t.Bit(i)) << uint(i/s.dims)
+	}
+	if s.bits > 0 {
+		TransposeToAxes(coords, s.bits)
+	}
+	return coords, nil
+}
+
+// MapInverse transform coordinates on the Hilbert curve to t. There must be exactly Dims()
+// coordinates, each within [0, 2^bits-1]. coords is not modified.
+func (s *HilbertND) MapInverse(coords []uint64) (*big.Int, error) {
+	if len(coords) != s.dims {
+		return nil, ErrInvalidLength
+	}
+	for _, c := range coords {
+		if s.bits < 64 && c>>uint(s.bits) != 0 {
+			return nil, ErrOutOfRange
+		}
+	}
+
+	transpose := append([]uint64(nil), coords...)
+	if s.bits > 0 {
+		AxesToTranspose(transpose, s.bits)
+	}
+	t := new(big.Int)
+	for i := 0; i < s.dims*s.bits; i++ {
+		t.SetBit(t, i, uint(transpose[s.dims-1-i%s.dims]>>uint(i/s.dims)&1))
+	}
+	return t, nil
+}
@@ -0,0 +1,116 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestNewHilbertNDErrors(t *testing.T) {
+	testCases := []struct {
+		dims, bits int
+		want       error
+	}{
+		{0, 4, ErrNotPositive},
+		{-1, 4, ErrNotPositive},
+		{2, -1, ErrNegativeOrder},
+		{2, 65, ErrTooLarge},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbertND(tc.dims, tc.bits)
+		if s != nil || err != tc.want {
+			t.Errorf("NewHilbertND(%d, %d) = (%+v, %q) did not fail want (?, %q)", tc.dims, tc.bits, s, err, tc.want)
+		}
+	}
+}
+
+func TestHilbertNDMatchesLowerDimensions(t *testing.T) {
+	h2, _ := NewHilbert(16, false)
+	h3, _ := NewHilbert3D(8)
+	s2, _ := NewHilbertND(2, 4)
+	s3, _ := NewHilbertND(3, 3)
+
+	for d := 0; d < 256; d++ {
+		x, y, _ := h2.Map(d)
+		got, err := s2.Map(big.NewInt(int64(d)))
+		if want := []uint64{uint64(x), uint64(y)}; err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("2D Map(%d) = (%v, %v) want (%v, nil)", d, got, err, want)
+		}
+	}
+	for d := 0; d < 512; d++ {
+		x, y, z, _ := h3.Map(d)
+		got, err := s3.Map(big.NewInt(int64(d)))
+		if want := []uint64{uint64(x), uint64(y), uint64(z)}; err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("3D Map(%d) = (%v, %v) want (%v, nil)", d, got, err, want)
+		}
+	}
+}
+
+func TestHilbertNDRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, tc := range []struct{ dims, bits int }{{1, 8}, {4, 5}, {10, 16}, {3, 64}} {
+		s, err := NewHilbertND(tc.dims, tc.bits)
+		if err != nil {
+			t.Fatalf("NewHilbertND(%d, %d) failed: %s", tc.dims, tc.bits, err)
+		}
+
+		for i := 0; i < 100; i++ {
+			coords := make([]uint64, tc.dims)
+			for j := range coords {
+				coords[j] = r.Uint64()
+				if tc.bits < 64 {
+					coords[j] &= 1<<uint(tc.bits) - 1
+				}
+			}
+
+			d, err := s.MapInverse(coords)
+			if err != nil {
+				t.Fatalf("MapInverse(%v) returned error: %s", coords, err)
+			}
+			if got, err := s.Map(d); err != nil || !reflect.DeepEqual(got, coords) {
+				t.Errorf("Failed MapInverse(%v) -> %v -> Map() -> %v", coords, d, got)
+			}
+		}
+	}
+}
+
+func TestHilbertNDRangeErrors(t *testing.T) {
+	s, _ := NewHilbertND(3, 4)
+
+	tooBig := new(big.Int).Lsh(big.NewInt(1), 12)
+	for _, d := range []*big.Int{nil, big.NewInt(-1), tooBig} {
+		if _, err := s.Map(d); err != ErrOutOfRange {
+			t.Errorf("Map(%v) = %q want %q", d, err, ErrOutOfRange)
+		}
+	}
+
+	if _, err := s.MapInverse([]uint64{0, 0}); err != ErrInvalidLength {
+		t.Errorf("MapInverse([0 0]) = %q want %q", err, ErrInvalidLength)
+	}
+	if _, err := s.MapInverse([]uint64{0, 16, 0}); err != ErrOutOfRange {
+		t.Errorf("MapInverse([0 16 0]) = %q want %q", err, ErrOutOfRange)
+	}
+
+	s64, _ := NewHilbertND(2, 64)
+	if _, err := s64.MapInverse([]uint64{math.MaxUint64, 0}); err != nil {
+		t.Errorf("MapInverse([MaxUint64 0]) returned error: %s", err)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// HilbertU is the same as Hilbert, but uses unsigned values for the index and coordinates, so
+// only the upper bound needs to be checked.
+type HilbertU struct {
+	N                  uint
+	verticalCompatible bool
+}
+
+// NewHilbertU returns a HilbertU space which maps unsigned integers to and from the curve.
+// n must be a power of two. verticalCompatible has the same meaning as in NewHilbert.
+// ErrTooLarge is returned if n is more than 2^MaxOrder, as N*N would overflow a uint.
+func NewHilbertU(n uint, verticalCompatible bool) (*HilbertU, error) {
+	if n == 0 {
+		return nil, ErrNotPositive
+	}
+
+	// Test if power of two
+	if (n & (n - 1)) != 0 {
+		return nil, ErrNotPowerOfTwo
+	}
+
+	if n > 1<<MaxOrder {
+		return nil, ErrTooLarge
+	}
+
+	return &HilbertU{
+		N:                  n,
+		verticalCompatible: verticalCompatible,
+	}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *HilbertU) GetDimensions() (uint, uint) {
+	return s.N, s.N
+}
+
+// Map transforms a one dimension value, t, in the range [0, n^2-1] to coordinates on the Hilbert
+// curve in the two-dimension space, where x and y are within [0,n-1].
+func (s *HilbertU) Map(t uint) (x, y uint, err error) {
+	if t >= s.N*s.N {
+		return 0, 0, ErrOutOfRange
+	}
+
+	for i := uint(1); i < s.N; i = i * 2 {
+		rx := t&2 == 2
+		ry := t&1 == 1
+		if rx {
+			ry = !ry
+		}
+
+		x, y = s.rotate(i, x, y, rx, ry)
+
+		if rx {
+			x = x + i
+		}
+		if ry {
+			y = y + i
+		}
+
+		t /= 4
+	}
+
+	if s.verticalCompatible {
+		// Rotate 90 degrees counter clockwise: swap x and y, then adjust y to match rotation.
+		x, y = y, s.N-1-x
+
+		// Reflect around the X-axis: flip y.
+		y = s.N - 1 - y
+	}
+
+	return
+}
+
+// MapInverse transform coordinates on Hilbert curve from (x,y) to t.
+func (s *HilbertU) MapInverse(x, y uint) (t uint, err error) {
+	if x >= s.N || y >= s.N {
+		return 0, ErrOutOfRange
+	}
+
+	if s.verticalCompatible {
+		// Reverse the X-axis reflection.
+		y = s.N - 1 - y
+
+		// Reverse the 90-degree counter-clockwise rotation.
+		x, y = s.N-1-y, x
+	}
+
+	for i := s.N / 2; i > 0; i = i / 2 {
+		rx := (x & i) > 0
+		ry := (y & i) > 0
+
+		var a uint
+		if rx {
+			a = 3
+		}
+		if ry {
+			a ^= 1
+		}
+		t += i * i * a
+
+		x, y = s.rotate(i, x, y, rx, ry)
+	}
+
+	return
+}
+
+// rotate rotates and flips the quadrant appropriately. As the values are unsigned, n-1-x may
+// wrap around, but the lower bits, which are all that matter, are still correct.
+func (s *HilbertU) rotate(n, x, y uint, rx, ry bool) (uint, uint) {
+	if !ry {
+		if rx {
+			x = n - 1 - x
+			y = n - 1 - y
+		}
+
+		x, y = y, x
+	}
+	return x, y
+}
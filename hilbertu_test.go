@@ -0,0 +1,129 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestNewHilbertUErrors(t *testing.T) {
+	var newTestCases = []struct {
+		n       uint
+		wantErr error
+	}{
+		{0, ErrNotPositive},
+		{3, ErrNotPowerOfTwo},
+		{5, ErrNotPowerOfTwo},
+		{1 << (MaxOrder + 1), ErrTooLarge},
+		{1 << (bits.UintSize - 1), ErrTooLarge},
+	}
+
+	for _, tc := range newTestCases {
+		s, err := NewHilbertU(tc.n, false)
+		if s != nil || err != tc.wantErr {
+			t.Errorf("NewHilbertU(%d) did not fail, want %q, got (%+v, %q)", tc.n, tc.wantErr, s, err)
+		}
+	}
+
+	s, err := NewHilbertU(1<<MaxOrder, false)
+	if err != nil {
+		t.Fatalf("NewHilbertU(1<<MaxOrder) failed: %s", err)
+	}
+	if _, _, err := s.Map(s.N*s.N - 1); err != nil {
+		t.Errorf("Map(N*N-1) returned error: %s", err)
+	}
+}
+
+func TestHilbertURangeErrors(t *testing.T) {
+	s, err := NewHilbertU(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	if _, _, err := s.Map(255); err != nil {
+		t.Errorf("Map(255) returned error: %s", err)
+	}
+	if _, _, err := s.Map(256); err != ErrOutOfRange {
+		t.Errorf("Map(256) = %q want %q", err, ErrOutOfRange)
+	}
+	if _, err := s.MapInverse(16, 0); err != ErrOutOfRange {
+		t.Errorf("MapInverse(16, 0) = %q want %q", err, ErrOutOfRange)
+	}
+	if _, err := s.MapInverse(0, 16); err != ErrOutOfRange {
+		t.Errorf("MapInverse(0, 16) = %q want %q", err, ErrOutOfRange)
+	}
+}
+
+func TestHilbertUMatchesHilbert(t *testing.T) {
+	for _, n := range []int{1, 2, 16, 64} {
+		for _, vertical := range []bool{false, true} {
+			h, err := NewHilbert(n, vertical)
+			if err != nil {
+				t.Fatalf("NewHilbert(%d) failed: %s", n, err)
+			}
+			s, err := NewHilbertU(uint(n), vertical)
+			if err != nil {
+				t.Fatalf("NewHilbertU(%d) failed: %s", n, err)
+			}
+
+			for d := 0; d < n*n; d++ {
+				wantX, wantY, _ := h.Map(d)
+				x, y, err := s.Map(uint(d))
+				if err != nil {
+					t.Errorf("Map(%d) returned error: %s", d, err)
+				}
+				if int(x) != wantX || int(y) != wantY {
+					t.Errorf("NewHilbertU(%d, %t).Map(%d) = (%d, %d) want (%d, %d)", n, vertical, d, x, y, wantX, wantY)
+				}
+
+				got, err := s.MapInverse(x, y)
+				if err != nil {
+					t.Errorf("MapInverse(%d, %d) returned error: %s", x, y, err)
+				}
+				if int(got) != d {
+					t.Errorf("NewHilbertU(%d, %t).MapInverse(%d, %d) = %d want %d", n, vertical, x, y, got, d)
+				}
+			}
+		}
+	}
+}
+
+func BenchmarkHilbertUMap(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s, err := NewHilbertU(benchmarkN, false)
+		if err != nil {
+			b.Fatalf("Failed to create hibert space: %s", err)
+		}
+		for d := uint(0); d < benchmarkN*benchmarkN; d++ {
+			s.Map(d)
+		}
+	}
+}
+
+func BenchmarkHilbertUMapInverse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		s, err := NewHilbertU(benchmarkN, false)
+		if err != nil {
+			b.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		for x := uint(0); x < benchmarkN; x++ {
+			for y := uint(0); y < benchmarkN; y++ {
+				s.MapInverse(x, y)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"iter"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// Join returns an iterator over every pair of indexes (i, j), such that the cells a[i] and b[j]
+// are within the Euclidean distance maxDist of each other. Both sets are sorted along the curve
+// once, and then swept together one aligned block at a time, where the blocks are the smallest
+// squares of the quadtree at least maxDist wide. Each block is a single range of values on the
+// curve, and every match for a point lies in its own block or one of the eight around it, so the
+// points of a in each block are only compared against the points of b in those nine, which are
+// found with one search per block rather than per point. The candidates are then filtered by the
+// exact distance. Pairs are yielded in order of a along the curve, and for each point of a in
+// order of b along the curve. Points outside of the space are never joined, and nothing is
+// yielded if maxDist is negative.
+func (s *Hilbert) Join(a, b [][2]int, maxDist int) iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		if maxDist < 0 {
+			return
+		}
+		r2, ok := mulChecked(maxDist, maxDist)
+		if !ok {
+			r2 = math.MaxInt
+		}
+
+		// The blocks are the cells at the level whose squares are the smallest at least maxDist
+		// wide, so the block of a value on the curve is its top bits.
+		size := 1
+		for size < maxDist && size < s.N {
+			size *= 2
+		}
+		shift := uint(2 * bits.TrailingZeros(uint(size)))
+		blocks := s.N / size
+
+		sa, sb := s.sortedPoints(a), s.sortedPoints(b)
+		block := func(p joinPoint) int {
+			return p.t >> shift
+		}
+
+		var near [][]joinPoint
+		for lo := 0; lo < len(sa); {
+			id := block(sa[lo])
+			hi := lo + 1
+			for hi < len(sa) && block(sa[hi]) == id {
+				hi++
+			}
+
+			// The points of b in the blocks around this one, in curve order.
+			var ids []int
+			bx, by := sa[lo].x/size, sa[lo].y/size
+			for dy := -1; dy <= 1; dy++ {
+				for dx := -1; dx <= 1; dx++ {
+					nx, ny := bx+dx, by+dy
+					if nx < 0 || nx >= blocks || ny < 0 || ny >= blocks {
+						continue
+					}
+					t, _ := s.MapInverse(nx*size, ny*size)
+					ids = append(ids, t>>shift)
+				}
+			}
+			sort.Ints(ids)
+			near = near[:0]
+			for _, nid := range ids {
+				k := sort.Search(len(sb), func(k int) bool { return block(sb[k]) >= nid })
+				end := k
+				for end < len(sb) && block(sb[end]) == nid {
+					end++
+				}
+				near = append(near, sb[k:end])
+			}
+
+			for _, p := range sa[lo:hi] {
+				for _, points := range near {
+					for _, q := range points {
+						dx, dy := p.x-q.x, p.y-q.y
+						if dx*dx+dy*dy <= r2 && !yield(p.i, q.i) {
+							return
+						}
+					}
+				}
+			}
+			lo = hi
+		}
+	}
+}
+
+// joinPoint is a point given to Join, with its value on the curve and index in its set.
+type joinPoint struct {
+	x, y, t, i int
+}
+
+// sortedPoints returns the points within the space, sorted by their values on the curve.
+func (s *Hilbert) sortedPoints(points [][2]int) []joinPoint {
+	sorted := make([]joinPoint, 0, len(points))
+	for i, p := range points {
+		if s.contains(p) {
+			t, _ := s.MapInverse(p[0], p[1])
+			sorted = append(sorted, joinPoint{p[0], p[1], t, i})
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].t < sorted[j].t || sorted[i].t == sorted[j].t && sorted[i].i < sorted[j].i
+	})
+	return sorted
+}
+
+// contains returns true if the cell p is within the space.
+func (s *Hilbert) contains(p [2]int) bool {
+	return p[0] >= 0 && p[0] < s.N && p[1] >= 0 && p[1] < s.N
+}
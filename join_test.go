@@ -0,0 +1,147 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	s, err := NewHilbert(32, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	randomPoints := func(n int) [][2]int {
+		points := make([][2]int, n)
+		for i := range points {
+			points[i] = [2]int{r.Intn(s.N+2) - 1, r.Intn(s.N+2) - 1} // Some are outside
+		}
+		return points
+	}
+
+	for _, maxDist := range []int{0, 1, 3, 8, 40} {
+		a, b := randomPoints(100), randomPoints(200)
+		b = append(b, a[0]) // At least one exact match
+
+		var want [][2]int
+		for i, p := range a {
+			for j, q := range b {
+				dx, dy := p[0]-q[0], p[1]-q[1]
+				if s.contains(p) && s.contains(q) && dx*dx+dy*dy <= maxDist*maxDist {
+					want = append(want, [2]int{i, j})
+				}
+			}
+		}
+
+		var got [][2]int
+		for i, j := range s.Join(a, b, maxDist) {
+			got = append(got, [2]int{i, j})
+		}
+
+		// Only the set of pairs matters here.
+		less := func(pairs [][2]int) func(i, j int) bool {
+			return func(i, j int) bool {
+				return pairs[i][0] < pairs[j][0] || pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1]
+			}
+		}
+		sort.Slice(got, less(got))
+		sort.Slice(want, less(want))
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Join(%d) = %v want %v", maxDist, got, want)
+		}
+	}
+}
+
+func TestJoinStop(t *testing.T) {
+	s, err := NewHilbert(8, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	a := [][2]int{{1, 1}, {2, 2}}
+	b := [][2]int{{1, 1}, {1, 2}, {2, 2}}
+
+	count := 0
+	for range s.Join(a, b, 1) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Join() did not stop, count = %d", count)
+	}
+
+	for range s.Join(a, b, -1) {
+		t.Errorf("Join(-1) yielded a pair, want none")
+	}
+}
+
+func TestJoinLayouts(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	a, b := make([][2]int, 50), make([][2]int, 50)
+	for i := range a {
+		a[i] = [2]int{r.Intn(16), r.Intn(16)}
+		b[i] = [2]int{r.Intn(16), r.Intn(16)}
+	}
+
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(16, true, WithTransform(Transform{Mirror: true, Rotation: 1}))
+	for _, s := range []*Hilbert{h, h.Reversed(), v} {
+		for _, maxDist := range []int{2, 5, math.MaxInt} {
+			want := 0
+			for _, p := range a {
+				for _, q := range b {
+					dx, dy := p[0]-q[0], p[1]-q[1]
+					if maxDist == math.MaxInt || dx*dx+dy*dy <= maxDist*maxDist {
+						want++
+					}
+				}
+			}
+			got := 0
+			for i, j := range s.Join(a, b, maxDist) {
+				if dx, dy := a[i][0]-b[j][0], a[i][1]-b[j][1]; maxDist != math.MaxInt && dx*dx+dy*dy > maxDist*maxDist {
+					t.Errorf("%s.Join(%d) yielded %v and %v", s.Describe(), maxDist, a[i], b[j])
+				}
+				got++
+			}
+			if got != want {
+				t.Errorf("%s.Join(%d) yielded %d pairs want %d", s.Describe(), maxDist, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkJoin(b *testing.B) {
+	s, _ := NewHilbert(1024, false)
+	r := rand.New(rand.NewSource(1))
+	points := make([][2]int, 10000)
+	for i := range points {
+		points[i] = [2]int{r.Intn(s.N), r.Intn(s.N)}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range s.Join(points, points, 8) {
+		}
+	}
+}
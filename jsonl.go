@@ -0,0 +1,55 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// WriteJSONL writes every cell on the curve to w as newline-delimited JSON, one object per line
+// in curve order, such as {"t":96,"x":4,"y":12}. The lines are written as they are generated, so
+// the whole curve is never held in memory.
+func (s *Hilbert) WriteJSONL(w io.Writer) error {
+	return s.WriteJSONLRange(w, 0, s.N*s.N-1)
+}
+
+// WriteJSONLRange is like WriteJSONL, but only writes the cells with t in the range [lo, hi].
+func (s *Hilbert) WriteJSONLRange(w io.Writer, lo, hi int) error {
+	if err := s.validRange(lo, hi, "lo", "hi"); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	progress := s.newProgress(hi - lo + 1)
+	var buf []byte
+	for t := lo; t <= hi; t++ {
+		x, y, _ := s.Map(t)
+
+		buf = append(buf[:0], `{"t":`...)
+		buf = strconv.AppendInt(buf, int64(t), 10)
+		buf = append(buf, `,"x":`...)
+		buf = strconv.AppendInt(buf, int64(x), 10)
+		buf = append(buf, `,"y":`...)
+		buf = strconv.AppendInt(buf, int64(y), 10)
+		buf = append(buf, "}\n"...)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		progress.report(t - lo + 1)
+	}
+	return bw.Flush()
+}
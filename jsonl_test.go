@@ -0,0 +1,79 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWriteJSONL(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteJSONL(&buf); err != nil {
+		t.Fatalf("WriteJSONL() returned error: %s", err)
+	}
+
+	want := `{"t":0,"x":0,"y":0}
+{"t":1,"x":0,"y":1}
+{"t":2,"x":1,"y":1}
+{"t":3,"x":1,"y":0}
+`
+	if got := buf.String(); got != want {
+		t.Errorf("WriteJSONL() = %q want %q", got, want)
+	}
+}
+
+func TestWriteJSONLRange(t *testing.T) {
+	s, err := NewHilbert(16, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var buf bytes.Buffer
+	if err := s.WriteJSONLRange(&buf, 90, 100); err != nil {
+		t.Fatalf("WriteJSONLRange(90, 100) returned error: %s", err)
+	}
+
+	want := 90
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var p struct{ T, X, Y int }
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			t.Fatalf("WriteJSONLRange(90, 100) wrote invalid line %q: %s", scanner.Text(), err)
+		}
+		x, y, _ := s.Map(want)
+		if p.T != want || p.X != x || p.Y != y {
+			t.Errorf("WriteJSONLRange(90, 100) wrote %+v want {T:%d X:%d Y:%d}", p, want, x, y)
+		}
+		want++
+	}
+	if want != 101 {
+		t.Errorf("WriteJSONLRange(90, 100) wrote up to %d want 100", want-1)
+	}
+
+	for _, r := range []Range{{-1, 0}, {0, 256}, {5, 4}} {
+		if err := s.WriteJSONLRange(&buf, r.Lo, r.Hi); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("WriteJSONLRange(%d, %d) = %q want %q", r.Lo, r.Hi, err, ErrOutOfRange)
+		}
+	}
+}
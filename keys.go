@@ -0,0 +1,117 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "encoding/binary"
+
+// compositeExtraBytes is the number of bytes used by the extra value in a composite key.
+const compositeExtraBytes = 4
+
+// compositeIndexBytes returns the number of bytes used by the Hilbert index in a composite key.
+func (s *Hilbert) compositeIndexBytes() int {
+	return (2*s.GetOrder() + 7) / 8
+}
+
+// CompositeKey returns a key made up of the Hilbert index of (x,y) followed by extra, for example
+// a timestamp. The index is stored big-endian in the first ceil(2*GetOrder()/8) bytes, and extra
+// is stored big-endian in the last 4 bytes. As all keys for a curve have the same length,
+// comparing keys byte by byte orders them by Hilbert index first, and then by extra.
+func (s *Hilbert) CompositeKey(x, y int, extra uint32) ([]byte, error) {
+	t, err := s.MapInverse(x, y)
+	if err != nil {
+		return nil, err
+	}
+
+	n := s.compositeIndexBytes()
+	key := make([]byte, n+compositeExtraBytes)
+	for i := n - 1; i >= 0; i-- {
+		key[i] = byte(t)
+		t >>= 8
+	}
+	binary.BigEndian.PutUint32(key[n:], extra)
+	return key, nil
+}
+
+// FromCompositeKey is the inverse of CompositeKey, returning the coordinates and extra value
+// stored in the key. ErrOutOfRange is returned if the index stored in the key is not on the curve,
+// whatever the bounds policy.
+func (s *Hilbert) FromCompositeKey(key []byte) (x, y int, extra uint32, err error) {
+	n := s.compositeIndexBytes()
+	if len(key) != n+compositeExtraBytes {
+		return -1, -1, 0, ErrInvalidLength
+	}
+
+	t := 0
+	for _, b := range key[:n] {
+		t = t<<8 | int(b)
+	}
+	if t < 0 || t >= s.N*s.N {
+		return -1, -1, 0, ErrOutOfRange
+	}
+	x, y = s.mapValid(t)
+	return x, y, binary.BigEndian.Uint32(key[n:]), nil
+}
+
+// keyBytes is the length of the keys written by EncodeKey.
+const keyBytes = 8
+
+// EncodeKey returns t as an 8 byte big-endian key, so comparing keys byte by byte, as ordered key
+// value stores do, orders them the same as the values on the curve.
+func EncodeKey(t uint64) []byte {
+	return AppendKey(nil, t)
+}
+
+// AppendKey appends the key for t, as returned by EncodeKey, to dst and returns the result.
+func AppendKey(dst []byte, t uint64) []byte {
+	return binary.BigEndian.AppendUint64(dst, t)
+}
+
+// DecodeKey is the inverse of EncodeKey. ErrInvalidLength is returned if the key is not 8 bytes.
+func DecodeKey(key []byte) (uint64, error) {
+	if len(key) != keyBytes {
+		return 0, ErrInvalidLength
+	}
+	return binary.BigEndian.Uint64(key), nil
+}
+
+// EncodeLevelKey is like EncodeKey, but prefixes the key with a byte holding the order of the
+// curve t is on, such as the level of a CellID, so keys for several levels can share a store.
+// Keys are then ordered by level first, and then along the curve. ErrOutOfRange is returned if
+// level is not within [0, MaxOrder64], or t is not on the curve of that order.
+func EncodeLevelKey(level int, t uint64) ([]byte, error) {
+	if !onCurveOfOrder(level, t) {
+		return nil, ErrOutOfRange
+	}
+	return AppendKey([]byte{byte(level)}, t), nil
+}
+
+// onCurveOfOrder returns true if order is within [0, MaxOrder64], and t is on the curve of that
+// order.
+func onCurveOfOrder(order int, t uint64) bool {
+	return order >= 0 && order <= MaxOrder64 && (order == MaxOrder64 || t>>uint(2*order) == 0)
+}
+
+// DecodeLevelKey is the inverse of EncodeLevelKey. ErrInvalidLength is returned if the key is not
+// 9 bytes, and ErrOutOfRange if it does not hold a valid level and value.
+func DecodeLevelKey(key []byte) (level int, t uint64, err error) {
+	if len(key) != 1+keyBytes {
+		return -1, 0, ErrInvalidLength
+	}
+	level, t = int(key[0]), binary.BigEndian.Uint64(key[1:])
+	if !onCurveOfOrder(level, t) {
+		return -1, 0, ErrOutOfRange
+	}
+	return level, t, nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompositeKey(t *testing.T) {
+	testCases := []struct {
+		n, x, y int
+		extra   uint32
+		want    []byte
+	}{
+		{1, 0, 0, 0x01020304, []byte{0x01, 0x02, 0x03, 0x04}},
+		{16, 4, 12, 7, []byte{96, 0, 0, 0, 7}},
+		{16, 15, 0, 0xffffffff, []byte{255, 0xff, 0xff, 0xff, 0xff}},
+		{32, 31, 0, 1, []byte{0x03, 0xff, 0, 0, 0, 1}},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(tc.n, false)
+		if err != nil {
+			t.Fatalf("NewHilbert(%d) failed: %s", tc.n, err)
+		}
+
+		got, err := s.CompositeKey(tc.x, tc.y, tc.extra)
+		if err != nil {
+			t.Errorf("CompositeKey(%d, %d, %d) returned error: %s", tc.x, tc.y, tc.extra, err)
+		}
+		if !bytes.Equal(got, tc.want) {
+			t.Errorf("NewHilbert(%d).CompositeKey(%d, %d, %d) = %v want %v", tc.n, tc.x, tc.y, tc.extra, got, tc.want)
+		}
+
+		x, y, extra, err := s.FromCompositeKey(got)
+		if err != nil {
+			t.Errorf("FromCompositeKey(%v) returned error: %s", got, err)
+		}
+		if x != tc.x || y != tc.y || extra != tc.extra {
+			t.Errorf("FromCompositeKey(%v) = (%d, %d, %d) want (%d, %d, %d)", got, x, y, extra, tc.x, tc.y, tc.extra)
+		}
+	}
+}
+
+func TestCompositeKeyErrors(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	if _, err := s.CompositeKey(16, 0, 0); err != ErrOutOfRange {
+		t.Errorf("CompositeKey(16, 0, 0) = %q want %q", err, ErrOutOfRange)
+	}
+	if _, _, _, err := s.FromCompositeKey([]byte{0, 0, 0, 0}); err != ErrInvalidLength {
+		t.Errorf("FromCompositeKey([0 0 0 0]) = %q want %q", err, ErrInvalidLength)
+	}
+
+	s, err = NewHilbert(8, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	if _, _, _, err := s.FromCompositeKey([]byte{64, 0, 0, 0, 0}); err != ErrOutOfRange {
+		t.Errorf("FromCompositeKey([64 0 0 0 0]) = %q want %q", err, ErrOutOfRange)
+	}
+
+	s, err = NewHilbert(8, false, WithBoundsPolicy(BoundsWrap))
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	if _, _, _, err := s.FromCompositeKey([]byte{0xff, 0, 0, 0, 7}); err != ErrOutOfRange {
+		t.Errorf("FromCompositeKey([255 0 0 0 7]) with BoundsWrap = %q want %q", err, ErrOutOfRange)
+	}
+}
+
+func TestCompositeKeyOrder(t *testing.T) {
+	s, err := NewHilbert(16, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var prev []byte
+	for d := 0; d < s.N*s.N; d++ {
+		x, y, _ := s.Map(d)
+		for _, extra := range []uint32{0, 1, 0xffffffff} {
+			key, err := s.CompositeKey(x, y, extra)
+			if err != nil {
+				t.Fatalf("CompositeKey(%d, %d, %d) returned error: %s", x, y, extra, err)
+			}
+			if prev != nil && bytes.Compare(prev, key) >= 0 {
+				t.Errorf("CompositeKey(%d, %d, %d) = %v, not after the previous key %v", x, y, extra, key, prev)
+			}
+			prev = key
+		}
+	}
+}
+
+func TestEncodeKey(t *testing.T) {
+	values := []uint64{0, 1, 255, 256, 1 << 32, 1<<63 + 5, 1<<64 - 1}
+	var prev []byte
+	for _, v := range values {
+		key := EncodeKey(v)
+		if len(key) != 8 {
+			t.Errorf("EncodeKey(%d) = %x want 8 bytes", v, key)
+		}
+		if prev != nil && bytes.Compare(prev, key) >= 0 {
+			t.Errorf("EncodeKey(%d) = %x, not after the previous key %x", v, key, prev)
+		}
+		prev = key
+
+		if got, err := DecodeKey(key); err != nil || got != v {
+			t.Errorf("DecodeKey(%x) = (%d, %v) want (%d, nil)", key, got, err, v)
+		}
+	}
+
+	if got := AppendKey([]byte{9}, 258); !bytes.Equal(got, []byte{9, 0, 0, 0, 0, 0, 0, 1, 2}) {
+		t.Errorf("AppendKey([9], 258) = %x want 090000000000000102", got)
+	}
+	for _, key := range [][]byte{nil, make([]byte, 7), make([]byte, 9)} {
+		if _, err := DecodeKey(key); err != ErrInvalidLength {
+			t.Errorf("DecodeKey(%x) = %v want %v", key, err, ErrInvalidLength)
+		}
+	}
+}
+
+func TestEncodeLevelKey(t *testing.T) {
+	testCases := []struct {
+		level int
+		t     uint64
+	}{
+		{0, 0},
+		{1, 3},
+		{2, 0},
+		{2, 15},
+		{16, 1<<32 - 1},
+		{MaxOrder64, 1<<64 - 1},
+	}
+
+	var prev []byte
+	for _, tc := range testCases {
+		key, err := EncodeLevelKey(tc.level, tc.t)
+		if err != nil {
+			t.Fatalf("EncodeLevelKey(%d, %d) returned error: %s", tc.level, tc.t, err)
+		}
+		if prev != nil && bytes.Compare(prev, key) >= 0 {
+			t.Errorf("EncodeLevelKey(%d, %d) = %x, not after the previous key %x", tc.level, tc.t, key, prev)
+		}
+		prev = key
+
+		level, got, err := DecodeLevelKey(key)
+		if err != nil || level != tc.level || got != tc.t {
+			t.Errorf("DecodeLevelKey(%x) = (%d, %d, %v) want (%d, %d, nil)", key, level, got, err, tc.level, tc.t)
+		}
+	}
+
+	for _, tc := range []struct {
+		level int
+		t     uint64
+	}{{-1, 0}, {0, 1}, {2, 16}, {MaxOrder64 + 1, 0}} {
+		if _, err := EncodeLevelKey(tc.level, tc.t); err != ErrOutOfRange {
+			t.Errorf("EncodeLevelKey(%d, %d) = %v want %v", tc.level, tc.t, err, ErrOutOfRange)
+		}
+	}
+	if _, _, err := DecodeLevelKey(EncodeKey(0)); err != ErrInvalidLength {
+		t.Errorf("DecodeLevelKey(EncodeKey(0)) = %v want %v", err, ErrInvalidLength)
+	}
+	if _, _, err := DecodeLevelKey(append([]byte{1}, EncodeKey(4)...)); err != ErrOutOfRange {
+		t.Errorf("DecodeLevelKey(level 1, 4) = %v want %v", err, ErrOutOfRange)
+	}
+}
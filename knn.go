@@ -0,0 +1,100 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"sort"
+)
+
+// neighbor is a candidate found by KNearest, with its squared distance from the query.
+type neighbor struct {
+	t    uint64
+	dist int
+}
+
+// KNearest returns the k values in indices whose cells are nearest to the cell (x,y) by Euclidean
+// distance, in order of increasing distance, with ties broken by the smaller value. indices must
+// be sorted, and may contain duplicates, such as for several points in the same cell, which are
+// each returned separately. Values not on the curve are ignored. Fewer than k values are returned
+// if indices does not hold k values on the curve, and nil if (x,y) is not within the space or k
+// is less than one.
+//
+// Cells close together along the curve are close together in the space, so the k values either
+// side of the query's own value give a first guess at the k nearest. The distance to the furthest
+// of these then bounds the true k nearest, which are found by reading only the ranges returned by
+// RangeQuery for the square around (x,y) reaching that distance.
+func (s *Hilbert) KNearest(indices []uint64, x, y, k int) []uint64 {
+	t, err := s.MapInverse(x, y)
+	if err != nil || k < 1 {
+		return nil
+	}
+	size := uint64(s.N) * uint64(s.N)
+	end := sort.Search(len(indices), func(i int) bool { return indices[i] >= size })
+	indices = indices[:end]
+	if len(indices) == 0 {
+		return nil
+	}
+	k = min(k, len(indices))
+
+	dist := func(v uint64) int {
+		vx, vy, _ := s.Map(int(v))
+		return (vx-x)*(vx-x) + (vy-y)*(vy-y)
+	}
+	nearest := func(candidates []neighbor) []neighbor {
+		sort.Slice(candidates, func(i, j int) bool {
+			if candidates[i].dist != candidates[j].dist {
+				return candidates[i].dist < candidates[j].dist
+			}
+			return candidates[i].t < candidates[j].t
+		})
+		return candidates[:min(k, len(candidates))]
+	}
+
+	// Guess from the values either side of t along the curve.
+	p := sort.Search(len(indices), func(i int) bool { return indices[i] >= uint64(t) })
+	var candidates []neighbor
+	for _, v := range indices[max(0, p-k):min(len(indices), p+k)] {
+		candidates = append(candidates, neighbor{v, dist(v)})
+	}
+	guess := nearest(candidates)
+	bound := guess[len(guess)-1].dist
+	r := int(math.Sqrt(float64(bound)))
+	for r*r < bound {
+		r++
+	}
+
+	// Every value at least as near as the furthest guess lies within r, so reading the ranges
+	// covering the square within r finds the true k nearest. The square needs far fewer ranges than
+	// the disc given by IndicesWithinRadius, which outweighs reading the cells in its corners.
+	candidates = candidates[:0]
+	i := 0
+	s.rangeQuery(max(0, x-r), max(0, y-r), min(s.N-1, x+r), min(s.N-1, y+r), 0, s.N, func(rg Range) bool {
+		rest := indices[i:]
+		i += sort.Search(len(rest), func(j int) bool { return rest[j] >= uint64(rg.Lo) })
+		for ; i < len(indices) && indices[i] <= uint64(rg.Hi); i++ {
+			if d := dist(indices[i]); d <= bound {
+				candidates = append(candidates, neighbor{indices[i], d})
+			}
+		}
+		return true
+	})
+
+	result := make([]uint64, 0, k)
+	for _, n := range nearest(candidates) {
+		result = append(result, n.t)
+	}
+	return result
+}
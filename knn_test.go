@@ -0,0 +1,115 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestKNearest(t *testing.T) {
+	s, err := NewHilbert(8, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	at := func(x, y int) uint64 {
+		v, _ := s.MapInverse(x, y)
+		return uint64(v)
+	}
+	indices := []uint64{at(0, 0), at(3, 3), at(4, 3), at(7, 7), at(3, 5)}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	testCases := []struct {
+		x, y, k int
+		want    []uint64
+	}{
+		{3, 3, 1, []uint64{at(3, 3)}},
+		{3, 4, 3, []uint64{at(3, 3), at(3, 5), at(4, 3)}},
+		{0, 1, 2, []uint64{at(0, 0), at(3, 3)}},
+		{7, 6, 10, []uint64{at(7, 7), at(3, 5), at(4, 3), at(3, 3), at(0, 0)}},
+		{3, 3, 0, nil},
+		{-1, 3, 1, nil},
+		{3, 8, 1, nil},
+	}
+
+	for _, tc := range testCases {
+		if got := s.KNearest(indices, tc.x, tc.y, tc.k); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("KNearest(%d, %d, %d) = %v want %v", tc.x, tc.y, tc.k, got, tc.want)
+		}
+	}
+}
+
+func TestKNearestDuplicatesAndOffCurve(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	indices := []uint64{2, 2, 2, 9, 16, 100}
+	want := []uint64{2, 2, 2, 9}
+	if got := s.KNearest(indices, 0, 0, 5); !reflect.DeepEqual(got, want) {
+		t.Errorf("KNearest(0, 0, 5) = %v want %v", got, want)
+	}
+	if got := s.KNearest([]uint64{16, 17}, 0, 0, 1); got != nil {
+		t.Errorf("KNearest(0, 0, 1) = %v want nil", got)
+	}
+}
+
+func TestKNearestRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	h, _ := NewHilbert(64, false)
+	v, _ := NewHilbert(64, true)
+	for _, s := range []*Hilbert{h, v, h.Reversed()} {
+		for i := 0; i < 100; i++ {
+			indices := make([]uint64, r.Intn(200)+1)
+			for j := range indices {
+				indices[j] = uint64(r.Intn(64 * 64))
+			}
+			sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+			x, y, k := r.Intn(64), r.Intn(64), r.Intn(20)+1
+
+			// Brute force, sorting every value by distance and then by value.
+			dist := func(v uint64) int {
+				vx, vy, _ := s.Map(int(v))
+				return (vx-x)*(vx-x) + (vy-y)*(vy-y)
+			}
+			all := append([]uint64(nil), indices...)
+			sort.SliceStable(all, func(i, j int) bool { return dist(all[i]) < dist(all[j]) })
+			want := all[:min(k, len(all))]
+
+			if got := s.KNearest(indices, x, y, k); !reflect.DeepEqual(got, want) {
+				t.Errorf("KNearest(%d, %d, %d) = %v want %v", x, y, k, got, want)
+			}
+		}
+	}
+}
+
+func BenchmarkKNearest(b *testing.B) {
+	s, _ := NewHilbert(1024, false)
+	r := rand.New(rand.NewSource(1))
+	indices := make([]uint64, 10000)
+	for i := range indices {
+		indices[i] = uint64(r.Intn(1024 * 1024))
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.KNearest(indices, i%1024, (i/1024)%1024, 10)
+	}
+}
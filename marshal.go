@@ -0,0 +1,351 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// curveConfig is the configuration of a curve, as marshalled to text and JSON. Curve is the kind
+// of curve, such as "hilbert", and the remaining fields only apply to Hilbert curves.
+type curveConfig struct {
+	Curve    string `json:"curve"`
+	N        int    `json:"n"`
+	Vertical bool   `json:"vertical,omitempty"`
+	Reversed bool   `json:"reversed,omitempty"`
+	Mirror   bool   `json:"mirror,omitempty"`
+	Rotation int    `json:"rotation,omitempty"`
+}
+
+// text returns the configuration as space separated fields, the kind and N followed by any flags
+// which are set, such as "hilbert 16 vertical reversed mirror rotation=1".
+func (c curveConfig) text() []byte {
+	fields := []string{c.Curve, strconv.Itoa(c.N)}
+	for _, flag := range []struct {
+		set  bool
+		name string
+	}{
+		{c.Vertical, "vertical"},
+		{c.Reversed, "reversed"},
+		{c.Mirror, "mirror"},
+	} {
+		if flag.set {
+			fields = append(fields, flag.name)
+		}
+	}
+	if c.Rotation != 0 {
+		fields = append(fields, "rotation="+strconv.Itoa(c.Rotation))
+	}
+	return []byte(strings.Join(fields, " "))
+}
+
+// parseConfig is the inverse of curveConfig.text. ErrInvalidConfig is returned if the text is
+// not a configuration of the given kind of curve.
+func parseConfig(text []byte, curve string) (curveConfig, error) {
+	fields := strings.Fields(string(text))
+	if len(fields) < 2 || fields[0] != curve {
+		return curveConfig{}, ErrInvalidConfig
+	}
+	c := curveConfig{Curve: curve}
+	var err error
+	if c.N, err = strconv.Atoi(fields[1]); err != nil {
+		return curveConfig{}, ErrInvalidConfig
+	}
+
+	for _, f := range fields[2:] {
+		switch {
+		case f == "vertical":
+			c.Vertical = true
+		case f == "reversed":
+			c.Reversed = true
+		case f == "mirror":
+			c.Mirror = true
+		case strings.HasPrefix(f, "rotation="):
+			if c.Rotation, err = strconv.Atoi(strings.TrimPrefix(f, "rotation=")); err != nil {
+				return curveConfig{}, ErrInvalidConfig
+			}
+		default:
+			return curveConfig{}, ErrInvalidConfig
+		}
+	}
+	return c, nil
+}
+
+// parseJSONConfig is the same as parseConfig, but for JSON.
+func parseJSONConfig(data []byte, curve string) (curveConfig, error) {
+	var c curveConfig
+	if err := json.Unmarshal(data, &c); err != nil {
+		return curveConfig{}, err
+	}
+	if c.Curve != curve {
+		return curveConfig{}, ErrInvalidConfig
+	}
+	return c, nil
+}
+
+// squareOnly returns ErrInvalidConfig if c has any flags set, which only apply to Hilbert curves.
+func (c curveConfig) squareOnly() error {
+	if c.Vertical || c.Reversed || c.Mirror || c.Rotation != 0 {
+		return ErrInvalidConfig
+	}
+	return nil
+}
+
+// config returns the configuration of the curve to marshal.
+func (s *Hilbert) config() curveConfig {
+	c := s.Config()
+	return curveConfig{
+		Curve:    "hilbert",
+		N:        c.N,
+		Vertical: c.VerticalCompatible,
+		Reversed: c.Transform.Reversed,
+		Mirror:   c.Transform.Mirror,
+		Rotation: c.Transform.Rotation,
+	}
+}
+
+// setConfig replaces the curve with a new one created from c. Options such as WithBoundsPolicy
+// are not part of the marshalled configuration, so are reset.
+func (s *Hilbert) setConfig(c curveConfig) error {
+	curve, err := NewHilbertFromConfig(Config{
+		N:                  c.N,
+		VerticalCompatible: c.Vertical,
+		Transform:          Transform{Mirror: c.Mirror, Rotation: c.Rotation, Reversed: c.Reversed},
+	})
+	if err == ErrOutOfRange {
+		return ErrInvalidConfig
+	}
+	if err != nil {
+		return err
+	}
+	*s = *curve
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the configuration of the curve as
+// text, such as "hilbert 16 vertical reversed". Any transform added with WithTransform is
+// included, but other options, such as WithBoundsPolicy, are not.
+func (s *Hilbert) MarshalText() ([]byte, error) {
+	return s.config().text(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the curve with one created from
+// text written by MarshalText. ErrInvalidConfig is returned if the text is malformed, and any
+// error from NewHilbert if N is invalid.
+func (s *Hilbert) UnmarshalText(text []byte) error {
+	c, err := parseConfig(text, "hilbert")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// MarshalJSON implements json.Marshaler, returning the configuration of the curve as an object,
+// such as {"curve":"hilbert","n":16,"vertical":true}, with the same fields as MarshalText.
+func (s *Hilbert) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.config())
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the curve with one created from JSON
+// written by MarshalJSON, and returning errors in the same way as UnmarshalText.
+func (s *Hilbert) UnmarshalJSON(data []byte) error {
+	c, err := parseJSONConfig(data, "hilbert")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the configuration of the curve as
+// text, such as "peano 27".
+func (p *Peano) MarshalText() ([]byte, error) {
+	return curveConfig{Curve: "peano", N: p.N}.text(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the curve with one created from
+// text written by MarshalText.
+func (p *Peano) UnmarshalText(text []byte) error {
+	c, err := parseConfig(text, "peano")
+	if err != nil {
+		return err
+	}
+	return p.setConfig(c)
+}
+
+// MarshalJSON implements json.Marshaler, returning the configuration of the curve as an object,
+// such as {"curve":"peano","n":27}.
+func (p *Peano) MarshalJSON() ([]byte, error) {
+	return json.Marshal(curveConfig{Curve: "peano", N: p.N})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the curve with one created from JSON
+// written by MarshalJSON.
+func (p *Peano) UnmarshalJSON(data []byte) error {
+	c, err := parseJSONConfig(data, "peano")
+	if err != nil {
+		return err
+	}
+	return p.setConfig(c)
+}
+
+// setConfig replaces the curve with a new one created from c.
+func (p *Peano) setConfig(c curveConfig) error {
+	if err := c.squareOnly(); err != nil {
+		return err
+	}
+	curve, err := NewPeano(c.N)
+	if err != nil {
+		return err
+	}
+	*p = *curve
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the configuration of the curve as
+// text, such as "morton 16".
+func (s *Morton) MarshalText() ([]byte, error) {
+	return curveConfig{Curve: "morton", N: s.N}.text(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the curve with one created from
+// text written by MarshalText.
+func (s *Morton) UnmarshalText(text []byte) error {
+	c, err := parseConfig(text, "morton")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// MarshalJSON implements json.Marshaler, returning the configuration of the curve as an object,
+// such as {"curve":"morton","n":16}.
+func (s *Morton) MarshalJSON() ([]byte, error) {
+	return json.Marshal(curveConfig{Curve: "morton", N: s.N})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the curve with one created from JSON
+// written by MarshalJSON.
+func (s *Morton) UnmarshalJSON(data []byte) error {
+	c, err := parseJSONConfig(data, "morton")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// setConfig replaces the curve with a new one created from c.
+func (s *Morton) setConfig(c curveConfig) error {
+	if err := c.squareOnly(); err != nil {
+		return err
+	}
+	curve, err := NewMorton(c.N)
+	if err != nil {
+		return err
+	}
+	*s = *curve
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the configuration of the curve as
+// text, such as "gray 16".
+func (s *Gray) MarshalText() ([]byte, error) {
+	return curveConfig{Curve: "gray", N: s.N}.text(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the curve with one created from
+// text written by MarshalText.
+func (s *Gray) UnmarshalText(text []byte) error {
+	c, err := parseConfig(text, "gray")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// MarshalJSON implements json.Marshaler, returning the configuration of the curve as an object,
+// such as {"curve":"gray","n":16}.
+func (s *Gray) MarshalJSON() ([]byte, error) {
+	return json.Marshal(curveConfig{Curve: "gray", N: s.N})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the curve with one created from JSON
+// written by MarshalJSON.
+func (s *Gray) UnmarshalJSON(data []byte) error {
+	c, err := parseJSONConfig(data, "gray")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// setConfig replaces the curve with a new one created from c.
+func (s *Gray) setConfig(c curveConfig) error {
+	if err := c.squareOnly(); err != nil {
+		return err
+	}
+	curve, err := NewGray(c.N)
+	if err != nil {
+		return err
+	}
+	*s = *curve
+	return nil
+}
+
+// MarshalText implements encoding.TextMarshaler, returning the configuration of the curve as
+// text, such as "moore 16".
+func (s *Moore) MarshalText() ([]byte, error) {
+	return curveConfig{Curve: "moore", N: s.N}.text(), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, replacing the curve with one created from
+// text written by MarshalText.
+func (s *Moore) UnmarshalText(text []byte) error {
+	c, err := parseConfig(text, "moore")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// MarshalJSON implements json.Marshaler, returning the configuration of the curve as an object,
+// such as {"curve":"moore","n":16}.
+func (s *Moore) MarshalJSON() ([]byte, error) {
+	return json.Marshal(curveConfig{Curve: "moore", N: s.N})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, replacing the curve with one created from JSON
+// written by MarshalJSON.
+func (s *Moore) UnmarshalJSON(data []byte) error {
+	c, err := parseJSONConfig(data, "moore")
+	if err != nil {
+		return err
+	}
+	return s.setConfig(c)
+}
+
+// setConfig replaces the curve with a new one created from c.
+func (s *Moore) setConfig(c curveConfig) error {
+	if err := c.squareOnly(); err != nil {
+		return err
+	}
+	curve, err := NewMoore(c.N)
+	if err != nil {
+		return err
+	}
+	*s = *curve
+	return nil
+}
@@ -0,0 +1,153 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+func TestHilbertMarshalText(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(8, true)
+	rotated, _ := NewHilbert(4, true, WithTransform(Transform{Mirror: true, Rotation: 1}))
+
+	testCases := []struct {
+		s    *Hilbert
+		want string
+	}{
+		{h, "hilbert 16"},
+		{h.Reversed(), "hilbert 16 reversed"},
+		{v, "hilbert 8 vertical"},
+		{rotated, "hilbert 4 vertical mirror rotation=1"},
+		{rotated.Reversed(), "hilbert 4 vertical reversed mirror rotation=1"},
+	}
+
+	for _, tc := range testCases {
+		text, err := tc.s.MarshalText()
+		if err != nil || string(text) != tc.want {
+			t.Errorf("%s MarshalText() = (%q, %v) want (%q, nil)", DescribeCurve(tc.s), text, err, tc.want)
+		}
+
+		var got Hilbert
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("UnmarshalText(%q) returned error: %s", text, err)
+		}
+		if !tc.s.transformedBy(&got, Transform{}, tc.s.N*tc.s.N-1) || got.verticalCompatible != tc.s.verticalCompatible {
+			t.Errorf("UnmarshalText(%q) = %s want %s", text, DescribeCurve(&got), DescribeCurve(tc.s))
+		}
+	}
+}
+
+func TestHilbertMarshalJSON(t *testing.T) {
+	// The curve is stored as an object within a larger configuration.
+	type config struct {
+		Name  string
+		Curve *Hilbert
+	}
+	s, _ := NewHilbert(32, true, WithTransform(Transform{Rotation: 2, Reversed: true}))
+
+	data, err := json.Marshal(config{"index", s})
+	if err != nil {
+		t.Fatalf("json.Marshal() returned error: %s", err)
+	}
+	if want := `{"Name":"index","Curve":{"curve":"hilbert","n":32,"vertical":true,"reversed":true,"rotation":2}}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s want %s", data, want)
+	}
+
+	var got config
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("json.Unmarshal(%s) returned error: %s", data, err)
+	}
+	if got.Curve == nil || !s.transformedBy(got.Curve, Transform{}, 32*32-1) {
+		t.Errorf("json.Unmarshal(%s) = %s want %s", data, DescribeCurve(got.Curve), DescribeCurve(s))
+	}
+}
+
+func TestUnmarshalTextErrors(t *testing.T) {
+	testCases := []struct {
+		c    encoding.TextUnmarshaler
+		text string
+		want error
+	}{
+		{&Hilbert{}, "", ErrInvalidConfig},
+		{&Hilbert{}, "hilbert", ErrInvalidConfig},
+		{&Hilbert{}, "peano 27", ErrInvalidConfig},
+		{&Hilbert{}, "hilbert sixteen", ErrInvalidConfig},
+		{&Hilbert{}, "hilbert 16 sideways", ErrInvalidConfig},
+		{&Hilbert{}, "hilbert 16 rotation=4", ErrInvalidConfig},
+		{&Hilbert{}, "hilbert 16 rotation=x", ErrInvalidConfig},
+		{&Hilbert{}, "hilbert 12", ErrNotPowerOfTwo},
+		{&Peano{}, "peano 27 vertical", ErrInvalidConfig},
+		{&Peano{}, "peano 8", ErrNotPowerOfThree},
+		{&Morton{}, "morton 0", ErrNotPositive},
+		{&Moore{}, "moore 1", ErrOrderTooSmall},
+		{&Gray{}, "gray 16 reversed", ErrInvalidConfig},
+		{&Gray{}, "gray 12", ErrNotPowerOfTwo},
+	}
+
+	for _, tc := range testCases {
+		if err := tc.c.UnmarshalText([]byte(tc.text)); err != tc.want {
+			t.Errorf("%T.UnmarshalText(%q) = %v want %v", tc.c, tc.text, err, tc.want)
+		}
+	}
+}
+
+func TestMarshalOtherCurves(t *testing.T) {
+	p, _ := NewPeano(27)
+	m, _ := NewMorton(16)
+	moore, _ := NewMoore(8)
+	g, _ := NewGray(32)
+
+	testCases := []struct {
+		c        SpaceFilling
+		text     string
+		json     string
+		newEmpty func() SpaceFilling
+	}{
+		{p, "peano 27", `{"curve":"peano","n":27}`, func() SpaceFilling { return &Peano{} }},
+		{m, "morton 16", `{"curve":"morton","n":16}`, func() SpaceFilling { return &Morton{} }},
+		{moore, "moore 8", `{"curve":"moore","n":8}`, func() SpaceFilling { return &Moore{} }},
+		{g, "gray 32", `{"curve":"gray","n":32}`, func() SpaceFilling { return &Gray{} }},
+	}
+
+	for _, tc := range testCases {
+		if text, err := tc.c.(encoding.TextMarshaler).MarshalText(); err != nil || string(text) != tc.text {
+			t.Errorf("%s MarshalText() = (%q, %v) want (%q, nil)", DescribeCurve(tc.c), text, err, tc.text)
+		}
+		if data, err := json.Marshal(tc.c); err != nil || string(data) != tc.json {
+			t.Errorf("%s json.Marshal() = (%s, %v) want (%s, nil)", DescribeCurve(tc.c), data, err, tc.json)
+		}
+
+		fromText, fromJSON := tc.newEmpty(), tc.newEmpty()
+		if err := fromText.(encoding.TextUnmarshaler).UnmarshalText([]byte(tc.text)); err != nil {
+			t.Errorf("UnmarshalText(%q) returned error: %s", tc.text, err)
+		}
+		if err := json.Unmarshal([]byte(tc.json), fromJSON); err != nil {
+			t.Errorf("json.Unmarshal(%s) returned error: %s", tc.json, err)
+		}
+		for _, got := range []SpaceFilling{fromText, fromJSON} {
+			if DescribeCurve(got) != DescribeCurve(tc.c) {
+				t.Errorf("Unmarshalled %s want %s", DescribeCurve(got), DescribeCurve(tc.c))
+			}
+		}
+	}
+
+	var s Morton
+	if err := json.Unmarshal([]byte(`{"curve":"hilbert","n":16}`), &s); err != ErrInvalidConfig {
+		t.Errorf("json.Unmarshal(hilbert) into Morton = %v want %v", err, ErrInvalidConfig)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"math/rand"
+)
+
+// walk calls fn with the coordinates of each pair of consecutive values on the curve.
+func walk(s SpaceFilling, fn func(x0, y0, x1, y1 int)) {
+	width, height := s.GetDimensions()
+	x0, y0, _ := s.Map(0)
+	for t := 1; t < width*height; t++ {
+		x1, y1, _ := s.Map(t)
+		fn(x0, y0, x1, y1)
+		x0, y0 = x1, y1
+	}
+}
+
+// JumpCount returns the number of consecutive values on the curve, t and t+1, whose cells are not
+// adjacent horizontally or vertically. This is zero for continuous curves such as Hilbert and
+// Peano.
+func JumpCount(s SpaceFilling) int {
+	jumps := 0
+	walk(s, func(x0, y0, x1, y1 int) {
+		if headingBetween(x0, y0, x1, y1) == HeadingNone {
+			jumps++
+		}
+	})
+	return jumps
+}
+
+// MaxJump returns the largest Euclidean distance between the cells of consecutive values on the
+// curve. This is one for continuous curves such as Hilbert and Peano, and zero if the curve has a
+// single cell.
+func MaxJump(s SpaceFilling) float64 {
+	max := 0.0
+	walk(s, func(x0, y0, x1, y1 int) {
+		max = math.Max(max, math.Hypot(float64(x1-x0), float64(y1-y0)))
+	})
+	return max
+}
+
+// FractalDimension returns an estimate of the box-counting dimension of the curve, drawn as a line
+// through the centre of each cell. This should be close to two for curves which fill the space,
+// and one for curves which only fill a line.
+//
+// The boxes touched by the curve are counted at the given number of scales, starting with a single
+// box covering the whole space, and halving the box size each time. The estimate is the slope of
+// the least squares fit of log(count) against log(1/size). scales must be at least two, and the
+// smallest box must be no smaller than a cell, otherwise ErrOutOfRange is returned.
+func FractalDimension(s SpaceFilling, scales int) (float64, error) {
+	width, height := s.GetDimensions()
+	size := float64(width)
+	if height > width {
+		size = float64(height)
+	}
+	if scales < 2 || scales > 63 || size < float64(uint64(1)<<uint(scales-1)) {
+		return 0, ErrOutOfRange
+	}
+
+	// As consecutive cells are at most adjacent, the line between them only passes through the
+	// boxes containing the cells, as long as the boxes are no smaller than a cell. So it is enough
+	// to count the boxes containing each cell's centre.
+	var sumX, sumY, sumXX, sumXY float64
+	for k := 0; k < scales; k++ {
+		boxes := make(map[[2]int]bool)
+		for t := 0; t < width*height; t++ {
+			x, y, _ := s.Map(t)
+			boxes[[2]int{int((float64(x) + 0.5) / size), int((float64(y) + 0.5) / size)}] = true
+		}
+
+		lx, ly := math.Log(float64(uint64(1)<<uint(k))), math.Log(float64(len(boxes)))
+		sumX += lx
+		sumY += ly
+		sumXX += lx * lx
+		sumXY += lx * ly
+		size /= 2
+	}
+
+	n := float64(scales)
+	return (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX), nil
+}
+
+// LocalityRatio measures how close together the cells of values which are close on the curve are,
+// as the mean over pairs of distinct values, t0 and t1, of the Euclidean distance between their
+// cells divided by sqrt(|t1-t0|). Lower values are better, and as the ratio does not grow with
+// the size of the space for continuous curves such as Hilbert and Peano, curves of different
+// sizes can be compared. samples pairs are chosen at random using seed, with the distance
+// between them chosen log-uniformly, so near and far pairs are measured equally. ErrNotPositive
+// is returned if samples is not positive. The ratio is zero if the curve has a single cell.
+func LocalityRatio(s SpaceFilling, samples int, seed int64) (float64, error) {
+	if samples <= 0 {
+		return 0, ErrNotPositive
+	}
+	width, height := s.GetDimensions()
+	cells := width * height
+	if cells < 2 {
+		return 0, nil
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	sum := 0.0
+	for i := 0; i < samples; i++ {
+		// Choose the distance log-uniformly, so every scale is measured equally, rather than
+		// almost every pair being far apart.
+		d := int(math.Exp(r.Float64() * math.Log(float64(cells-1))))
+		t0 := r.Intn(cells - d)
+		t1 := t0 + d
+		x0, y0, _ := s.Map(t0)
+		x1, y1, _ := s.Map(t1)
+		sum += math.Hypot(float64(x1-x0), float64(y1-y0)) / math.Sqrt(math.Abs(float64(t1-t0)))
+	}
+	return sum / float64(samples), nil
+}
+
+// MaxStretch returns the largest ratio over all pairs of distinct values, t0 and t1, of the
+// squared Euclidean distance between their cells to |t1-t0|, the worst case of LocalityRatio
+// squared. For large Hilbert curves this approaches 6. Every pair is compared, which takes time
+// proportional to the square of the number of cells, so is only suitable for small curves, such
+// as up to 64x64. It is zero if the curve has a single cell.
+func MaxStretch(s SpaceFilling) float64 {
+	width, height := s.GetDimensions()
+	xs, ys := make([]int, width*height), make([]int, width*height)
+	for t := range xs {
+		xs[t], ys[t], _ = s.Map(t)
+	}
+
+	max := 0.0
+	for t0 := range xs {
+		for t1 := t0 + 1; t1 < len(xs); t1++ {
+			dx, dy := xs[t1]-xs[t0], ys[t1]-ys[t0]
+			max = math.Max(max, float64(dx*dx+dy*dy)/float64(t1-t0))
+		}
+	}
+	return max
+}
+
+// NeighborDistance measures the reverse of LocalityRatio, how close together on the curve the
+// values of adjacent cells are, as the mean of |t1-t0| over every pair of horizontally or
+// vertically adjacent cells. Lower values are better, as neighboring cells are then more likely
+// to be stored close together. It is zero if the space has no adjacent cells.
+func NeighborDistance(s SpaceFilling) float64 {
+	width, height := s.GetDimensions()
+	ts := make([]int, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ts[y*width+x], _ = s.MapInverse(x, y)
+		}
+	}
+
+	sum, pairs := 0, 0
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := ts[y*width+x]
+			if x+1 < width {
+				sum += absInt(ts[y*width+x+1] - t)
+				pairs++
+			}
+			if y+1 < height {
+				sum += absInt(ts[(y+1)*width+x] - t)
+				pairs++
+			}
+		}
+	}
+	if pairs == 0 {
+		return 0
+	}
+	return float64(sum) / float64(pairs)
+}
@@ -0,0 +1,178 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"testing"
+)
+
+// rowMajor is a simple, discontinuous, SpaceFilling used to test the metrics.
+type rowMajor struct {
+	w, h int
+}
+
+func (r rowMajor) Map(t int) (x, y int, err error) {
+	return t % r.w, t / r.w, nil
+}
+
+func (r rowMajor) MapInverse(x, y int) (t int, err error) {
+	return y*r.w + x, nil
+}
+
+func (r rowMajor) GetDimensions() (x, y int) {
+	return r.w, r.h
+}
+
+func TestJumps(t *testing.T) {
+	h1, _ := NewHilbert(1, false)
+	h16, _ := NewHilbert(16, false)
+	h16v, _ := NewHilbert(16, true)
+	p27, _ := NewPeano(27)
+
+	testCases := []struct {
+		name      string
+		s         SpaceFilling
+		wantCount int
+		wantMax   float64
+	}{
+		{"Hilbert(1)", h1, 0, 0},
+		{"Hilbert(16)", h16, 0, 1},
+		{"Hilbert(16, vertical)", h16v, 0, 1},
+		{"Peano(27)", p27, 0, 1},
+		{"rowMajor(4, 3)", rowMajor{4, 3}, 2, math.Hypot(3, 1)},
+	}
+
+	for _, tc := range testCases {
+		if got := JumpCount(tc.s); got != tc.wantCount {
+			t.Errorf("JumpCount(%s) = %d want %d", tc.name, got, tc.wantCount)
+		}
+		if got := MaxJump(tc.s); got != tc.wantMax {
+			t.Errorf("MaxJump(%s) = %f want %f", tc.name, got, tc.wantMax)
+		}
+	}
+}
+
+func TestFractalDimension(t *testing.T) {
+	h16, _ := NewHilbert(16, false)
+	p27, _ := NewPeano(27)
+
+	testCases := []struct {
+		name    string
+		s       SpaceFilling
+		scales  int
+		wantMin float64
+		wantMax float64
+	}{
+		{"Hilbert(16)", h16, 5, 2, 2},
+		{"Hilbert(16)", h16, 2, 2, 2},
+		{"Peano(27)", p27, 5, 1.9, 2.1},
+		{"rowMajor(8, 1)", rowMajor{8, 1}, 4, 1, 1},
+	}
+
+	for _, tc := range testCases {
+		got, err := FractalDimension(tc.s, tc.scales)
+		if err != nil {
+			t.Errorf("FractalDimension(%s, %d) failed: %s", tc.name, tc.scales, err)
+			continue
+		}
+		if got < tc.wantMin-1e-9 || got > tc.wantMax+1e-9 {
+			t.Errorf("FractalDimension(%s, %d) = %f want [%f, %f]", tc.name, tc.scales, got, tc.wantMin, tc.wantMax)
+		}
+	}
+}
+
+func TestFractalDimensionErrors(t *testing.T) {
+	h16, _ := NewHilbert(16, false)
+
+	for _, scales := range []int{-1, 0, 1, 6, 100} {
+		if _, err := FractalDimension(h16, scales); err != ErrOutOfRange {
+			t.Errorf("FractalDimension(Hilbert(16), %d) = %v want %v", scales, err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestMaxStretch(t *testing.T) {
+	h1, _ := NewHilbert(1, false)
+	h2, _ := NewHilbert(2, false)
+	h4, _ := NewHilbert(4, true)
+	m4, _ := NewMorton(4)
+
+	testCases := []struct {
+		name string
+		s    SpaceFilling
+		want float64
+	}{
+		{"Hilbert(1)", h1, 0},
+		{"Hilbert(2)", h2, 1},
+		{"Hilbert(4, vertical)", h4, 2.5},
+		{"Morton(4)", m4, 10},
+		{"rowMajor(4, 3)", rowMajor{4, 3}, 10},
+	}
+
+	for _, tc := range testCases {
+		if got := MaxStretch(tc.s); got != tc.want {
+			t.Errorf("MaxStretch(%s) = %f want %f", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestNeighborDistance(t *testing.T) {
+	h1, _ := NewHilbert(1, false)
+	h2, _ := NewHilbert(2, false)
+
+	testCases := []struct {
+		name string
+		s    SpaceFilling
+		want float64
+	}{
+		{"Hilbert(1)", h1, 0},
+		{"Hilbert(2)", h2, 1.5},
+		// 9 horizontal pairs 1 apart, and 8 vertical pairs 4 apart.
+		{"rowMajor(4, 3)", rowMajor{4, 3}, 41.0 / 17},
+	}
+
+	for _, tc := range testCases {
+		if got := NeighborDistance(tc.s); got != tc.want {
+			t.Errorf("NeighborDistance(%s) = %f want %f", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestLocalityRatio(t *testing.T) {
+	h1, _ := NewHilbert(1, false)
+	h32, _ := NewHilbert(32, false)
+	m32, _ := NewMorton(32)
+
+	if _, err := LocalityRatio(h32, 0, 1); err != ErrNotPositive {
+		t.Errorf("LocalityRatio(Hilbert(32), 0) = %v want %v", err, ErrNotPositive)
+	}
+	if got, err := LocalityRatio(h1, 10, 1); got != 0 || err != nil {
+		t.Errorf("LocalityRatio(Hilbert(1), 10) = (%f, %v) want (0, nil)", got, err)
+	}
+
+	hr, _ := LocalityRatio(h32, 10000, 1)
+	if again, _ := LocalityRatio(h32, 10000, 1); again != hr {
+		t.Errorf("LocalityRatio(Hilbert(32)) = %f then %f with the same seed", hr, again)
+	}
+
+	// The bound on the squared ratio is MaxStretch, and Morton is worse than Hilbert.
+	if bound := math.Sqrt(MaxStretch(h32)); hr <= 0 || hr > bound {
+		t.Errorf("LocalityRatio(Hilbert(32)) = %f want in (0, %f]", hr, bound)
+	}
+	if mr, _ := LocalityRatio(m32, 10000, 1); mr <= hr {
+		t.Errorf("LocalityRatio(Morton(32)) = %f want more than Hilbert(32) %f", mr, hr)
+	}
+}
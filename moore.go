@@ -0,0 +1,109 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Moore represents a 2D Moore curve of order N for mapping to and from. It is a variant of the
+// Hilbert curve whose first and last cells are horizontally adjacent, so the curve forms a closed
+// loop. It is made up of four vertical Hilbert curves of width N/2, one per quadrant, visited
+// anticlockwise starting and ending at the top middle of the space, at (N/2-1,0) and (N/2,0).
+// Implements SpaceFilling interface.
+type Moore struct {
+	N int // Always a power of two, and is the width/height of the space.
+
+	quadrant *Hilbert // The vertical curve of each quadrant
+}
+
+// NewMoore returns a new Moore space filling curve which maps integers to and from the curve.
+// n must be a power of two, and at least 2, otherwise ErrOrderTooSmall is returned. As for
+// NewHilbert, ErrTooLarge is returned if n is more than 2^MaxOrder.
+func NewMoore(n int) (*Moore, error) {
+	if n <= 0 {
+		return nil, ErrNotPositive
+	}
+	if (n & (n - 1)) != 0 {
+		return nil, ErrNotPowerOfTwo
+	}
+	if n < 2 {
+		return nil, ErrOrderTooSmall
+	}
+	if n > 1<<MaxOrder {
+		return nil, ErrTooLarge
+	}
+
+	quadrant, _ := NewHilbert(n/2, true)
+	return &Moore{
+		N:        n,
+		quadrant: quadrant,
+	}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *Moore) GetDimensions() (int, int) {
+	return s.N, s.N
+}
+
+// Map transforms a one dimension value, t, in the range [0, n^2-1] to coordinates on the Moore
+// curve in the two-dimension space, where x and y are within [0,n-1].
+func (s *Moore) Map(t int) (x, y int, err error) {
+	if t < 0 || t >= s.N*s.N {
+		return -1, -1, ErrOutOfRange
+	}
+
+	// The vertical curves run down the left side of their quadrant, from (0,0) to (0,h-1). The
+	// left quadrants are mirrored so they run down the middle of the space, and the right
+	// quadrants are reversed so they run up it.
+	h := s.N / 2
+	q, t := t/(h*h), t%(h*h)
+	if q >= 2 {
+		t = h*h - 1 - t
+	}
+	x, y, _ = s.quadrant.Map(t)
+
+	switch q {
+	case 0: // Top left
+		return h - 1 - x, y, nil
+	case 1: // Bottom left
+		return h - 1 - x, h + y, nil
+	case 2: // Bottom right
+		return h + x, h + y, nil
+	}
+	return h + x, y, nil // Top right
+}
+
+// MapInverse transform coordinates on the Moore curve from (x,y) to t.
+func (s *Moore) MapInverse(x, y int) (t int, err error) {
+	if x < 0 || x >= s.N || y < 0 || y >= s.N {
+		return -1, ErrOutOfRange
+	}
+
+	h := s.N / 2
+	var q int
+	switch {
+	case x < h && y < h:
+		q, x = 0, h-1-x
+	case x < h:
+		q, x, y = 1, h-1-x, y-h
+	case y >= h:
+		q, x, y = 2, x-h, y-h
+	default:
+		q, x = 3, x-h
+	}
+
+	t, _ = s.quadrant.MapInverse(x, y)
+	if q >= 2 {
+		t = h*h - 1 - t
+	}
+	return q*h*h + t, nil
+}
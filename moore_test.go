@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestMooreNewErrors(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want error
+	}{
+		{-1, ErrNotPositive},
+		{0, ErrNotPositive},
+		{1, ErrOrderTooSmall},
+		{3, ErrNotPowerOfTwo},
+		{1 << (MaxOrder + 1), ErrTooLarge},
+	} {
+		s, err := NewMoore(tc.n)
+		if s != nil || err != tc.want {
+			t.Errorf("NewMoore(%d) = (%+v, %q) did not fail want (?, %q)", tc.n, s, err, tc.want)
+		}
+	}
+}
+
+func TestMooreMap(t *testing.T) {
+	s, err := NewMoore(4)
+	if err != nil {
+		t.Fatalf("NewMoore(4) failed: %s", err)
+	}
+
+	want := [][2]int{
+		{1, 0}, {0, 0}, {0, 1}, {1, 1},
+		{1, 2}, {0, 2}, {0, 3}, {1, 3},
+		{2, 3}, {3, 3}, {3, 2}, {2, 2},
+		{2, 1}, {3, 1}, {3, 0}, {2, 0},
+	}
+	for d, w := range want {
+		x, y, err := s.Map(d)
+		if err != nil || x != w[0] || y != w[1] {
+			t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, w[0], w[1])
+		}
+	}
+}
+
+func TestMooreClosedLoop(t *testing.T) {
+	for n := 2; n <= 64; n *= 2 {
+		s, _ := NewMoore(n)
+		seen := make(map[[2]int]bool)
+		for d := 0; d < n*n; d++ {
+			x, y, err := s.Map(d)
+			if err != nil {
+				t.Fatalf("NewMoore(%d).Map(%d) returned error: %s", n, d, err)
+			}
+			if seen[[2]int{x, y}] {
+				t.Errorf("NewMoore(%d).Map(%d) = (%d, %d), which was already visited", n, d, x, y)
+			}
+			seen[[2]int{x, y}] = true
+
+			// Each cell, including the last, must be next to the one after it.
+			nx, ny, _ := s.Map((d + 1) % (n * n))
+			if headingBetween(x, y, nx, ny) == HeadingNone {
+				t.Errorf("NewMoore(%d): (%d, %d) at %d is not next to (%d, %d)", n, x, y, d, nx, ny)
+			}
+
+			if got, err := s.MapInverse(x, y); err != nil || got != d {
+				t.Errorf("NewMoore(%d).MapInverse(%d, %d) = (%d, %v) want (%d, nil)", n, x, y, got, err, d)
+			}
+		}
+	}
+}
+
+func TestMooreRangeErrors(t *testing.T) {
+	s, _ := NewMoore(4)
+	for _, d := range []int{-1, 16} {
+		if _, _, err := s.Map(d); err != ErrOutOfRange {
+			t.Errorf("Map(%d) = %v want %v", d, err, ErrOutOfRange)
+		}
+	}
+	for _, p := range [][2]int{{-1, 0}, {0, 4}} {
+		if _, err := s.MapInverse(p[0], p[1]); err != ErrOutOfRange {
+			t.Errorf("MapInverse(%d, %d) = %v want %v", p[0], p[1], err, ErrOutOfRange)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Morton represents a 2D Z-order (Morton) curve of order N for mapping to and from. Values are
+// formed by interleaving the bits of the coordinates, each bit of x followed by the bit of y at the
+// same level, which is cheaper than a Hilbert curve, but has worse locality as the curve jumps
+// between quadrants. Implements SpaceFilling interface.
+type Morton struct {
+	N int // Always a power of two, and is the width/height of the space.
+}
+
+// NewMorton returns a new Morton space filling curve which maps integers to and from the curve.
+// n must be a power of two. ErrTooLarge is returned if n*n would not fit in an int.
+func NewMorton(n int) (*Morton, error) {
+	if n <= 0 {
+		return nil, ErrNotPositive
+	}
+	if (n & (n - 1)) != 0 {
+		return nil, ErrNotPowerOfTwo
+	}
+	if _, ok := mulChecked(n, n); !ok {
+		return nil, ErrTooLarge
+	}
+	return &Morton{
+		N: n,
+	}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *Morton) GetDimensions() (int, int) {
+	return s.N, s.N
+}
+
+// Map transforms a one dimension value, t, in the range [0, n^2-1] to coordinates on the Morton
+// curve in the two-dimension space, where x and y are within [0,n-1].
+func (s *Morton) Map(t int) (x, y int, err error) {
+	if t < 0 || t >= s.N*s.N {
+		return -1, -1, ErrOutOfRange
+	}
+
+	for i := uint(0); t > 0; i++ {
+		y |= (t & 1) << i
+		x |= (t >> 1 & 1) << i
+		t >>= 2
+	}
+	return x, y, nil
+}
+
+// MapInverse transform coordinates on the Morton curve from (x,y) to t.
+func (s *Morton) MapInverse(x, y int) (t int, err error) {
+	if x < 0 || x >= s.N || y < 0 || y >= s.N {
+		return -1, ErrOutOfRange
+	}
+
+	for i := uint(0); x > 0 || y > 0; i++ {
+		t |= (x&1<<1 | y&1) << (2 * i)
+		x >>= 1
+		y >>= 1
+	}
+	return t, nil
+}
+
+// TranscodeMortonToHilbertKey rewrites a Morton (Z-order) key for a space of the given order as
+// the key for the same cell on the horizontal Hilbert curve, without decoding it to coordinates.
+// Both keys are stored big-endian in ceil(2*order/8) bytes, and the Morton key is the value of the
+// cell on a Morton curve of the same order. order must be in the range [0, 32].
+//
+// ErrInvalidLength is returned if the key is the wrong length, and ErrOutOfRange if it has bits
+// set above the lowest 2*order.
+func TranscodeMortonToHilbertKey(mortonKey []byte, order int) ([]byte, error) {
+	if order < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if order > 32 {
+		return nil, ErrTooLarge
+	}
+	n := levelBytes(order)
+	if len(mortonKey) != n {
+		return nil, ErrInvalidLength
+	}
+
+	var m uint64
+	for _, b := range mortonKey {
+		m = m<<8 | uint64(b)
+	}
+	if order < 32 && m>>uint(2*order) != 0 {
+		return nil, ErrOutOfRange
+	}
+
+	// Each pair of Morton bits is xbit<<1 | ybit, which is exactly how inverseStates is indexed.
+	var h uint64
+	state := uint8(0)
+	for shift := uint(2 * order); shift > 0; shift -= 2 {
+		e := inverseStates[state<<2|uint8(m>>(shift-2)&3)]
+		h = h<<2 | uint64(e>>2)
+		state = e & 3
+	}
+
+	key := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		key[i] = byte(h)
+		h >>= 8
+	}
+	return key, nil
+}
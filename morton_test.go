@@ -0,0 +1,140 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bytes"
+	"math/bits"
+	"math/rand"
+	"testing"
+)
+
+// uintKey returns v stored big-endian in n bytes.
+func uintKey(v uint64, n int) []byte {
+	key := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		key[i] = byte(v)
+		v >>= 8
+	}
+	return key
+}
+
+func TestMortonNewErrors(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		want error
+	}{
+		{-1, ErrNotPositive},
+		{0, ErrNotPositive},
+		{3, ErrNotPowerOfTwo},
+		{6, ErrNotPowerOfTwo},
+		{1 << uint(bits.UintSize/2), ErrTooLarge},
+	} {
+		s, err := NewMorton(tc.n)
+		if s != nil || err != tc.want {
+			t.Errorf("NewMorton(%d) = (%+v, %q) did not fail want (?, %q)", tc.n, s, err, tc.want)
+		}
+	}
+}
+
+func TestMortonMap(t *testing.T) {
+	s, err := NewMorton(4)
+	if err != nil {
+		t.Fatalf("NewMorton(4) failed: %s", err)
+	}
+
+	// The Z shape, repeated in each quadrant.
+	want := [][2]int{
+		{0, 0}, {0, 1}, {1, 0}, {1, 1},
+		{0, 2}, {0, 3}, {1, 2}, {1, 3},
+		{2, 0}, {2, 1}, {3, 0}, {3, 1},
+		{2, 2}, {2, 3}, {3, 2}, {3, 3},
+	}
+	for d, p := range want {
+		x, y, err := s.Map(d)
+		if err != nil || x != p[0] || y != p[1] {
+			t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, p[0], p[1])
+		}
+		if got, err := s.MapInverse(p[0], p[1]); err != nil || got != d {
+			t.Errorf("MapInverse(%d, %d) = (%d, %v) want (%d, nil)", p[0], p[1], got, err, d)
+		}
+	}
+
+	if _, _, err := s.Map(16); err != ErrOutOfRange {
+		t.Errorf("Map(16) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := s.MapInverse(4, 0); err != ErrOutOfRange {
+		t.Errorf("MapInverse(4, 0) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestMortonAllMapValues(t *testing.T) {
+	s, err := NewMorton(64)
+	if err != nil {
+		t.Fatalf("NewMorton(64) failed: %s", err)
+	}
+
+	for d := 0; d < s.N*s.N; d++ {
+		x, y, err := s.Map(d)
+		if err != nil {
+			t.Errorf("Map(%d) returned error: %s", d, err)
+		}
+		if want := interleave([]int{x, y}, 6); want != d {
+			t.Errorf("Map(%d) = (%d, %d) want the cell with interleaved bits %d", d, x, y, want)
+		}
+		if got, err := s.MapInverse(x, y); err != nil || got != d {
+			t.Errorf("Failed Map(%d) -> MapInverse(%d, %d) -> %d", d, x, y, got)
+		}
+	}
+}
+
+func TestTranscodeMortonToHilbertKey(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for _, order := range []int{0, 1, 3, 4, 16, 31, 32} {
+		n := levelBytes(order)
+		mask := uint32(1<<uint(order) - 1)
+		for i := 0; i < 100; i++ {
+			x, y := r.Uint32()&mask, r.Uint32()&mask
+			morton := uint64(interleave([]int{int(x), int(y)}, order))
+
+			got, err := TranscodeMortonToHilbertKey(uintKey(morton, n), order)
+			if want := uintKey(Encode2D(x, y, order), n); err != nil || !bytes.Equal(got, want) {
+				t.Errorf("TranscodeMortonToHilbertKey(%x, %d) = (%x, %v) want (%x, nil)", uintKey(morton, n), order, got, err, want)
+			}
+		}
+	}
+}
+
+func TestTranscodeMortonToHilbertKeyErrors(t *testing.T) {
+	testCases := []struct {
+		key   []byte
+		order int
+		want  error
+	}{
+		{[]byte{0}, -1, ErrNegativeOrder},
+		{make([]byte, 9), 33, ErrTooLarge},
+		{[]byte{0, 0}, 4, ErrInvalidLength},
+		{nil, 4, ErrInvalidLength},
+		{[]byte{0x40}, 3, ErrOutOfRange},
+		{[]byte{0x04, 0x00}, 5, ErrOutOfRange},
+	}
+
+	for _, tc := range testCases {
+		if _, err := TranscodeMortonToHilbertKey(tc.key, tc.order); err != tc.want {
+			t.Errorf("TranscodeMortonToHilbertKey(%x, %d) = %v want %v", tc.key, tc.order, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// IndexNeighborsToroidal returns the values on the curve of the four cells next to the cell at t,
+// treating the space as a torus, so the neighbors of cells on an edge wrap around to the opposite
+// edge. The neighbors are returned in the order right, down, left and up. For N=1 all four
+// neighbors are the cell itself.
+func (s *Hilbert) IndexNeighborsToroidal(t int) ([4]int, error) {
+	x, y, err := s.Map(t)
+	if err != nil {
+		return [4]int{-1, -1, -1, -1}, err
+	}
+
+	wrap := func(v int) int {
+		return (v + s.N) % s.N
+	}
+
+	var neighbors [4]int
+	for i, d := range [4][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}} {
+		neighbors[i], _ = s.MapInverse(wrap(x+d[0]), wrap(y+d[1]))
+	}
+	return neighbors, nil
+}
+
+// Next returns t+1, the next value along the curve. ok is false, and next is -1, if t is the last
+// value on the curve or is not on the curve.
+func (s *Hilbert) Next(t int) (next int, ok bool) {
+	if t < 0 || t >= s.N*s.N-1 {
+		return -1, false
+	}
+	return t + 1, true
+}
+
+// Prev returns t-1, the previous value along the curve. ok is false, and prev is -1, if t is the
+// first value on the curve or is not on the curve.
+func (s *Hilbert) Prev(t int) (prev int, ok bool) {
+	if t <= 0 || t >= s.N*s.N {
+		return -1, false
+	}
+	return t - 1, true
+}
+
+// NextWrap is like Next, but treats the curve as a cycle, so the value after the last is zero.
+// ok is false only if t is not on the curve.
+func (s *Hilbert) NextWrap(t int) (next int, ok bool) {
+	if t < 0 || t >= s.N*s.N {
+		return -1, false
+	}
+	return (t + 1) % (s.N * s.N), true
+}
+
+// PrevWrap is like Prev, but treats the curve as a cycle, so the value before zero is the last.
+// ok is false only if t is not on the curve.
+func (s *Hilbert) PrevWrap(t int) (prev int, ok bool) {
+	if t < 0 || t >= s.N*s.N {
+		return -1, false
+	}
+	return (t + s.N*s.N - 1) % (s.N * s.N), true
+}
+
+// NextCell returns the coordinates of the cell after (x,y) along the curve, the same as mapping
+// (x,y) to t and then t+1 back to coordinates, but with a single walk down the levels which only
+// recomputes the levels below the one that changes. ErrOutOfRange is returned if (x,y) is not
+// within the space, or is the last cell on the curve.
+func (s *Hilbert) NextCell(x, y int) (nx, ny int, err error) {
+	return s.stepCell(x, y, true)
+}
+
+// PrevCell is like NextCell, but returns the cell before (x,y), and ErrOutOfRange for the first
+// cell on the curve.
+func (s *Hilbert) PrevCell(x, y int) (px, py int, err error) {
+	return s.stepCell(x, y, false)
+}
+
+// stepCell moves one cell from (x,y) along the curve, to the next cell if next is true, or else
+// the previous one.
+func (s *Hilbert) stepCell(x, y int, next bool) (int, int, error) {
+	if x < 0 || x >= s.N || y < 0 || y >= s.N {
+		var okX, okY bool
+		x, okX = s.fitCoord(x)
+		y, okY = s.fitCoord(y)
+		if !okX || !okY {
+			return -1, -1, ErrOutOfRange
+		}
+	}
+
+	if s.inverse != nil {
+		t := int(s.inverse[y*s.N+x]) + 1
+		if !next {
+			t -= 2
+		}
+		if t < 0 || t >= s.N*s.N {
+			return -1, -1, ErrOutOfRange
+		}
+		p := int(s.forward[t])
+		return p % s.N, p / s.N, nil
+	}
+	if s.mirrored {
+		x = s.N - 1 - x
+	}
+
+	// The digits of t are of the unreversed curve, so step the other way if it is reversed.
+	// Adding one to t carries through the trailing 3 digits, and subtracting one borrows through
+	// the trailing 0 digits, which all then wrap around.
+	last, step := uint8(3), uint8(1)
+	if next == s.reversed {
+		last, step = 0, 3
+	}
+
+	// Walk down the levels as in MapInverse, remembering the deepest level whose digit of t does
+	// not carry, which is the only one that changes apart from those below it.
+	order := s.GetOrder()
+	level, levelState, levelDigit := -1, uint8(0), uint8(0)
+	state := s.startState
+	for i := 0; i < order; i++ {
+		bit := uint(order - 1 - i)
+		e := inverseStates[int(state)<<2|(x>>bit&1)<<1|y>>bit&1]
+		if e>>2 != last {
+			level, levelState, levelDigit = i, state, e>>2
+		}
+		state = e & 3
+	}
+	if level < 0 {
+		return -1, -1, ErrOutOfRange
+	}
+
+	// Map back from the changed level down, as in Generator.
+	state, digit := levelState, (levelDigit+step)&3
+	for i := level; i < order; i++ {
+		e := forwardStates[state<<2|digit]
+		bit := uint(order - 1 - i)
+		x = x&^(1<<bit) | int(e>>3)<<bit
+		y = y&^(1<<bit) | int(e>>2&1)<<bit
+		state, digit = e&3, (last+step)&3
+	}
+	if s.mirrored {
+		x = s.N - 1 - x
+	}
+	return x, y, nil
+}
+
+// Neighbors4Index returns the values on the curve of the cells horizontally and vertically
+// adjacent to the cell at t, in the order right, down, left and up, skipping any outside of the
+// space. nil is returned if t is not on the curve.
+func (s *Hilbert) Neighbors4Index(t int) []int {
+	neighbors, _ := s.Neighbors(t, false)
+	return neighbors
+}
+
+// neighborOffsets are the offsets to the adjacent cells, with the four horizontal and vertical
+// ones, right, down, left and up, first, followed by the diagonals.
+var neighborOffsets = [8][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}, {1, 1}, {-1, 1}, {-1, -1}, {1, -1}}
+
+// Neighbors returns the values on the curve of the cells adjacent to the cell at t, skipping any
+// outside of the space. The horizontal and vertical neighbors are returned first, in the order
+// right, down, left and up, followed by the diagonal neighbors, down-right, down-left, up-left and
+// up-right, if diagonals is true. ErrOutOfRange is returned if t is not on the curve.
+func (s *Hilbert) Neighbors(t int, diagonals bool) ([]int, error) {
+	x, y, err := s.Map(t)
+	if err != nil {
+		return nil, err
+	}
+
+	offsets := neighborOffsets[:4]
+	if diagonals {
+		offsets = neighborOffsets[:]
+	}
+	neighbors := make([]int, 0, len(offsets))
+	for _, d := range offsets {
+		nx, ny := x+d[0], y+d[1]
+		if nx < 0 || nx >= s.N || ny < 0 || ny >= s.N {
+			continue
+		}
+		n, _ := s.MapInverse(nx, ny)
+		neighbors = append(neighbors, n)
+	}
+	return neighbors, nil
+}
+
+// CurveNeighbors returns the cells before and after (x,y) along the curve, at t-1 and t+1, in that
+// order. The first and last cells on the curve only have one neighbor, and N=1 has none.
+// ErrOutOfRange is returned if (x,y) is not within the space.
+func (s *Hilbert) CurveNeighbors(x, y int) ([]Cell, error) {
+	t, err := s.MapInverse(x, y)
+	if err != nil {
+		return nil, err
+	}
+
+	neighbors := make([]Cell, 0, 2)
+	for _, n := range [2]int{t - 1, t + 1} {
+		if n >= 0 && n < s.N*s.N {
+			nx, ny, _ := s.Map(n)
+			neighbors = append(neighbors, Cell{n, nx, ny})
+		}
+	}
+	return neighbors, nil
+}
+
+// AdjacencyList returns the space as a graph, where element t holds the values of the cells
+// adjacent to the cell at t, as returned by Neighbors4Index. This uses memory proportional to
+// N*N, so for large spaces use Neighbors4Index as needed instead.
+func (s *Hilbert) AdjacencyList() [][]int {
+	adjacency := make([][]int, s.N*s.N)
+	for t := range adjacency {
+		adjacency[t] = s.Neighbors4Index(t)
+	}
+	return adjacency
+}
+
+// IsConnectedRange returns true if the cells with values in the range [lo, hi] form a single
+// 4-connected region. Consecutive cells on a Hilbert curve are always horizontally or vertically
+// adjacent, so this is true for every valid range, such as [1, 2] or [0, N*N-1]. It is provided
+// for validating range based partitions, and to match Table.IsConnectedRange, where it may be
+// false. A *RangeError is returned if the range is not within the curve.
+func (s *Hilbert) IsConnectedRange(lo, hi int) (bool, error) {
+	if err := s.validRange(lo, hi, "lo", "hi"); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// connectedRange returns true if the cells with values in the range [lo, hi] on c form a single
+// 4-connected region, by flood filling from the cell at lo. The range must be valid.
+func connectedRange(c SpaceFilling, lo, hi int) bool {
+	w, h := c.GetDimensions()
+	seen := make([]bool, hi-lo+1)
+	x, y, _ := c.Map(lo)
+	seen[0] = true
+	stack := [][2]int{{x, y}}
+	visited := 1
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, d := range [4][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}} {
+			nx, ny := p[0]+d[0], p[1]+d[1]
+			if nx < 0 || nx >= w || ny < 0 || ny >= h {
+				continue
+			}
+			t, err := c.MapInverse(nx, ny)
+			if err != nil || t < lo || t > hi || seen[t-lo] {
+				continue
+			}
+			seen[t-lo] = true
+			visited++
+			stack = append(stack, [2]int{nx, ny})
+		}
+	}
+	return visited == len(seen)
+}
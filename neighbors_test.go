@@ -0,0 +1,293 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIndexNeighborsToroidal(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	// The cells of the 4x4 curve, indexed by [y][x].
+	var grid [4][4]int
+	for d := 0; d < 16; d++ {
+		x, y, _ := s.Map(d)
+		grid[y][x] = d
+	}
+
+	testCases := []struct {
+		x, y int
+		want [4]int
+	}{
+		{1, 1, [4]int{grid[1][2], grid[2][1], grid[1][0], grid[0][1]}},
+		{0, 0, [4]int{grid[0][1], grid[1][0], grid[0][3], grid[3][0]}}, // Top left corner
+		{3, 0, [4]int{grid[0][0], grid[1][3], grid[0][2], grid[3][3]}}, // Top right corner
+		{0, 3, [4]int{grid[3][1], grid[0][0], grid[3][3], grid[2][0]}}, // Bottom left corner
+		{3, 3, [4]int{grid[3][0], grid[0][3], grid[3][2], grid[2][3]}}, // Bottom right corner
+		{2, 0, [4]int{grid[0][3], grid[1][2], grid[0][1], grid[3][2]}}, // Top edge
+		{3, 2, [4]int{grid[2][0], grid[3][3], grid[2][2], grid[1][3]}}, // Right edge
+	}
+
+	for _, tc := range testCases {
+		got, err := s.IndexNeighborsToroidal(grid[tc.y][tc.x])
+		if err != nil {
+			t.Errorf("IndexNeighborsToroidal(%d) returned error: %s", grid[tc.y][tc.x], err)
+		}
+		if got != tc.want {
+			t.Errorf("IndexNeighborsToroidal(%d) at (%d, %d) = %v want %v", grid[tc.y][tc.x], tc.x, tc.y, got, tc.want)
+		}
+	}
+
+	if _, err := s.IndexNeighborsToroidal(16); err != ErrOutOfRange {
+		t.Errorf("IndexNeighborsToroidal(16) = %q want %q", err, ErrOutOfRange)
+	}
+
+	one, _ := NewHilbert(1, false)
+	if got, _ := one.IndexNeighborsToroidal(0); got != [4]int{} {
+		t.Errorf("NewHilbert(1).IndexNeighborsToroidal(0) = %v want [0 0 0 0]", got)
+	}
+}
+
+func TestNextPrev(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		t                  int
+		next, prev         int
+		nextOK, prevOK     bool
+		nextWrap, prevWrap int
+		wrapOK             bool
+	}{
+		{0, 1, -1, true, false, 1, 15, true},
+		{7, 8, 6, true, true, 8, 6, true},
+		{15, -1, 14, false, true, 0, 14, true},
+		{-1, -1, -1, false, false, -1, -1, false},
+		{16, -1, -1, false, false, -1, -1, false},
+	}
+
+	for _, tc := range testCases {
+		if got, ok := s.Next(tc.t); got != tc.next || ok != tc.nextOK {
+			t.Errorf("Next(%d) = (%d, %t) want (%d, %t)", tc.t, got, ok, tc.next, tc.nextOK)
+		}
+		if got, ok := s.Prev(tc.t); got != tc.prev || ok != tc.prevOK {
+			t.Errorf("Prev(%d) = (%d, %t) want (%d, %t)", tc.t, got, ok, tc.prev, tc.prevOK)
+		}
+		if got, ok := s.NextWrap(tc.t); got != tc.nextWrap || ok != tc.wrapOK {
+			t.Errorf("NextWrap(%d) = (%d, %t) want (%d, %t)", tc.t, got, ok, tc.nextWrap, tc.wrapOK)
+		}
+		if got, ok := s.PrevWrap(tc.t); got != tc.prevWrap || ok != tc.wrapOK {
+			t.Errorf("PrevWrap(%d) = (%d, %t) want (%d, %t)", tc.t, got, ok, tc.prevWrap, tc.wrapOK)
+		}
+	}
+}
+
+func TestNextPrevCell(t *testing.T) {
+	for n := 1; n <= 32; n *= 2 {
+		h, _ := NewHilbert(n, false)
+		v, _ := NewHilbert(n, true)
+		p, _ := NewHilbertCached(n, true)
+		for _, s := range []*Hilbert{h, h.Reversed(), v, v.Reversed(), p} {
+			for d := 0; d < n*n; d++ {
+				x, y, _ := s.Map(d)
+
+				nx, ny, err := s.NextCell(x, y)
+				wantX, wantY, wantErr := s.Map(d + 1)
+				if nx != wantX || ny != wantY || err != wantErr {
+					t.Errorf("%s NextCell(%d, %d) = (%d, %d, %v) want (%d, %d, %v)", s.Fingerprint(), x, y, nx, ny, err, wantX, wantY, wantErr)
+				}
+
+				px, py, err := s.PrevCell(x, y)
+				wantX, wantY, wantErr = s.Map(d - 1)
+				if px != wantX || py != wantY || err != wantErr {
+					t.Errorf("%s PrevCell(%d, %d) = (%d, %d, %v) want (%d, %d, %v)", s.Fingerprint(), x, y, px, py, err, wantX, wantY, wantErr)
+				}
+			}
+		}
+	}
+
+	s, _ := NewHilbert(4, false)
+	if _, _, err := s.NextCell(4, 0); err != ErrOutOfRange {
+		t.Errorf("NextCell(4, 0) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, _, err := s.PrevCell(0, -1); err != ErrOutOfRange {
+		t.Errorf("PrevCell(0, -1) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func BenchmarkNextCell(b *testing.B) {
+	s, err := NewHilbert(benchmarkN, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x, y := 0, 0
+		for err == nil {
+			x, y, err = s.NextCell(x, y)
+		}
+		err = nil
+	}
+}
+
+func TestNeighbors4Index(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	// The cells of the 4x4 curve, indexed by [y][x].
+	var grid [4][4]int
+	for d := 0; d < 16; d++ {
+		x, y, _ := s.Map(d)
+		grid[y][x] = d
+	}
+
+	testCases := []struct {
+		x, y int
+		want []int
+	}{
+		{1, 1, []int{grid[1][2], grid[2][1], grid[1][0], grid[0][1]}},
+		{0, 0, []int{grid[0][1], grid[1][0]}},             // Top left corner
+		{3, 3, []int{grid[3][2], grid[2][3]}},             // Bottom right corner
+		{2, 0, []int{grid[0][3], grid[1][2], grid[0][1]}}, // Top edge
+		{0, 2, []int{grid[2][1], grid[3][0], grid[1][0]}}, // Left edge
+	}
+
+	adjacency := s.AdjacencyList()
+	if len(adjacency) != 16 {
+		t.Fatalf("len(AdjacencyList()) = %d want 16", len(adjacency))
+	}
+	for _, tc := range testCases {
+		d := grid[tc.y][tc.x]
+		if got := s.Neighbors4Index(d); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Neighbors4Index(%d) at (%d, %d) = %v want %v", d, tc.x, tc.y, got, tc.want)
+		}
+		if got := adjacency[d]; !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("AdjacencyList()[%d] at (%d, %d) = %v want %v", d, tc.x, tc.y, got, tc.want)
+		}
+	}
+
+	if got := s.Neighbors4Index(16); got != nil {
+		t.Errorf("Neighbors4Index(16) = %v want nil", got)
+	}
+
+	// Neighbors outside of the space are skipped whatever the bounds policy.
+	wrapped, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsWrap))
+	if got, want := wrapped.Neighbors4Index(0), s.Neighbors4Index(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("Neighbors4Index(0) with BoundsWrap = %v want %v", got, want)
+	}
+}
+
+func TestNeighbors(t *testing.T) {
+	s, err := NewHilbert(4, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	// The cells of the 4x4 curve, indexed by [y][x].
+	var grid [4][4]int
+	for d := 0; d < 16; d++ {
+		x, y, _ := s.Map(d)
+		grid[y][x] = d
+	}
+
+	testCases := []struct {
+		x, y int
+		want []int
+	}{
+		{1, 1, []int{grid[1][2], grid[2][1], grid[1][0], grid[0][1], grid[2][2], grid[2][0], grid[0][0], grid[0][2]}},
+		{0, 0, []int{grid[0][1], grid[1][0], grid[1][1]}},                         // Top left corner
+		{3, 3, []int{grid[3][2], grid[2][3], grid[2][2]}},                         // Bottom right corner
+		{2, 0, []int{grid[0][3], grid[1][2], grid[0][1], grid[1][3], grid[1][1]}}, // Top edge
+		{3, 1, []int{grid[2][3], grid[1][2], grid[0][3], grid[2][2], grid[0][2]}}, // Right edge
+	}
+	for _, tc := range testCases {
+		d := grid[tc.y][tc.x]
+		got, err := s.Neighbors(d, true)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Neighbors(%d, true) at (%d, %d) = (%v, %v) want (%v, nil)", d, tc.x, tc.y, got, err, tc.want)
+		}
+		got, err = s.Neighbors(d, false)
+		if want := s.Neighbors4Index(d); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("Neighbors(%d, false) at (%d, %d) = (%v, %v) want (%v, nil)", d, tc.x, tc.y, got, err, want)
+		}
+	}
+
+	for _, d := range []int{-1, 16} {
+		if _, err := s.Neighbors(d, true); err != ErrOutOfRange {
+			t.Errorf("Neighbors(%d, true) = %v want %v", d, err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestCurveNeighbors(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for d := 0; d < 16; d++ {
+		x, y, _ := s.Map(d)
+		var want []Cell
+		for _, n := range []int{d - 1, d + 1} {
+			if nx, ny, err := s.Map(n); err == nil {
+				want = append(want, Cell{n, nx, ny})
+			}
+		}
+		if got, err := s.CurveNeighbors(x, y); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("CurveNeighbors(%d, %d) = (%v, %v) want (%v, nil)", x, y, got, err, want)
+		}
+	}
+
+	if _, err := s.CurveNeighbors(4, 0); err != ErrOutOfRange {
+		t.Errorf("CurveNeighbors(4, 0) = %v want %v", err, ErrOutOfRange)
+	}
+	one, _ := NewHilbert(1, false)
+	if got, err := one.CurveNeighbors(0, 0); err != nil || len(got) != 0 {
+		t.Errorf("NewHilbert(1).CurveNeighbors(0, 0) = (%v, %v) want ([], nil)", got, err)
+	}
+}
+
+func TestIsConnectedRange(t *testing.T) {
+	s, err := NewHilbert(8, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for lo := 0; lo < s.N*s.N; lo++ {
+		for hi := lo; hi < s.N*s.N; hi++ {
+			got, err := s.IsConnectedRange(lo, hi)
+			if want := connectedRange(s, lo, hi); err != nil || got != want {
+				t.Errorf("IsConnectedRange(%d, %d) = (%t, %v) want (%t, nil)", lo, hi, got, err, want)
+			}
+		}
+	}
+
+	for _, r := range []Range{{-1, 0}, {0, 64}, {5, 4}} {
+		if _, err := s.IsConnectedRange(r.Lo, r.Hi); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("IsConnectedRange(%d, %d) = %v want %v", r.Lo, r.Hi, err, ErrOutOfRange)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"math/bits"
+)
+
+// BitsFor returns the smallest order of Hilbert curve which contains the coordinate maxCoord,
+// that is the number of bits needed to hold it. The curve for the order can then be created with
+// NewHilbert(1<<order, ...). -1 is returned if maxCoord is negative.
+func BitsFor(maxCoord int) int {
+	if maxCoord < 0 {
+		return -1
+	}
+	return bits.Len(uint(maxCoord))
+}
+
+// FitsInOrder returns true if the cell (x,y) is within a Hilbert curve of the given order, which
+// is 2^order wide.
+func FitsInOrder(x, y, order int) bool {
+	if x < 0 || y < 0 || order < 0 {
+		return false
+	}
+	return BitsFor(x) <= order && BitsFor(y) <= order
+}
+
+// OrderForResolution returns the order of Hilbert curve whose width, 2^order, is closest to pixels,
+// so when the curve is drawn as a square image pixels wide, each cell covers about one pixel.
+// Widths are compared by their ratio, so 181 pixels gives order 7 (128) and 182 gives order 8
+// (256). The order is capped so N*N fits in an int, and -1 is returned if pixels is not positive.
+func OrderForResolution(pixels int) int {
+	if pixels <= 0 {
+		return -1
+	}
+
+	order := bits.Len(uint(pixels)) - 1
+	if float64(pixels) >= float64(uint(1)<<uint(order))*math.Sqrt2 {
+		order++
+	}
+	return min(order, MaxOrder)
+}
+
+// CurveType identifies one of the curves implemented by this package.
+type CurveType int
+
+// Supported curve types.
+const (
+	CurveHilbert CurveType = iota // Hilbert curve, created with NewHilbert
+	CurvePeano                    // Peano curve, created with NewPeano
+)
+
+var curveTypeNames = [...]string{"hilbert", "peano"}
+
+// String returns the lower case name of the curve type, e.g. "hilbert".
+func (c CurveType) String() string {
+	if c < 0 || int(c) >= len(curveTypeNames) {
+		return "unknown"
+	}
+	return curveTypeNames[c]
+}
+
+// BestCurveFor returns the curve type and order which covers a width by height space with the
+// fewest unused cells. A Hilbert curve is 2^order wide and a Peano curve is 3^order wide, so
+// NewHilbert(1<<order, ...) or NewPeano(3^order) creates the curve. If both waste the same number
+// of cells, the Hilbert curve is returned. ErrTooLarge is returned if no curve fits in an int.
+func BestCurveFor(width, height int) (CurveType, int, error) {
+	if width <= 0 || height <= 0 {
+		return CurveHilbert, -1, ErrNotPositive
+	}
+
+	best, bestOrder, bestSide := CurveHilbert, -1, 0
+	for _, c := range []CurveType{CurveHilbert, CurvePeano} {
+		base := 2
+		if c == CurvePeano {
+			base = 3
+		}
+		order, side := coveringPower(base, max(width, height))
+		if order < 0 {
+			continue
+		}
+		if bestOrder < 0 || side < bestSide {
+			best, bestOrder, bestSide = c, order, side
+		}
+	}
+	if bestOrder < 0 {
+		return CurveHilbert, -1, ErrTooLarge
+	}
+	return best, bestOrder, nil
+}
+
+// coveringPower returns the smallest order, and base^order, which is at least n, or -1 if the
+// square of that side would not fit in an int.
+func coveringPower(base, n int) (order, side int) {
+	side = 1
+	for side < n {
+		if side > math.MaxInt/base {
+			return -1, 0
+		}
+		side *= base
+		order++
+	}
+	if side > math.MaxInt/side {
+		return -1, 0
+	}
+	return order, side
+}
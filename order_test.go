@@ -0,0 +1,138 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"math/bits"
+	"testing"
+)
+
+func TestBitsFor(t *testing.T) {
+	testCases := []struct {
+		maxCoord int
+		want     int
+	}{
+		{-1, -1},
+		{0, 0},
+		{1, 1},
+		{2, 2},
+		{3, 2},
+		{4, 3},
+		{999, 10},
+		{1023, 10},
+		{1024, 11},
+		{math.MaxInt32, 31},
+	}
+
+	for _, tc := range testCases {
+		if got := BitsFor(tc.maxCoord); got != tc.want {
+			t.Errorf("BitsFor(%d) = %d want %d", tc.maxCoord, got, tc.want)
+		}
+	}
+
+	// The curve for the order must contain the coordinate.
+	for c := 0; c < 100; c++ {
+		s, _ := NewHilbert(1<<uint(BitsFor(c)), false)
+		if _, err := s.MapInverse(c, c); err != nil {
+			t.Errorf("NewHilbert(1<<BitsFor(%d)).MapInverse(%d, %d) failed: %s", c, c, c, err)
+		}
+	}
+}
+
+func TestFitsInOrder(t *testing.T) {
+	testCases := []struct {
+		x, y, order int
+		want        bool
+	}{
+		{0, 0, 0, true},
+		{1, 0, 0, false},
+		{1, 1, 1, true},
+		{2, 1, 1, false},
+		{1, 2, 1, false},
+		{15, 15, 4, true},
+		{16, 0, 4, false},
+		{-1, 0, 4, false},
+		{0, -1, 4, false},
+		{0, 0, -1, false},
+		{math.MaxInt32, 0, 100, true},
+	}
+
+	for _, tc := range testCases {
+		if got := FitsInOrder(tc.x, tc.y, tc.order); got != tc.want {
+			t.Errorf("FitsInOrder(%d, %d, %d) = %t want %t", tc.x, tc.y, tc.order, got, tc.want)
+		}
+	}
+}
+
+func TestOrderForResolution(t *testing.T) {
+	testCases := []struct {
+		pixels int
+		want   int
+	}{
+		{-1, -1},
+		{0, -1},
+		{1, 0},
+		{2, 1},
+		{3, 2},
+		{5, 2},
+		{6, 3},
+		{181, 7},
+		{182, 8},
+		{256, 8},
+		{1000, 10},
+		{math.MaxInt, bits.UintSize/2 - 1},
+	}
+
+	for _, tc := range testCases {
+		if got := OrderForResolution(tc.pixels); got != tc.want {
+			t.Errorf("OrderForResolution(%d) = %d want %d", tc.pixels, got, tc.want)
+		}
+	}
+}
+
+func TestBestCurveFor(t *testing.T) {
+	testCases := []struct {
+		width, height int
+		want          CurveType
+		wantOrder     int
+	}{
+		{1, 1, CurveHilbert, 0},
+		{2, 1, CurveHilbert, 1},
+		{3, 3, CurvePeano, 1},
+		{4, 4, CurveHilbert, 2},
+		{5, 2, CurveHilbert, 3},
+		{1, 9, CurvePeano, 2},
+		{17, 17, CurvePeano, 3},
+		{28, 20, CurveHilbert, 5},
+		{1000, 1000, CurveHilbert, 10},
+	}
+
+	for _, tc := range testCases {
+		got, order, err := BestCurveFor(tc.width, tc.height)
+		if err != nil || got != tc.want || order != tc.wantOrder {
+			t.Errorf("BestCurveFor(%d, %d) = (%s, %d, %v) want (%s, %d, nil)", tc.width, tc.height, got, order, err, tc.want, tc.wantOrder)
+		}
+	}
+
+	for _, size := range [][2]int{{0, 1}, {1, 0}, {-1, -1}} {
+		if _, _, err := BestCurveFor(size[0], size[1]); err != ErrNotPositive {
+			t.Errorf("BestCurveFor(%d, %d) = %v want %v", size[0], size[1], err, ErrNotPositive)
+		}
+	}
+	if _, _, err := BestCurveFor(math.MaxInt, 1); err != ErrTooLarge {
+		t.Errorf("BestCurveFor(MaxInt, 1) = %v want %v", err, ErrTooLarge)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"sort"
+	"strconv"
+)
+
+// IndexError records which element of a slice failed to convert, and why.
+type IndexError struct {
+	Index int
+	Err   error
+}
+
+func (e *IndexError) Error() string {
+	return "index " + strconv.Itoa(e.Index) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error, so errors.Is(err, ErrOutOfRange) works.
+func (e *IndexError) Unwrap() error {
+	return e.Err
+}
+
+// IndexPacked returns the value of t for each of the row-major positions in packed, where each
+// position is y*N+x. The lookup tables built by Prewarm are used if present. If a position is
+// outside of the space, an *IndexError for the first one is returned, wrapping a *RangeError.
+func (s *Hilbert) IndexPacked(packed []int) ([]int, error) {
+	out := make([]int, len(packed))
+	for i, p := range packed {
+		if p < 0 || p >= s.N*s.N {
+			return nil, &IndexError{Index: i, Err: &RangeError{Name: "packed", Value: p, Min: 0, Max: s.N*s.N - 1}}
+		}
+		out[i], _ = s.MapInverse(p%s.N, p/s.N)
+	}
+	return out, nil
+}
+
+// SortedIndexPacked is like IndexPacked, but returns the values of t in ascending order, along
+// with the permutation that sorts them, so ts[i] is the value for packed[perm[i]]. This is useful
+// for reordering a row-major grid into curve order.
+func (s *Hilbert) SortedIndexPacked(packed []int) (ts, perm []int, err error) {
+	ts, err = s.IndexPacked(packed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	perm = make([]int, len(ts))
+	for i := range perm {
+		perm[i] = i
+	}
+	sort.Sort(byIndex{ts, perm})
+	return ts, perm, nil
+}
+
+// byIndex sorts ts and perm together by ts.
+type byIndex struct {
+	ts, perm []int
+}
+
+func (b byIndex) Len() int           { return len(b.ts) }
+func (b byIndex) Less(i, j int) bool { return b.ts[i] < b.ts[j] }
+func (b byIndex) Swap(i, j int) {
+	b.ts[i], b.ts[j] = b.ts[j], b.ts[i]
+	b.perm[i], b.perm[j] = b.perm[j], b.perm[i]
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIndexPacked(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	packed := []int{0, 1, 5, 15, 12}
+	want := []int{0, 1, 2, 10, 5}
+	got, err := s.IndexPacked(packed)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("IndexPacked(%v) = (%v, %v) want (%v, nil)", packed, got, err, want)
+	}
+
+	if err := s.Prewarm(); err != nil {
+		t.Fatalf("Prewarm() returned error: %s", err)
+	}
+	got, err = s.IndexPacked(packed)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("prewarmed IndexPacked(%v) = (%v, %v) want (%v, nil)", packed, got, err, want)
+	}
+
+	_, err = s.IndexPacked([]int{3, 16, -1})
+	var ie *IndexError
+	if !errors.As(err, &ie) || ie.Index != 1 || !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("IndexPacked([3 16 -1]) = %v want index 1 %v", err, ErrOutOfRange)
+	}
+	var re *RangeError
+	if !errors.As(err, &re) || *re != (RangeError{Name: "packed", Value: 16, Min: 0, Max: 15}) {
+		t.Errorf("IndexPacked([3 16 -1]) = %v want packed = 16 is not within [0, 15]", err)
+	}
+}
+
+func TestSortedIndexPacked(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	packed := []int{15, 12, 0, 5, 1}
+	ts, perm, err := s.SortedIndexPacked(packed)
+	if err != nil {
+		t.Fatalf("SortedIndexPacked(%v) returned error: %s", packed, err)
+	}
+	if want := []int{0, 1, 2, 5, 10}; !reflect.DeepEqual(ts, want) {
+		t.Errorf("SortedIndexPacked(%v) ts = %v want %v", packed, ts, want)
+	}
+	if want := []int{2, 4, 3, 1, 0}; !reflect.DeepEqual(perm, want) {
+		t.Errorf("SortedIndexPacked(%v) perm = %v want %v", packed, perm, want)
+	}
+
+	if _, _, err := s.SortedIndexPacked([]int{16}); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("SortedIndexPacked([16]) = %v want %v", err, ErrOutOfRange)
+	}
+}
@@ -35,7 +35,7 @@ func isPow3(n float64) bool {
 }
 
 // NewPeano returns a new Peano space filling curve which maps integers to and from the curve.
-// n must be a power of three.
+// n must be a power of three. ErrTooLarge is returned if n*n would not fit in an int.
 func NewPeano(n int) (*Peano, error) {
 	if n <= 0 {
 		return nil, ErrNotPositive
@@ -44,6 +44,9 @@ func NewPeano(n int) (*Peano, error) {
 	if !isPow3(float64(n)) {
 		return nil, ErrNotPowerOfThree
 	}
+	if _, ok := mulChecked(n, n); !ok {
+		return nil, ErrTooLarge
+	}
 
 	return &Peano{
 		N: n,
@@ -120,12 +123,27 @@ func (p *Peano) rotate(n, x, y, s int) (int, int) {
 }
 
 // MapInverse transform coordinates on the Peano curve from (x,y) to t.
-// NOT IMPLEMENTED YET
 func (p *Peano) MapInverse(x, y int) (t int, err error) {
 	if x < 0 || x >= p.N || y < 0 || y >= p.N {
 		return -1, ErrOutOfRange
 	}
 
-	panic("Not finished")
-	return -1, nil
+	// Undo Map, starting from the largest 3x3 grid. The flips done by rotate are their own
+	// inverse.
+	for i := p.N / 3; i >= 1; i = i / 3 {
+		rx := x / i
+		ry := y / i
+		if rx == 1 {
+			ry = 2 - ry
+		}
+		s := rx*3 + ry
+		t = t*9 + s
+
+		x, y = x%i, y%i
+		if i > 1 {
+			x, y = p.rotate(i, x, y, s)
+		}
+	}
+
+	return t, nil
 }
@@ -0,0 +1,205 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"os"
+)
+
+// permMagic identifies the format written by WritePermFile.
+var permMagic = [4]byte{'H', 'L', 'B', 'P'}
+
+// permLayoutMagic identifies the format written by WritePermFile for curves created with
+// WithTransform, where the Layout field of the header records the layout.
+var permLayoutMagic = [4]byte{'H', 'L', 'B', 'Q'}
+
+// permHeaderBytes is the size of the header written by WritePermFile, which keeps the tables
+// following it aligned for direct 4 byte reads.
+const permHeaderBytes = 16
+
+// permHeader is the header written by WritePermFile, padded to permHeaderBytes. Layout is only
+// set if the magic is permLayoutMagic, and is otherwise implied by VerticalCompatible. It is
+// followed by the forward table, N*N little-endian uint32 values of y*N+x for each t, and then the
+// inverse table, N*N little-endian uint32 values of t for each y*N+x.
+type permHeader struct {
+	Magic              [4]byte
+	Order              uint8
+	VerticalCompatible bool
+	Reversed           bool
+	Layout             uint8
+	_                  [8]byte
+}
+
+// WritePermFile writes the whole curve to the file at path, in a fixed-width format which
+// OpenPermFile maps directly into memory. The file holds both lookup tables, 8*N*N bytes, after a
+// header recording the order, layout and direction. The tables are generated as they are
+// written, so are never held in memory. N must be at most 65536.
+func (s *Hilbert) WritePermFile(path string) (err error) {
+	if s.N > maxTableN {
+		return ErrTooLarge
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	bw := bufio.NewWriter(f)
+	header := permHeader{
+		Magic:              permMagic,
+		Order:              uint8(s.GetOrder()),
+		VerticalCompatible: s.verticalCompatible,
+		Reversed:           s.reversed,
+	}
+	if !s.hasDefaultLayout() {
+		header.Magic = permLayoutMagic
+		header.Layout = s.layout()
+	}
+	if err := binary.Write(bw, binary.LittleEndian, header); err != nil {
+		return err
+	}
+
+	var buf [tableEntryBytes]byte
+	for t := 0; t < s.N*s.N; t++ {
+		x, y, _ := s.Map(t)
+		binary.LittleEndian.PutUint32(buf[:], uint32(y*s.N+x))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	for p := 0; p < s.N*s.N; p++ {
+		t, _ := s.MapInverse(p%s.N, p/s.N)
+		binary.LittleEndian.PutUint32(buf[:], uint32(t))
+		if _, err := bw.Write(buf[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// PermFile is a Hilbert curve backed by a file written by WritePermFile, so Map and MapInverse
+// are reads from the mapped file. Implements SpaceFilling interface.
+type PermFile struct {
+	N                  int
+	verticalCompatible bool
+	reversed           bool
+	layout             uint8 // As returned by Hilbert.layout
+
+	data    []byte // The whole file
+	release func() error
+}
+
+// OpenPermFile opens a file written by WritePermFile, mapping it read-only into memory where the
+// platform supports it, and reading it otherwise. The returned curve is a *PermFile, whose Close
+// method releases the file. Only the header and size of the file are checked, so opening takes
+// constant time; ErrBadTables is returned if they are invalid, but the tables are trusted.
+func OpenPermFile(path string) (SpaceFilling, error) {
+	data, release, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var header permHeader
+	if len(data) >= permHeaderBytes {
+		err = binary.Read(bytes.NewReader(data[:permHeaderBytes]), binary.LittleEndian, &header)
+	}
+	layout := uint8(b2i(header.VerticalCompatible))
+	if header.Magic == permLayoutMagic {
+		layout = header.Layout
+	}
+	n := 1 << uint(header.Order)
+	if err != nil || (header.Magic != permMagic && header.Magic != permLayoutMagic) ||
+		header.Order > 16 || layout > 7 ||
+		len(data) != permHeaderBytes+2*tableEntryBytes*n*n {
+		release()
+		return nil, ErrBadTables
+	}
+
+	return &PermFile{
+		N:                  n,
+		verticalCompatible: header.VerticalCompatible,
+		reversed:           header.Reversed,
+		layout:             layout,
+		data:               data,
+		release:            release,
+	}, nil
+}
+
+// Close releases the file. The curve must not be used afterwards. Closing an already closed
+// curve does nothing and returns nil.
+func (s *PermFile) Close() error {
+	if s.data == nil {
+		return nil
+	}
+	s.data = nil
+	return s.release()
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *PermFile) GetDimensions() (int, int) {
+	return s.N, s.N
+}
+
+// Orientation returns the orientation of the curve the file was written for.
+func (s *PermFile) Orientation() Orientation {
+	if s.layout&1 == 1 {
+		return Vertical
+	}
+	return Horizontal
+}
+
+// IsReversed returns true if the file was written for a reversed curve.
+func (s *PermFile) IsReversed() bool {
+	return s.reversed
+}
+
+// Transform returns the transform which moves the horizontal curve, as created by
+// NewHilbert(N, false), to the curve the file was written for, so NewHilbert(N, false,
+// WithTransform(Transform())) creates a curve which maps the same as the file.
+func (s *PermFile) Transform() Transform {
+	c := Hilbert{N: s.N, reversed: s.reversed, startState: s.layout & 3, mirrored: s.layout&4 != 0}
+	return c.Transform()
+}
+
+// entry returns the i'th entry of the tables, the forward table followed by the inverse.
+func (s *PermFile) entry(i int) int {
+	return int(binary.LittleEndian.Uint32(s.data[permHeaderBytes+tableEntryBytes*i:]))
+}
+
+// Map transforms a one dimension value, t, in the range [0, n^2-1] to coordinates on the curve
+// in the two-dimension space, where x and y are within [0,n-1].
+func (s *PermFile) Map(t int) (x, y int, err error) {
+	if t < 0 || t >= s.N*s.N {
+		return -1, -1, ErrOutOfRange
+	}
+	p := s.entry(t)
+	return p % s.N, p / s.N, nil
+}
+
+// MapInverse transform coordinates on the curve from (x,y) to t.
+func (s *PermFile) MapInverse(x, y int) (t int, err error) {
+	if x < 0 || x >= s.N || y < 0 || y >= s.N {
+		return -1, ErrOutOfRange
+	}
+	return s.entry(s.N*s.N + y*s.N + x), nil
+}
@@ -0,0 +1,128 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestPermFile(t *testing.T) {
+	dir := t.TempDir()
+
+	h, _ := NewHilbert(32, false)
+	v, _ := NewHilbert(32, true)
+	m, _ := NewHilbert(32, false, WithTransform(Transform{Mirror: true}))
+	rot, _ := NewHilbert(32, true, WithTransform(Transform{Rotation: 1, Reversed: true}))
+	for i, s := range []*Hilbert{h, v, h.Reversed(), m, rot} {
+		path := filepath.Join(dir, strconv.Itoa(i))
+		if err := s.WritePermFile(path); err != nil {
+			t.Fatalf("WritePermFile() returned error: %s", err)
+		}
+		if fi, err := os.Stat(path); err != nil || fi.Size() != 16+8*32*32 {
+			t.Errorf("WritePermFile() wrote %v bytes want %d", fi.Size(), 16+8*32*32)
+		}
+
+		c, err := OpenPermFile(path)
+		if err != nil {
+			t.Fatalf("OpenPermFile() returned error: %s", err)
+		}
+		p := c.(*PermFile)
+		if p.N != s.N || p.Orientation() != s.Orientation() || p.IsReversed() != s.IsReversed() {
+			t.Errorf("OpenPermFile() = {N:%d %s reversed:%t} want {N:%d %s reversed:%t}",
+				p.N, p.Orientation(), p.IsReversed(), s.N, s.Orientation(), s.IsReversed())
+		}
+		if p.Transform() != s.Transform() {
+			t.Errorf("Transform() = %+v want %+v", p.Transform(), s.Transform())
+		}
+		for d := 0; d < s.N*s.N; d++ {
+			wantX, wantY, _ := s.Map(d)
+			if x, y, err := p.Map(d); err != nil || x != wantX || y != wantY {
+				t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, wantX, wantY)
+			}
+		}
+		for x := 0; x < s.N; x++ {
+			for y := 0; y < s.N; y++ {
+				want, _ := s.MapInverse(x, y)
+				if got, err := p.MapInverse(x, y); err != nil || got != want {
+					t.Errorf("MapInverse(%d, %d) = (%d, %v) want (%d, nil)", x, y, got, err, want)
+				}
+			}
+		}
+		if _, _, err := p.Map(32 * 32); err != ErrOutOfRange {
+			t.Errorf("Map(1024) = %v want %v", err, ErrOutOfRange)
+		}
+		if _, err := p.MapInverse(0, 32); err != ErrOutOfRange {
+			t.Errorf("MapInverse(0, 32) = %v want %v", err, ErrOutOfRange)
+		}
+		if err := p.Close(); err != nil {
+			t.Errorf("Close() returned error: %s", err)
+		}
+		if err := p.Close(); err != nil {
+			t.Errorf("second Close() returned error: %s", err)
+		}
+	}
+}
+
+func TestOpenPermFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	s, _ := NewHilbert(4, false)
+	path := filepath.Join(dir, "perm")
+	if err := s.WritePermFile(path); err != nil {
+		t.Fatalf("WritePermFile() returned error: %s", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() returned error: %s", err)
+	}
+
+	badMagic := append([]byte(nil), data...)
+	badMagic[0] = 'X'
+	badOrder := append([]byte(nil), data...)
+	badOrder[4] = 3
+	badLayout := append([]byte(nil), data...)
+	badLayout[3], badLayout[7] = 'Q', 8
+	for name, contents := range map[string][]byte{
+		"empty":     nil,
+		"header":    data[:16],
+		"truncated": data[:len(data)-1],
+		"long":      append(append([]byte(nil), data...), 0),
+		"magic":     badMagic,
+		"order":     badOrder,
+		"layout":    badLayout,
+	} {
+		bad := filepath.Join(dir, name)
+		if err := os.WriteFile(bad, contents, 0o644); err != nil {
+			t.Fatalf("WriteFile() returned error: %s", err)
+		}
+		if _, err := OpenPermFile(bad); err != ErrBadTables {
+			t.Errorf("OpenPermFile(%s) = %v want %v", name, err, ErrBadTables)
+		}
+	}
+
+	if _, err := OpenPermFile(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("OpenPermFile(missing) returned no error")
+	}
+
+	// N=1<<17 is more than MaxOrder allows when int is 32 bits.
+	if big, err := NewHilbert(1<<17, false); err == nil {
+		if err := big.WritePermFile(filepath.Join(dir, "big")); err != ErrTooLarge {
+			t.Errorf("WritePermFile() for N=1<<17 = %v want %v", err, ErrTooLarge)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "sort"
+
+// span is the inclusive range of x coordinates, [lo, hi], of cells in one row.
+type span struct {
+	lo, hi int
+}
+
+// CoverPolygon returns the sorted list of ranges on the curve that exactly cover the cells inside
+// or on the edge of the polygon, as in RangeQuery. The polygon is given by its vertices in order,
+// with each vertex being the coordinates of a cell, and an edge joining the last vertex back to
+// the first. The polygon must be simple, but need not be convex, otherwise ErrNotSimple is
+// returned. ErrOutOfRange is returned if a vertex is not within the space, and ErrInvalidLength
+// if there are fewer than three vertices.
+func (s *Hilbert) CoverPolygon(vertices [][2]int) ([]Range, error) {
+	if len(vertices) < 3 {
+		return nil, ErrInvalidLength
+	}
+	for _, v := range vertices {
+		if v[0] < 0 || v[0] >= s.N || v[1] < 0 || v[1] >= s.N {
+			return nil, ErrOutOfRange
+		}
+	}
+	if selfIntersecting(vertices) {
+		return nil, ErrNotSimple
+	}
+
+	rows := make(map[int][]span)
+	edge := func(i int) ([2]int, [2]int) {
+		return vertices[i], vertices[(i+1)%len(vertices)]
+	}
+
+	// Fill the interior a row at a time, using the even-odd rule. Each edge crosses the row half
+	// open, so a vertex is only counted once by the two edges meeting at it.
+	minY, maxY := vertices[0][1], vertices[0][1]
+	for _, v := range vertices {
+		minY, maxY = min(minY, v[1]), max(maxY, v[1])
+	}
+	for y := minY; y <= maxY; y++ {
+		var crossings []fraction
+		for i := range vertices {
+			a, b := edge(i)
+			if (a[1] <= y && y < b[1]) || (b[1] <= y && y < a[1]) {
+				crossings = append(crossings, crossingX(a, b, y))
+			}
+		}
+		sort.Slice(crossings, func(i, j int) bool {
+			return crossings[i].less(crossings[j])
+		})
+		for i := 0; i+1 < len(crossings); i += 2 {
+			if lo, hi := crossings[i].ceil(), crossings[i+1].floor(); lo <= hi {
+				rows[y] = append(rows[y], span{lo, hi})
+			}
+		}
+	}
+
+	// The even-odd rule misses some cells exactly on the edges, such as the top and bottom
+	// vertices and horizontal edges, so add every cell the edges pass through.
+	for i := range vertices {
+		a, b := edge(i)
+		if a[1] == b[1] {
+			rows[a[1]] = append(rows[a[1]], span{min(a[0], b[0]), max(a[0], b[0])})
+			continue
+		}
+		for y := min(a[1], b[1]); y <= max(a[1], b[1]); y++ {
+			if x := crossingX(a, b, y); x.num%x.den == 0 {
+				rows[y] = append(rows[y], span{x.floor(), x.floor()})
+			}
+		}
+	}
+
+	var ranges []Range
+	for y, spans := range rows {
+		for _, sp := range spans {
+			r, _ := s.RangeQuery(sp.lo, y, sp.hi, y)
+			ranges = append(ranges, r...)
+		}
+	}
+	return MergeRanges(ranges), nil
+}
+
+// fraction is the rational number num/den, where den is positive.
+type fraction struct {
+	num, den int
+}
+
+func (f fraction) less(o fraction) bool {
+	return f.num*o.den < o.num*f.den
+}
+
+func (f fraction) floor() int {
+	q := f.num / f.den
+	if f.num%f.den != 0 && f.num < 0 {
+		q--
+	}
+	return q
+}
+
+func (f fraction) ceil() int {
+	return -fraction{-f.num, f.den}.floor()
+}
+
+// crossingX returns the x coordinate where the line through a and b crosses the row y. a and b must
+// be in different rows.
+func crossingX(a, b [2]int, y int) fraction {
+	f := fraction{a[0]*(b[1]-a[1]) + (y-a[1])*(b[0]-a[0]), b[1] - a[1]}
+	if f.den < 0 {
+		f.num, f.den = -f.num, -f.den
+	}
+	return f
+}
+
+// selfIntersecting returns true if any two edges of the polygon, which are not next to each
+// other, touch.
+func selfIntersecting(vertices [][2]int) bool {
+	n := len(vertices)
+	for i := 0; i < n; i++ {
+		for j := i + 2; j < n; j++ {
+			if i == 0 && j == n-1 {
+				continue // The last edge is next to the first.
+			}
+			if segmentsTouch(vertices[i], vertices[(i+1)%n], vertices[j], vertices[(j+1)%n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// segmentsTouch returns true if the line segments ab and cd have at least one point in common.
+func segmentsTouch(a, b, c, d [2]int) bool {
+	cross := func(o, p, q [2]int) int {
+		v := (p[0]-o[0])*(q[1]-o[1]) - (p[1]-o[1])*(q[0]-o[0])
+		switch {
+		case v > 0:
+			return 1
+		case v < 0:
+			return -1
+		}
+		return 0
+	}
+	within := func(o, p, q [2]int) bool { // q is on the line op, so check it is between them
+		return min(o[0], p[0]) <= q[0] && q[0] <= max(o[0], p[0]) &&
+			min(o[1], p[1]) <= q[1] && q[1] <= max(o[1], p[1])
+	}
+
+	d1, d2 := cross(c, d, a), cross(c, d, b)
+	d3, d4 := cross(a, b, c), cross(a, b, d)
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+	return (d1 == 0 && within(c, d, a)) || (d2 == 0 && within(c, d, b)) ||
+		(d3 == 0 && within(a, b, c)) || (d4 == 0 && within(a, b, d))
+}
@@ -0,0 +1,135 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// bruteForcePolygon returns the ranges covering the cells inside or on the edge of the polygon,
+// by testing every cell in the space.
+func bruteForcePolygon(s *Hilbert, vertices [][2]int) []Range {
+	inside := func(x, y int) bool {
+		in := false
+		for i := range vertices {
+			a, b := vertices[i], vertices[(i+1)%len(vertices)]
+			if segmentsTouch(a, b, [2]int{x, y}, [2]int{x, y}) {
+				return true
+			}
+			if (a[1] <= y && y < b[1]) || (b[1] <= y && y < a[1]) {
+				// x coordinate of the crossing is to the right of the cell.
+				if float64(a[0])+float64(y-a[1])*float64(b[0]-a[0])/float64(b[1]-a[1]) > float64(x) {
+					in = !in
+				}
+			}
+		}
+		return in
+	}
+
+	var ranges []Range
+	for t := 0; t < s.N*s.N; t++ {
+		x, y, _ := s.Map(t)
+		if inside(x, y) {
+			ranges = append(ranges, Range{t, t})
+		}
+	}
+	return MergeRanges(ranges)
+}
+
+func TestCoverPolygon(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := [][][2]int{
+		{{2, 3}, {9, 3}, {9, 12}, {2, 12}},                   // Rectangle
+		{{0, 0}, {15, 0}, {0, 15}},                           // Triangle
+		{{7, 1}, {14, 8}, {7, 15}, {0, 8}},                   // Diamond
+		{{1, 1}, {6, 1}, {6, 9}, {13, 9}, {13, 14}, {1, 14}}, // Concave L shape
+		{{3, 3}, {12, 5}, {5, 7}, {11, 13}, {2, 11}},         // Concave, with a notch
+		{{4, 4}, {10, 10}, {4, 10}},                          // Diagonal edge through cells
+		{{0, 5}, {15, 5}, {7, 5}},                            // Degenerate, all on one row
+	}
+
+	for _, vertices := range testCases {
+		got, err := s.CoverPolygon(vertices)
+		if err != nil {
+			t.Errorf("CoverPolygon(%v) failed: %s", vertices, err)
+			continue
+		}
+		if want := bruteForcePolygon(s, vertices); !reflect.DeepEqual(got, want) {
+			t.Errorf("CoverPolygon(%v) = %v want %v", vertices, got, want)
+		}
+	}
+
+	// A rectangle is the same as a RangeQuery.
+	got, _ := s.CoverPolygon([][2]int{{2, 3}, {9, 3}, {9, 12}, {2, 12}})
+	if want, _ := s.RangeQuery(2, 3, 9, 12); !reflect.DeepEqual(got, want) {
+		t.Errorf("CoverPolygon(rectangle) = %v want %v", got, want)
+	}
+}
+
+func TestCoverPolygonRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	s, err := NewHilbert(32, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		var vertices [][2]int
+		for j := 0; j < 3; j++ {
+			vertices = append(vertices, [2]int{r.Intn(s.N), r.Intn(s.N)})
+		}
+
+		got, err := s.CoverPolygon(vertices)
+		if err != nil {
+			t.Errorf("CoverPolygon(%v) failed: %s", vertices, err)
+			continue
+		}
+		if want := bruteForcePolygon(s, vertices); !reflect.DeepEqual(got, want) {
+			t.Errorf("CoverPolygon(%v) = %v want %v", vertices, got, want)
+		}
+	}
+}
+
+func TestCoverPolygonErrors(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		vertices [][2]int
+		want     error
+	}{
+		{nil, ErrInvalidLength},
+		{[][2]int{{0, 0}, {5, 5}}, ErrInvalidLength},
+		{[][2]int{{0, 0}, {16, 0}, {0, 5}}, ErrOutOfRange},
+		{[][2]int{{0, 0}, {5, 0}, {0, -1}}, ErrOutOfRange},
+		{[][2]int{{0, 0}, {10, 10}, {10, 0}, {0, 10}}, ErrNotSimple}, // Bow tie
+		{[][2]int{{0, 0}, {8, 0}, {4, 0}, {4, 8}}, ErrNotSimple},     // Edge doubles back
+	}
+
+	for _, tc := range testCases {
+		if _, err := s.CoverPolygon(tc.vertices); err != tc.want {
+			t.Errorf("CoverPolygon(%v) = %v want %v", tc.vertices, err, tc.want)
+		}
+	}
+}
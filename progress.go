@@ -0,0 +1,60 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// ProgressFunc is called during long running operations, such as exporting the whole curve, with
+// the number of cells done so far out of the total.
+type ProgressFunc func(done, total int)
+
+// WithProgress sets a function to be called periodically by the export methods, WriteJSONL,
+// WriteJSONLRange, WriteCSV and ToGeoJSONPoints, about every 1% of the cells, and once more when
+// they finish. fn is called from the same goroutine as the export.
+func WithProgress(fn ProgressFunc) Option {
+	return func(s *Hilbert) {
+		s.progress = fn
+	}
+}
+
+// progress reports progress to a ProgressFunc, limiting how often it is called.
+type progress struct {
+	fn          ProgressFunc
+	total, step int
+	next        int // The number of cells done when fn is next called
+}
+
+// newProgress returns a progress for an operation on total cells. When the curve has no
+// ProgressFunc, next is never reached, so report costs a single comparison.
+func (s *Hilbert) newProgress(total int) progress {
+	p := progress{fn: s.progress, total: total, next: math.MaxInt}
+	if p.fn != nil {
+		p.step = max(1, total/100)
+		p.next = p.step
+	}
+	return p
+}
+
+// report records that done cells have been completed.
+func (p *progress) report(done int) {
+	if done < p.next {
+		return
+	}
+	p.fn(done, p.total)
+	p.next = done + p.step
+	if p.next > p.total && done < p.total {
+		p.next = p.total
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"io"
+	"testing"
+)
+
+func TestWithProgress(t *testing.T) {
+	var calls [][2]int
+	s, err := NewHilbert(64, false, WithProgress(func(done, total int) {
+		calls = append(calls, [2]int{done, total})
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	exports := []struct {
+		name  string
+		total int
+		fn    func() error
+	}{
+		{"WriteJSONL", 4096, func() error { return s.WriteJSONL(io.Discard) }},
+		{"WriteJSONLRange", 1000, func() error { return s.WriteJSONLRange(io.Discard, 10, 1009) }},
+		{"WriteCSV", 4096, func() error { return s.WriteCSV(io.Discard, []Column{ColumnT}) }},
+		{"ToGeoJSONPoints", 4096, func() error { return s.ToGeoJSONPoints(io.Discard, nil) }},
+		{"Reversed().WriteJSONL", 4096, func() error { return s.Reversed().WriteJSONL(io.Discard) }},
+	}
+
+	for _, e := range exports {
+		calls = nil
+		if err := e.fn(); err != nil {
+			t.Errorf("%s() returned error: %s", e.name, err)
+			continue
+		}
+
+		if len(calls) < 100 || len(calls) > 110 {
+			t.Errorf("%s() called progress %d times want about 100", e.name, len(calls))
+		}
+		last := 0
+		for _, c := range calls {
+			if c[0] <= last || c[1] != e.total {
+				t.Errorf("%s() called progress(%d, %d) after %d", e.name, c[0], c[1], last)
+			}
+			last = c[0]
+		}
+		if last != e.total {
+			t.Errorf("%s() last called progress with %d want %d", e.name, last, e.total)
+		}
+	}
+}
+
+func TestWithProgressSmall(t *testing.T) {
+	var calls []int
+	s, err := NewHilbert(2, false, WithProgress(func(done, total int) {
+		calls = append(calls, done)
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	if err := s.WriteJSONL(io.Discard); err != nil {
+		t.Fatalf("WriteJSONL() returned error: %s", err)
+	}
+	if len(calls) != 4 || calls[3] != 4 {
+		t.Errorf("WriteJSONL() called progress with %v want [1 2 3 4]", calls)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// AtLevel returns the curve of order level, which is 2^level wide, with the same orientation,
+// direction, transform and options as s, such as for the zoom levels of a tile pyramid. Each cell
+// at one level covers the four cells at the next level with the values 4*t to 4*t+3, as for
+// ChildCells, so the curves of every level are consistent, and values can be converted between
+// them with PromoteIndex. Lookup tables built by Prewarm are not copied. ErrOutOfRange is
+// returned if level is not within [0, MaxOrder].
+func (s *Hilbert) AtLevel(level int) (*Hilbert, error) {
+	if level < 0 || level > MaxOrder {
+		return nil, ErrOutOfRange
+	}
+	return &Hilbert{
+		N:                  1 << uint(level),
+		verticalCompatible: s.verticalCompatible,
+		reversed:           s.reversed,
+		bounds:             s.bounds,
+		progress:           s.progress,
+		startState:         s.startState,
+		mirrored:           s.mirrored,
+	}, nil
+}
+
+// PromoteIndex converts the value t on the curve of order fromLevel to the values on the curve of
+// order toLevel of the same configuration, as returned by AtLevel. If toLevel is coarser the
+// result is the single value of the cell covering t, and if it is finer it is the range of values
+// of the cells within t, which are consecutive. ErrOutOfRange is returned if either level is not
+// within [0, MaxOrder], or t is not on the curve of order fromLevel.
+func PromoteIndex(t, fromLevel, toLevel int) (Range, error) {
+	if fromLevel < 0 || fromLevel > MaxOrder || toLevel < 0 || toLevel > MaxOrder {
+		return Range{}, ErrOutOfRange
+	}
+	if t < 0 || t >= 1<<uint(2*fromLevel) {
+		return Range{}, ErrOutOfRange
+	}
+
+	if toLevel <= fromLevel {
+		t >>= uint(2 * (fromLevel - toLevel))
+		return Range{t, t}, nil
+	}
+	shift := uint(2 * (toLevel - fromLevel))
+	return Range{t << shift, (t+1)<<shift - 1}, nil
+}
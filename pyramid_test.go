@@ -0,0 +1,116 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestAtLevel(t *testing.T) {
+	h, _ := NewHilbert(4, false)
+	v, _ := NewHilbert(4, true, WithTransform(Transform{Mirror: true, Rotation: 1}))
+	for _, s := range []*Hilbert{h, h.Reversed(), v, v.Reversed()} {
+		for level := 0; level <= 5; level++ {
+			a, err := s.AtLevel(level)
+			if err != nil {
+				t.Fatalf("AtLevel(%d) returned error: %s", level, err)
+			}
+			if a.N != 1<<uint(level) || a.GetOrder() != level {
+				t.Errorf("AtLevel(%d).N = %d want %d", level, a.N, 1<<uint(level))
+			}
+			want := s.Config()
+			want.N = a.N
+			if got := a.Config(); got != want {
+				t.Errorf("AtLevel(%d).Config() = %+v want %+v", level, got, want)
+			}
+		}
+
+		// Every cell at a level must lie within the cell covering it at each coarser level.
+		fine, _ := s.AtLevel(5)
+		for d := 0; d < fine.N*fine.N; d++ {
+			x, y, _ := fine.Map(d)
+			for level := 0; level < 5; level++ {
+				coarse, _ := s.AtLevel(level)
+				r, err := PromoteIndex(d, 5, level)
+				if err != nil {
+					t.Fatalf("PromoteIndex(%d, 5, %d) returned error: %s", d, level, err)
+				}
+				cx, cy, _ := coarse.Map(r.Lo)
+				shift := uint(5 - level)
+				if cx != x>>shift || cy != y>>shift {
+					t.Errorf("%v: cell %d at level %d = (%d, %d) does not cover (%d, %d)", s.Config(), r.Lo, level, cx, cy, x, y)
+				}
+			}
+		}
+	}
+
+	for _, level := range []int{-1, MaxOrder + 1} {
+		if _, err := h.AtLevel(level); err != ErrOutOfRange {
+			t.Errorf("AtLevel(%d) = %v want %v", level, err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestPromoteIndex(t *testing.T) {
+	tests := []struct {
+		t, from, to int
+		want        Range
+	}{
+		{0, 0, 0, Range{0, 0}},
+		{0, 0, 2, Range{0, 15}},
+		{5, 2, 2, Range{5, 5}},
+		{5, 2, 1, Range{1, 1}},
+		{5, 2, 0, Range{0, 0}},
+		{15, 2, 1, Range{3, 3}},
+		{5, 2, 3, Range{20, 23}},
+		{5, 2, 4, Range{80, 95}},
+		{255, 4, 2, Range{15, 15}},
+		{1, 1, MaxOrder, Range{1 << uint(2*MaxOrder-2), 2<<uint(2*MaxOrder-2) - 1}},
+	}
+	for _, tt := range tests {
+		got, err := PromoteIndex(tt.t, tt.from, tt.to)
+		if err != nil {
+			t.Errorf("PromoteIndex(%d, %d, %d) returned error: %s", tt.t, tt.from, tt.to, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("PromoteIndex(%d, %d, %d) = %v want %v", tt.t, tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	// Promoting to a finer level and back must give the original value.
+	for d := 0; d < 64; d++ {
+		r, _ := PromoteIndex(d, 3, 6)
+		for _, f := range []int{r.Lo, r.Hi} {
+			if got, _ := PromoteIndex(f, 6, 3); got != (Range{d, d}) {
+				t.Errorf("PromoteIndex(%d, 6, 3) = %v want %v", f, got, Range{d, d})
+			}
+		}
+	}
+
+	errTests := []struct {
+		t, from, to int
+	}{
+		{-1, 2, 1},
+		{16, 2, 1},
+		{0, -1, 1},
+		{0, 1, -1},
+		{0, MaxOrder + 1, 1},
+		{0, 1, MaxOrder + 1},
+	}
+	for _, tt := range errTests {
+		if _, err := PromoteIndex(tt.t, tt.from, tt.to); err != ErrOutOfRange {
+			t.Errorf("PromoteIndex(%d, %d, %d) = %v want %v", tt.t, tt.from, tt.to, err, ErrOutOfRange)
+		}
+	}
+}
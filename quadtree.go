@@ -0,0 +1,295 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/bits"
+
+// QuadPath returns the quadtree path from the root of the space down to the cell at t on the
+// Hilbert curve. The path has GetOrder() entries, one per level, each being the child index
+// (0-3) chosen at that level. Child indices are spatial, with the low bit set for the right
+// half (greater x) and the high bit set for the bottom half (greater y):
+//
+//	0 | 1
+//	--+--
+//	2 | 3
+//
+// ErrOrderTooSmall is returned for N=1, which has no levels.
+func (s *Hilbert) QuadPath(t int) ([]int, error) {
+	if s.GetOrder() < 1 {
+		return nil, ErrOrderTooSmall
+	}
+	x, y, err := s.Map(t)
+	if err != nil {
+		return nil, err
+	}
+
+	order := s.GetOrder()
+	path := make([]int, order)
+	for level := 0; level < order; level++ {
+		shift := uint(order - 1 - level)
+		path[level] = int((x>>shift)&1) | int((y>>shift)&1)<<1
+	}
+	return path, nil
+}
+
+// FromQuadPath is the inverse of QuadPath, it transforms a quadtree path into t. The path must
+// have exactly GetOrder() entries, each in the range [0, 3]. ErrOrderTooSmall is returned for
+// N=1.
+func (s *Hilbert) FromQuadPath(path []int) (t int, err error) {
+	if s.GetOrder() < 1 {
+		return -1, ErrOrderTooSmall
+	}
+	if len(path) != s.GetOrder() {
+		return -1, ErrInvalidLength
+	}
+
+	x, y := 0, 0
+	for _, q := range path {
+		if q < 0 || q > 3 {
+			return -1, ErrOutOfRange
+		}
+		x = x<<1 | q&1
+		y = y<<1 | q>>1
+	}
+	return s.MapInverse(x, y)
+}
+
+// Cell is a cell in the space along with its value on the curve.
+type Cell struct {
+	T, X, Y int
+}
+
+// ChildCells returns the four cells which the cell at t divides into on the curve of the next
+// order, which is twice as wide and has the same orientation and direction. The children are
+// returned in curve order, so their values are 4*t to 4*t+3, with their coordinates on the finer
+// curve. ErrTooLarge is returned if the finer curve would not fit in an int.
+func (s *Hilbert) ChildCells(t int) ([]Cell, error) {
+	if t < 0 || t >= s.N*s.N {
+		return nil, ErrOutOfRange
+	}
+	if s.GetOrder()+1 > MaxOrder {
+		return nil, ErrTooLarge
+	}
+
+	fine := &Hilbert{
+		N:                  2 * s.N,
+		verticalCompatible: s.verticalCompatible,
+		reversed:           s.reversed,
+		startState:         s.startState,
+		mirrored:           s.mirrored,
+	}
+	cells := make([]Cell, 4)
+	for i := range cells {
+		c := &cells[i]
+		c.T = 4*t + i
+		c.X, c.Y, _ = fine.Map(c.T)
+	}
+	return cells, nil
+}
+
+// SameCell returns true if (x0,y0) and (x1,y1) fall within the same cell at the given level of
+// the quadtree. Level 0 is the whole space, and level GetOrder() is individual cells, so each
+// level has 2^level by 2^level cells.
+func (s *Hilbert) SameCell(x0, y0, x1, y1, level int) (bool, error) {
+	if level < 0 || level > s.GetOrder() {
+		return false, ErrOutOfRange
+	}
+
+	t0, err := s.MapInverse(x0, y0)
+	if err != nil {
+		return false, err
+	}
+	t1, err := s.MapInverse(x1, y1)
+	if err != nil {
+		return false, err
+	}
+
+	return s.coarseIndex(t0, level) == s.coarseIndex(t1, level), nil
+}
+
+// coarseIndex returns the index on the curve of the cell at the given level containing t.
+func (s *Hilbert) coarseIndex(t, level int) int {
+	return t >> uint(2*(s.GetOrder()-level))
+}
+
+// CellID identifies a cell in the quadtree of a curve, at any level, as in S2 or geohash. Level 0
+// is the whole space, and level GetOrder() is individual cells. The ID is the index on the curve
+// of the cell at its level, with a one bit above it marking the level, so the IDs of every cell
+// within a parent start with the bits of the parent's ID. Zero is not a valid ID.
+type CellID uint64
+
+// CellID returns the ID of the cell at the given level containing the cell at t.
+// ErrOutOfRange is returned if t is not on the curve, or the level is not within
+// [0, GetOrder()].
+func (s *Hilbert) CellID(t, level int) (CellID, error) {
+	if t < 0 || t >= s.N*s.N || level < 0 || level > s.GetOrder() {
+		return 0, ErrOutOfRange
+	}
+	return CellID(1)<<uint(2*level) | CellID(s.coarseIndex(t, level)), nil
+}
+
+// CellRange returns the range of values on the curve within the cell c. ErrOutOfRange is returned
+// if c is not a valid ID, or its level is more than GetOrder().
+func (s *Hilbert) CellRange(c CellID) (Range, error) {
+	level := c.Level()
+	if level < 0 || level > s.GetOrder() {
+		return Range{}, ErrOutOfRange
+	}
+	shift := uint(2 * (s.GetOrder() - level))
+	lo := c.Index() << shift
+	return Range{lo, lo + 1<<shift - 1}, nil
+}
+
+// Level returns the level of the cell in the quadtree, or -1 if c is not a valid ID, that is if
+// it is zero or its marker bit, the highest set bit, is not at an even position.
+func (c CellID) Level() int {
+	n := bits.Len64(uint64(c))
+	if n%2 == 0 {
+		return -1
+	}
+	return (n - 1) / 2
+}
+
+// Index returns the index on the curve of the cell at its level, that is the value of t for the
+// cell on the curve of order Level(), or -1 if c is not a valid ID.
+func (c CellID) Index() int {
+	level := c.Level()
+	if level < 0 {
+		return -1
+	}
+	return int(c &^ (1 << uint(2*level)))
+}
+
+// Parent returns the cell containing c at the level above. false is returned for the whole space,
+// at level 0, which has no parent, and if c is not a valid ID.
+func (c CellID) Parent() (CellID, bool) {
+	if c.Level() < 1 {
+		return 0, false
+	}
+	return c >> 2, true
+}
+
+// Children returns the four cells which c divides into at the level below, in curve order. Levels
+// up to 31 fit in a CellID, so c must be at level 30 or less.
+func (c CellID) Children() [4]CellID {
+	return [4]CellID{c << 2, c<<2 | 1, c<<2 | 2, c<<2 | 3}
+}
+
+// Contains returns true if o is c, or is within c at a lower level. It is false if either is not
+// a valid ID.
+func (c CellID) Contains(o CellID) bool {
+	if c.Level() < 0 || o.Level() < 0 {
+		return false
+	}
+	diff := o.Level() - c.Level()
+	return diff >= 0 && o>>uint(2*diff) == c
+}
+
+// Digits returns the GetOrder() base-4 digits of t, most significant first. Each digit is the
+// position, in curve order, of the quadrant chosen at that level of the recursion, so cells
+// sharing a prefix of digits are within the same sub-square. ErrOrderTooSmall is returned for
+// N=1, which has no digits.
+func (s *Hilbert) Digits(t int) ([]int, error) {
+	if s.GetOrder() < 1 {
+		return nil, ErrOrderTooSmall
+	}
+	if t < 0 || t >= s.N*s.N {
+		return nil, ErrOutOfRange
+	}
+
+	digits := make([]int, s.GetOrder())
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i] = t & 3
+		t >>= 2
+	}
+	return digits, nil
+}
+
+// MapWithDigits returns both the cell of t, as Map does, and its digits, as Digits does, but does
+// the work once. The digits are written to digitsBuf, which is returned resliced to GetOrder()
+// digits, and only a new slice is allocated if digitsBuf has less capacity than that. Passing the
+// returned digits back in on the next call means no allocations are made, but the previous call's
+// digits are then overwritten. As for Digits, the bounds policy is not applied: ErrOutOfRange is
+// returned if t is not within the space, even where Map would accept it, and ErrOrderTooSmall is
+// returned for N=1.
+func (s *Hilbert) MapWithDigits(t int, digitsBuf []int) (x, y int, digits []int, err error) {
+	order := s.GetOrder()
+	if order < 1 {
+		return -1, -1, digitsBuf, ErrOrderTooSmall
+	}
+	if t < 0 || t >= s.N*s.N {
+		return -1, -1, digitsBuf, ErrOutOfRange
+	}
+
+	if cap(digitsBuf) >= order {
+		digits = digitsBuf[:order]
+	} else {
+		digits = make([]int, order)
+	}
+
+	walk := t
+	if s.reversed {
+		walk = s.N*s.N - 1 - t
+	}
+	state := s.startState
+	for i := range digits {
+		shift := uint(2 * (order - 1 - i))
+		digits[i] = t >> shift & 3
+		e := forwardStates[state<<2|uint8(walk>>shift&3)]
+		x = x<<1 | int(e>>3)
+		y = y<<1 | int(e>>2&1)
+		state = e & 3
+	}
+	if s.mirrored {
+		x = s.N - 1 - x
+	}
+	return x, y, digits, nil
+}
+
+// FromDigits is the inverse of Digits. There must be exactly GetOrder() digits, each in the
+// range [0, 3]. ErrOrderTooSmall is returned for N=1.
+func (s *Hilbert) FromDigits(digits []int) (int, error) {
+	if s.GetOrder() < 1 {
+		return -1, ErrOrderTooSmall
+	}
+	if len(digits) != s.GetOrder() {
+		return -1, ErrInvalidLength
+	}
+
+	t := 0
+	for _, d := range digits {
+		if d < 0 || d > 3 {
+			return -1, ErrOutOfRange
+		}
+		t = t<<2 | d
+	}
+	return t, nil
+}
+
+// IsAlignedQuadrant returns true if the rectangle with corners (x0,y0) and (x1,y1) inclusive is
+// exactly one of the recursive sub-squares of the space, along with the level of that sub-square,
+// as used by SameCell. Such a rectangle is always covered by a single range on the curve. If the
+// rectangle is not an aligned sub-square, false and -1 are returned.
+func (s *Hilbert) IsAlignedQuadrant(x0, y0, x1, y1 int) (bool, int) {
+	if s.validRect(x0, y0, x1, y1) != nil {
+		return false, -1
+	}
+
+	size := x1 - x0 + 1
+	if size != y1-y0+1 || size&(size-1) != 0 || x0%size != 0 || y0%size != 0 {
+		return false, -1
+	}
+	return true, s.GetOrder() - bits.TrailingZeros(uint(size))
+}
@@ -0,0 +1,529 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetOrder(t *testing.T) {
+	testCases := []struct {
+		n, want int
+	}{
+		{1, 0},
+		{2, 1},
+		{16, 4},
+		{1024, 10},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(tc.n, false)
+		if err != nil {
+			t.Fatalf("NewHilbert(%d) failed: %s", tc.n, err)
+		}
+		if got := s.GetOrder(); got != tc.want {
+			t.Errorf("NewHilbert(%d).GetOrder() = %d want %d", tc.n, got, tc.want)
+		}
+	}
+}
+
+func TestQuadPath(t *testing.T) {
+	testCases := []struct {
+		d    int
+		want []int
+	}{
+		{0, []int{0, 0, 0, 0}},   // (0, 0)
+		{96, []int{2, 3, 0, 0}},  // (4, 12)
+		{170, []int{3, 3, 3, 3}}, // (15, 15)
+		{255, []int{1, 1, 1, 1}}, // (15, 0)
+		{112, []int{2, 1, 3, 3}}, // (7, 11)
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		got, err := s.QuadPath(tc.d)
+		if err != nil {
+			t.Errorf("QuadPath(%d) returned error: %s", tc.d, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("QuadPath(%d) = %v want %v", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestQuadPathErrors(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	if _, err := s.QuadPath(256); err != ErrOutOfRange {
+		t.Errorf("QuadPath(256) = %q want %q", err, ErrOutOfRange)
+	}
+
+	pathTestCases := []struct {
+		path    []int
+		wantErr error
+	}{
+		{[]int{0, 0, 0}, ErrInvalidLength},
+		{[]int{0, 0, 0, 0, 0}, ErrInvalidLength},
+		{[]int{0, 0, 4, 0}, ErrOutOfRange},
+		{[]int{0, -1, 0, 0}, ErrOutOfRange},
+		{[]int{3, 3, 3, 3}, nil},
+	}
+
+	for _, tc := range pathTestCases {
+		if _, err := s.FromQuadPath(tc.path); err != tc.wantErr {
+			t.Errorf("FromQuadPath(%v) = %q want %q", tc.path, err, tc.wantErr)
+		}
+	}
+}
+
+func TestQuadPathAllValues(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		s, err := NewHilbert(16, vertical)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		for d := 0; d < s.N*s.N; d++ {
+			path, err := s.QuadPath(d)
+			if err != nil {
+				t.Errorf("QuadPath(%d) returned error: %s", d, err)
+			}
+			got, err := s.FromQuadPath(path)
+			if err != nil {
+				t.Errorf("FromQuadPath(%v) returned error: %s", path, err)
+			}
+			if got != d {
+				t.Errorf("Failed QuadPath(%d) -> FromQuadPath(%v) -> %d", d, path, got)
+			}
+		}
+	}
+}
+
+func TestSameCell(t *testing.T) {
+	testCases := []struct {
+		x0, y0, x1, y1, level int
+		want                  bool
+	}{
+		{0, 0, 15, 15, 0, true},
+		{0, 0, 15, 15, 1, false},
+		{0, 0, 7, 7, 1, true},
+		{0, 0, 7, 7, 2, false},
+		{4, 12, 7, 11, 1, true},
+		{4, 12, 7, 11, 2, false},
+		{4, 12, 7, 15, 2, true},
+		{4, 12, 7, 15, 3, false},
+		{4, 12, 4, 12, 4, true},
+		{4, 12, 5, 12, 4, false},
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		got, err := s.SameCell(tc.x0, tc.y0, tc.x1, tc.y1, tc.level)
+		if err != nil {
+			t.Errorf("SameCell(%d, %d, %d, %d, %d) returned error: %s", tc.x0, tc.y0, tc.x1, tc.y1, tc.level, err)
+		}
+		if got != tc.want {
+			t.Errorf("SameCell(%d, %d, %d, %d, %d) = %t want %t", tc.x0, tc.y0, tc.x1, tc.y1, tc.level, got, tc.want)
+		}
+	}
+}
+
+func TestSameCellErrors(t *testing.T) {
+	testCases := []struct {
+		x0, y0, x1, y1, level int
+	}{
+		{0, 0, 0, 0, -1},
+		{0, 0, 0, 0, 5},
+		{-1, 0, 0, 0, 0},
+		{0, 0, 0, 16, 0},
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		if _, err := s.SameCell(tc.x0, tc.y0, tc.x1, tc.y1, tc.level); err != ErrOutOfRange {
+			t.Errorf("SameCell(%d, %d, %d, %d, %d) = %q want %q", tc.x0, tc.y0, tc.x1, tc.y1, tc.level, err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestSameCellMatchesCoordinates(t *testing.T) {
+	s, err := NewHilbert(16, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for level := 0; level <= s.GetOrder(); level++ {
+		shift := uint(s.GetOrder() - level)
+		for x := 0; x < s.N; x++ {
+			for y := 0; y < s.N; y++ {
+				got, _ := s.SameCell(x, y, 5, 9, level)
+				want := x>>shift == 5>>shift && y>>shift == 9>>shift
+				if got != want {
+					t.Errorf("SameCell(%d, %d, 5, 9, %d) = %t want %t", x, y, level, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDigits(t *testing.T) {
+	testCases := []struct {
+		d    int
+		want []int
+	}{
+		{0, []int{0, 0, 0, 0}},
+		{96, []int{1, 2, 0, 0}},
+		{170, []int{2, 2, 2, 2}},
+		{255, []int{3, 3, 3, 3}},
+		{27, []int{0, 1, 2, 3}},
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		got, err := s.Digits(tc.d)
+		if err != nil {
+			t.Errorf("Digits(%d) returned error: %s", tc.d, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Digits(%d) = %v want %v", tc.d, got, tc.want)
+		}
+
+		back, err := s.FromDigits(got)
+		if err != nil {
+			t.Errorf("FromDigits(%v) returned error: %s", got, err)
+		}
+		if back != tc.d {
+			t.Errorf("FromDigits(%v) = %d want %d", got, back, tc.d)
+		}
+	}
+}
+
+func TestDigitsErrors(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, d := range []int{-1, 256} {
+		if _, err := s.Digits(d); err != ErrOutOfRange {
+			t.Errorf("Digits(%d) = %q want %q", d, err, ErrOutOfRange)
+		}
+	}
+
+	testCases := []struct {
+		digits  []int
+		wantErr error
+	}{
+		{nil, ErrInvalidLength},
+		{[]int{0, 0, 0}, ErrInvalidLength},
+		{[]int{0, 0, 0, 0, 0}, ErrInvalidLength},
+		{[]int{0, 4, 0, 0}, ErrOutOfRange},
+		{[]int{0, 0, 0, -1}, ErrOutOfRange},
+	}
+
+	for _, tc := range testCases {
+		if _, err := s.FromDigits(tc.digits); err != tc.wantErr {
+			t.Errorf("FromDigits(%v) = %q want %q", tc.digits, err, tc.wantErr)
+		}
+	}
+}
+
+func TestMapWithDigits(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(16, true)
+
+	var buf []int
+	for _, s := range []*Hilbert{h, v, h.Reversed()} {
+		for d := 0; d < s.N*s.N; d++ {
+			x, y, digits, err := s.MapWithDigits(d, buf)
+			if err != nil {
+				t.Fatalf("MapWithDigits(%d) returned error: %s", d, err)
+			}
+			wantX, wantY, _ := s.Map(d)
+			wantDigits, _ := s.Digits(d)
+			if x != wantX || y != wantY || !reflect.DeepEqual(digits, wantDigits) {
+				t.Errorf("MapWithDigits(%d) = (%d, %d, %v) want (%d, %d, %v)", d, x, y, digits, wantX, wantY, wantDigits)
+			}
+			buf = digits
+		}
+	}
+
+	// A buffer with enough capacity is reused.
+	buf = make([]int, 0, 8)
+	if _, _, digits, _ := h.MapWithDigits(5, buf); &digits[0] != &buf[:1][0] {
+		t.Errorf("MapWithDigits(5) did not reuse the buffer")
+	}
+	if allocs := testing.AllocsPerRun(100, func() { h.MapWithDigits(200, buf) }); allocs != 0 {
+		t.Errorf("MapWithDigits(200) made %v allocations want 0", allocs)
+	}
+
+	if _, _, _, err := h.MapWithDigits(256, buf); err != ErrOutOfRange {
+		t.Errorf("MapWithDigits(256) = %v want %v", err, ErrOutOfRange)
+	}
+	w, _ := NewHilbert(16, false, WithBoundsPolicy(BoundsWrap))
+	if _, _, _, err := w.MapWithDigits(256, buf); err != ErrOutOfRange {
+		t.Errorf("MapWithDigits(256) with BoundsWrap = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestChildCells(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		coarse, _ := NewHilbert(8, vertical)
+		fine, _ := NewHilbert(16, vertical)
+		for _, pair := range [][2]*Hilbert{{coarse, fine}, {coarse.Reversed(), fine.Reversed()}} {
+			coarse, fine := pair[0], pair[1]
+			for d := 0; d < coarse.N*coarse.N; d++ {
+				cells, err := coarse.ChildCells(d)
+				if err != nil {
+					t.Fatalf("ChildCells(%d) returned error: %s", d, err)
+				}
+
+				x, y, _ := coarse.Map(d)
+				for i, c := range cells {
+					wantX, wantY, _ := fine.Map(4*d + i)
+					if c != (Cell{4*d + i, wantX, wantY}) {
+						t.Errorf("ChildCells(%d)[%d] = %+v want {T:%d X:%d Y:%d}", d, i, c, 4*d+i, wantX, wantY)
+					}
+					if c.X/2 != x || c.Y/2 != y {
+						t.Errorf("ChildCells(%d)[%d] = %+v is not within (%d, %d)", d, i, c, x, y)
+					}
+				}
+			}
+		}
+	}
+
+	s, _ := NewHilbert(4, false)
+	for _, d := range []int{-1, 16} {
+		if _, err := s.ChildCells(d); err != ErrOutOfRange {
+			t.Errorf("ChildCells(%d) = %v want %v", d, err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestCellID(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(16, true)
+	for _, s := range []*Hilbert{h, v.Reversed()} {
+		order := s.GetOrder()
+		for d := 0; d < s.N*s.N; d++ {
+			x, y, _ := s.Map(d)
+			leaf, err := s.CellID(d, order)
+			if err != nil {
+				t.Fatalf("CellID(%d, %d) returned error: %s", d, order, err)
+			}
+			if r, _ := s.CellRange(leaf); r != (Range{d, d}) {
+				t.Errorf("CellRange(CellID(%d, %d)) = %v want {%d %d}", d, order, r, d, d)
+			}
+
+			c := leaf
+			for level := order; level >= 0; level-- {
+				want, _ := s.CellID(d, level)
+				if c != want || c.Level() != level || !c.Contains(leaf) {
+					t.Errorf("Parent chain of CellID(%d, %d) = %b want %b at level %d containing %b", d, order, c, want, level, leaf)
+				}
+
+				// Every cell within the cell's range must be in the same sub-square.
+				r, _ := s.CellRange(c)
+				size := s.N >> uint(level)
+				for _, e := range []int{r.Lo, r.Hi} {
+					ex, ey, _ := s.Map(e)
+					if ex/size != x/size || ey/size != y/size {
+						t.Errorf("CellRange(%b) = %v, %d at (%d, %d) is not in the same cell as (%d, %d)", c, r, e, ex, ey, x, y)
+					}
+				}
+
+				parent, ok := c.Parent()
+				if ok != (level > 0) {
+					t.Errorf("%b.Parent() = (%b, %t) want ok %t", c, parent, ok, level > 0)
+				}
+				if ok && (!parent.Contains(c) || c.Contains(parent) || parent.Children()[c.Index()%4] != c) {
+					t.Errorf("%b.Parent() = %b, which does not have it as a child", c, parent)
+				}
+				c = parent
+			}
+		}
+	}
+
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	root, _ := s.CellID(0, 0)
+	for i, child := range root.Children() {
+		r, _ := s.CellRange(child)
+		if want := (Range{4 * i, 4*i + 3}); r != want {
+			t.Errorf("CellRange(%b) = %v want %v", child, r, want)
+		}
+		sibling := root.Children()[(i+1)%4]
+		if child.Contains(sibling) {
+			t.Errorf("%b.Contains(%b) = true want false", child, sibling)
+		}
+	}
+
+	for _, tc := range [][2]int{{-1, 0}, {16, 0}, {0, -1}, {0, 3}} {
+		if _, err := s.CellID(tc[0], tc[1]); err != ErrOutOfRange {
+			t.Errorf("CellID(%d, %d) = %v want %v", tc[0], tc[1], err, ErrOutOfRange)
+		}
+	}
+	for _, c := range []CellID{0, 1 << 6} {
+		if _, err := s.CellRange(c); err != ErrOutOfRange {
+			t.Errorf("CellRange(%b) = %v want %v", c, err, ErrOutOfRange)
+		}
+	}
+	if CellID(0).Level() != -1 || CellID(0).Contains(root) {
+		t.Errorf("CellID(0) = level %d, containing %b, want invalid", CellID(0).Level(), root)
+	}
+
+	// Marker bits at odd positions are not valid IDs.
+	big, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	for _, c := range []CellID{2, 3, 8, 12, 1<<7 | 5} {
+		if c.Level() != -1 || c.Index() != -1 {
+			t.Errorf("CellID(%b) = level %d, index %d want -1, -1", c, c.Level(), c.Index())
+		}
+		if r, err := big.CellRange(c); err != ErrOutOfRange {
+			t.Errorf("CellRange(%b) = (%v, %v) want %v", c, r, err, ErrOutOfRange)
+		}
+		if p, ok := c.Parent(); ok {
+			t.Errorf("CellID(%b).Parent() = (%b, true) want false", c, p)
+		}
+		if c.Contains(c) || root.Contains(c) || c.Contains(root.Children()[0]) {
+			t.Errorf("CellID(%b).Contains() = true for an invalid ID", c)
+		}
+	}
+}
+
+func TestOrderTooSmall(t *testing.T) {
+	zero, _ := NewHilbert(1, false)
+	one, _ := NewHilbert(2, false)
+
+	if _, err := zero.QuadPath(0); err != ErrOrderTooSmall {
+		t.Errorf("order 0 QuadPath(0) = %v want %v", err, ErrOrderTooSmall)
+	}
+	if _, err := zero.FromQuadPath(nil); err != ErrOrderTooSmall {
+		t.Errorf("order 0 FromQuadPath(nil) = %v want %v", err, ErrOrderTooSmall)
+	}
+	if _, err := zero.Digits(0); err != ErrOrderTooSmall {
+		t.Errorf("order 0 Digits(0) = %v want %v", err, ErrOrderTooSmall)
+	}
+	if _, err := zero.FromDigits(nil); err != ErrOrderTooSmall {
+		t.Errorf("order 0 FromDigits(nil) = %v want %v", err, ErrOrderTooSmall)
+	}
+	if _, _, _, err := zero.MapWithDigits(0, nil); err != ErrOrderTooSmall {
+		t.Errorf("order 0 MapWithDigits(0) = %v want %v", err, ErrOrderTooSmall)
+	}
+	if _, err := zero.QuadConnectivity(0); err != ErrOrderTooSmall {
+		t.Errorf("order 0 QuadConnectivity(0) = %v want %v", err, ErrOrderTooSmall)
+	}
+
+	// Order 1 has a single level.
+	for d := 0; d < 4; d++ {
+		path, err := one.QuadPath(d)
+		if err != nil || len(path) != 1 {
+			t.Errorf("order 1 QuadPath(%d) = (%v, %v) want one entry", d, path, err)
+		}
+		if got, err := one.FromQuadPath(path); err != nil || got != d {
+			t.Errorf("order 1 FromQuadPath(%v) = (%d, %v) want (%d, nil)", path, got, err, d)
+		}
+		if got, err := one.Digits(d); err != nil || !reflect.DeepEqual(got, []int{d}) {
+			t.Errorf("order 1 Digits(%d) = (%v, %v) want ([%d], nil)", d, got, err, d)
+		}
+	}
+	if _, err := one.QuadConnectivity(0); err != nil {
+		t.Errorf("order 1 QuadConnectivity(0) returned error: %s", err)
+	}
+	if _, err := one.QuadConnectivity(1); err != ErrOutOfRange {
+		t.Errorf("order 1 QuadConnectivity(1) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestIsAlignedQuadrant(t *testing.T) {
+	testCases := []struct {
+		x0, y0, x1, y1 int
+		want           bool
+		wantLevel      int
+	}{
+		{0, 0, 15, 15, true, 0},
+		{8, 0, 15, 7, true, 1},
+		{4, 12, 7, 15, true, 2},
+		{6, 2, 7, 3, true, 3},
+		{5, 9, 5, 9, true, 4},
+		{0, 0, 1, 0, false, -1},  // Not square
+		{0, 0, 2, 2, false, -1},  // Not a power of two
+		{1, 1, 2, 2, false, -1},  // Not aligned
+		{4, 0, 11, 7, false, -1}, // Not aligned
+		{0, 0, 16, 16, false, -1},
+		{-1, 0, 0, 1, false, -1},
+		{1, 1, 0, 0, false, -1},
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		got, level := s.IsAlignedQuadrant(tc.x0, tc.y0, tc.x1, tc.y1)
+		if got != tc.want || level != tc.wantLevel {
+			t.Errorf("IsAlignedQuadrant(%d, %d, %d, %d) = (%t, %d) want (%t, %d)", tc.x0, tc.y0, tc.x1, tc.y1, got, level, tc.want, tc.wantLevel)
+		}
+	}
+}
+
+func TestIsAlignedQuadrantSingleRange(t *testing.T) {
+	s, err := NewHilbert(8, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for x0 := 0; x0 < s.N; x0++ {
+		for y0 := 0; y0 < s.N; y0++ {
+			for x1 := x0; x1 < s.N; x1++ {
+				for y1 := y0; y1 < s.N; y1++ {
+					aligned, level := s.IsAlignedQuadrant(x0, y0, x1, y1)
+					if !aligned {
+						continue
+					}
+					ranges, _ := s.RangeQuery(x0, y0, x1, y1)
+					if size := s.N >> uint(level); len(ranges) != 1 || ranges[0].Len() != size*size {
+						t.Errorf("IsAlignedQuadrant(%d, %d, %d, %d) = (true, %d) but RangeQuery() = %v", x0, y0, x1, y1, level, ranges)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Quantizer maps real-valued positions within a known world box to and from the cells of a
+// Hilbert curve, dividing the box into N by N equally sized cells.
+type Quantizer struct {
+	curve                  *Hilbert
+	minX, minY, maxX, maxY float64
+	scaleX, scaleY         float64 // Cells per world unit
+}
+
+// NewQuantizer returns a new Quantizer for the world box with corners (minX,minY) and
+// (maxX,maxY), using curve. Positions outside of the box are handled with the curve's
+// BoundsPolicy. ErrNotFinite is returned if the box is not finite, and ErrOutOfRange if it is
+// empty.
+func NewQuantizer(curve *Hilbert, minX, minY, maxX, maxY float64) (*Quantizer, error) {
+	for _, f := range [4]float64{minX, minY, maxX, maxY} {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return nil, ErrNotFinite
+		}
+	}
+	if maxX <= minX || maxY <= minY {
+		return nil, ErrOutOfRange
+	}
+
+	return &Quantizer{
+		curve:  curve,
+		minX:   minX,
+		minY:   minY,
+		maxX:   maxX,
+		maxY:   maxY,
+		scaleX: float64(curve.N) / (maxX - minX),
+		scaleY: float64(curve.N) / (maxY - minY),
+	}, nil
+}
+
+// Index returns the value on the curve of the cell containing the position (x,y). Positions on
+// the maximum edges of the box are in the last row or column of cells. ErrNotFinite is returned
+// if the position is not finite, and ErrOutOfRange if it is outside of the box and the curve's
+// BoundsPolicy is BoundsError.
+func (q *Quantizer) Index(x, y float64) (int, error) {
+	cx, err := q.cell(x, q.minX, q.maxX, q.scaleX)
+	if err != nil {
+		return -1, err
+	}
+	cy, err := q.cell(y, q.minY, q.maxY, q.scaleY)
+	if err != nil {
+		return -1, err
+	}
+	return q.curve.MapInverse(cx, cy)
+}
+
+// cell returns the column or row of cells containing f, applying the curve's BoundsPolicy if it is
+// outside of [min, max].
+func (q *Quantizer) cell(f, min, max, scale float64) (int, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return -1, ErrNotFinite
+	}
+
+	n := float64(q.curve.N)
+	v := math.Floor((f - min) * scale)
+	if f >= min && f <= max {
+		return int(math.Min(v, n-1)), nil // Rounding may push positions near max to N.
+	}
+
+	switch q.curve.bounds {
+	case BoundsClamp:
+		return int(math.Max(0, math.Min(v, n-1))), nil
+	case BoundsWrap:
+		if v = math.Mod(v, n); v < 0 {
+			v += n
+		}
+		return int(v), nil
+	}
+	return -1, ErrOutOfRange
+}
+
+// Decode returns the position of the centre of the cell at t on the curve.
+func (q *Quantizer) Decode(t int) (x, y float64, err error) {
+	cx, cy, err := q.curve.Map(t)
+	if err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	return q.minX + (float64(cx)+0.5)/q.scaleX, q.minY + (float64(cy)+0.5)/q.scaleY, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"testing"
+)
+
+func TestQuantizer(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	// Cells are 0.25 wide and 2 high.
+	q, err := NewQuantizer(s, -2, 0, 2, 32)
+	if err != nil {
+		t.Fatalf("NewQuantizer() failed: %s", err)
+	}
+
+	for d := 0; d < s.N*s.N; d++ {
+		x, y, err := q.Decode(d)
+		if err != nil {
+			t.Errorf("Decode(%d) failed: %s", d, err)
+			continue
+		}
+		cx, cy, _ := s.Map(d)
+		if wantX, wantY := -2+0.25*float64(cx)+0.125, 2*float64(cy)+1; x != wantX || y != wantY {
+			t.Errorf("Decode(%d) = (%g, %g) want (%g, %g)", d, x, y, wantX, wantY)
+		}
+		if got, err := q.Index(x, y); err != nil || got != d {
+			t.Errorf("Index(%g, %g) = (%d, %v) want (%d, nil)", x, y, got, err, d)
+		}
+	}
+
+	testCases := []struct {
+		x, y   float64
+		cx, cy int
+	}{
+		{-2, 0, 0, 0},
+		{2, 32, 15, 15}, // The maximum edges are in the last cells
+		{-1.76, 1.99, 0, 0},
+		{-1.75, 2, 1, 1},
+		{0, 16, 8, 8},
+	}
+	for _, tc := range testCases {
+		want, _ := s.MapInverse(tc.cx, tc.cy)
+		if got, err := q.Index(tc.x, tc.y); err != nil || got != want {
+			t.Errorf("Index(%g, %g) = (%d, %v) want (%d, nil)", tc.x, tc.y, got, err, want)
+		}
+	}
+
+	if _, err := q.Index(2.1, 0); err != ErrOutOfRange {
+		t.Errorf("Index(2.1, 0) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := q.Index(0, math.NaN()); err != ErrNotFinite {
+		t.Errorf("Index(0, NaN) = %v want %v", err, ErrNotFinite)
+	}
+	if _, _, err := q.Decode(256); err != ErrOutOfRange {
+		t.Errorf("Decode(256) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestQuantizerBounds(t *testing.T) {
+	clamped, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsClamp))
+	q, err := NewQuantizer(clamped, 0, 0, 1, 1)
+	if err != nil {
+		t.Fatalf("NewQuantizer() failed: %s", err)
+	}
+	want, _ := clamped.MapInverse(3, 0)
+	if got, err := q.Index(5, -5); err != nil || got != want {
+		t.Errorf("Index(5, -5) with BoundsClamp = (%d, %v) want (%d, nil)", got, err, want)
+	}
+
+	wrapped, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsWrap))
+	q, err = NewQuantizer(wrapped, 0, 0, 1, 1)
+	if err != nil {
+		t.Fatalf("NewQuantizer() failed: %s", err)
+	}
+	want, _ = wrapped.MapInverse(1, 3)
+	if got, err := q.Index(1.3, -0.1); err != nil || got != want {
+		t.Errorf("Index(1.3, -0.1) with BoundsWrap = (%d, %v) want (%d, nil)", got, err, want)
+	}
+}
+
+func TestNewQuantizerErrors(t *testing.T) {
+	s, _ := NewHilbert(4, false)
+
+	testCases := []struct {
+		minX, minY, maxX, maxY float64
+		want                   error
+	}{
+		{math.NaN(), 0, 1, 1, ErrNotFinite},
+		{0, math.Inf(-1), 1, 1, ErrNotFinite},
+		{0, 0, 0, 1, ErrOutOfRange},
+		{0, 1, 1, 0, ErrOutOfRange},
+		{0, 0, 1, 1, nil},
+	}
+
+	for _, tc := range testCases {
+		if _, err := NewQuantizer(s, tc.minX, tc.minY, tc.maxX, tc.maxY); err != tc.want {
+			t.Errorf("NewQuantizer(%g, %g, %g, %g) = %v want %v", tc.minX, tc.minY, tc.maxX, tc.maxY, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,593 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"iter"
+	"math"
+	"sort"
+)
+
+// Range represents the inclusive range of values, [Lo, Hi], along a curve.
+type Range struct {
+	Lo, Hi int
+}
+
+// Len returns the number of values in the range.
+func (r Range) Len() int {
+	return r.Hi - r.Lo + 1
+}
+
+// Contains returns true if t is within the range.
+func (r Range) Contains(t int) bool {
+	return t >= r.Lo && t <= r.Hi
+}
+
+// Overlaps returns true if the two ranges have at least one value in common.
+func (r Range) Overlaps(o Range) bool {
+	return r.Lo <= o.Hi && o.Lo <= r.Hi
+}
+
+// Merge returns the union of the two ranges. If the ranges neither overlap nor are adjacent, the
+// union is not a single range, and false is returned.
+func (r Range) Merge(o Range) (Range, bool) {
+	if r.Lo > o.Hi+1 || o.Lo > r.Hi+1 {
+		return r, false
+	}
+	if o.Lo < r.Lo {
+		r.Lo = o.Lo
+	}
+	if o.Hi > r.Hi {
+		r.Hi = o.Hi
+	}
+	return r, true
+}
+
+// MergeRanges returns a sorted copy of ranges, with all overlapping and adjacent ranges
+// coalesced.
+func MergeRanges(ranges []Range) []Range {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sorted := append([]Range(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Lo < sorted[j].Lo
+	})
+
+	merged := sorted[:1]
+	for _, r := range sorted[1:] {
+		if m, ok := merged[len(merged)-1].Merge(r); ok {
+			merged[len(merged)-1] = m
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// RangeDiff returns the values in after but not in before, entered, and the values in before but
+// not in after, left, such as the cells entering and leaving a moving viewport. The inputs may be
+// unsorted and overlapping, and the outputs are sorted and merged as by MergeRanges.
+func RangeDiff(before, after []Range) (entered, left []Range) {
+	before, after = MergeRanges(before), MergeRanges(after)
+	return subtractRanges(after, before), subtractRanges(before, after)
+}
+
+// subtractRanges returns the values in a but not in b, both of which must be sorted and merged.
+func subtractRanges(a, b []Range) []Range {
+	var out []Range
+	j := 0
+	for _, r := range a {
+		// Skip the ranges of b entirely before r, then cut out those overlapping it.
+		for j < len(b) && b[j].Hi < r.Lo {
+			j++
+		}
+		lo := r.Lo
+		for k := j; k < len(b) && b[k].Lo <= r.Hi; k++ {
+			if b[k].Lo > lo {
+				out = append(out, Range{lo, b[k].Lo - 1})
+			}
+			lo = b[k].Hi + 1
+		}
+		if lo <= r.Hi {
+			out = append(out, Range{lo, r.Hi})
+		}
+	}
+	return out
+}
+
+// validRect returns a *RangeError for the first invalid corner coordinate if the rectangle with
+// corners (x0,y0) and (x1,y1) inclusive is not within the space, or if the corners are not
+// ordered.
+func (s *Hilbert) validRect(x0, y0, x1, y1 int) error {
+	switch {
+	case x1 < 0 || x1 >= s.N:
+		return &RangeError{Name: "x1", Value: x1, Min: 0, Max: s.N - 1}
+	case x0 < 0 || x0 > x1:
+		return &RangeError{Name: "x0", Value: x0, Min: 0, Max: x1}
+	case y1 < 0 || y1 >= s.N:
+		return &RangeError{Name: "y1", Value: y1, Min: 0, Max: s.N - 1}
+	case y0 < 0 || y0 > y1:
+		return &RangeError{Name: "y0", Value: y0, Min: 0, Max: y1}
+	}
+	return nil
+}
+
+// validRange returns a *RangeError for the first invalid bound if [lo, hi] is not a range of
+// values on the curve, naming the bounds loName and hiName.
+func (s *Hilbert) validRange(lo, hi int, loName, hiName string) error {
+	switch {
+	case hi < 0 || hi >= s.N*s.N:
+		return &RangeError{Name: hiName, Value: hi, Min: 0, Max: s.N*s.N - 1}
+	case lo < 0 || lo > hi:
+		return &RangeError{Name: loName, Value: lo, Min: 0, Max: hi}
+	}
+	return nil
+}
+
+// RangeQuery returns the sorted list of ranges on the curve that exactly cover the rectangle
+// with corners (x0,y0) and (x1,y1) inclusive. Adjacent ranges are merged, so each range is
+// separated from the next by at least one value outside of the rectangle.
+func (s *Hilbert) RangeQuery(x0, y0, x1, y1 int) ([]Range, error) {
+	if err := s.validRect(x0, y0, x1, y1); err != nil {
+		return nil, err
+	}
+
+	var ranges []Range
+	s.rangeQuery(x0, y0, x1, y1, 0, s.N, func(r Range) bool {
+		if n := len(ranges); n > 0 {
+			if m, ok := ranges[n-1].Merge(r); ok {
+				ranges[n-1] = m
+				return true
+			}
+		}
+		ranges = append(ranges, r)
+		return true
+	})
+	return ranges, nil
+}
+
+// RangeQuerySeq returns an iterator over the same ranges as RangeQuery, generated as they are
+// iterated, so the first ranges are available before the whole rectangle has been decomposed and
+// the memory used does not depend on the number of ranges. Nothing is yielded if the rectangle is
+// not within the space.
+func (s *Hilbert) RangeQuerySeq(x0, y0, x1, y1 int) iter.Seq[Range] {
+	return func(yield func(Range) bool) {
+		if s.validRect(x0, y0, x1, y1) != nil {
+			return
+		}
+
+		// Hold back each range until the next one is known not to merge with it.
+		var pending Range
+		started := false
+		if !s.rangeQuery(x0, y0, x1, y1, 0, s.N, func(r Range) bool {
+			if started {
+				if m, ok := pending.Merge(r); ok {
+					pending = m
+					return true
+				}
+				if !yield(pending) {
+					return false
+				}
+			}
+			pending, started = r, true
+			return true
+		}) {
+			return
+		}
+		if started {
+			yield(pending)
+		}
+	}
+}
+
+// rangeQuery recursively visits the sub-square of width size, whose values on the curve start at
+// t, calling emit in ascending order with each range of values within the rectangle. The ranges
+// are not merged. If emit returns false, the visit stops and false is returned.
+func (s *Hilbert) rangeQuery(x0, y0, x1, y1, t, size int, emit func(Range) bool) bool {
+	// Sub-squares are always aligned to their size, so the corner can be found from any cell.
+	x, y, _ := s.Map(t)
+	x, y = x/size*size, y/size*size
+
+	if x > x1 || y > y1 || x+size-1 < x0 || y+size-1 < y0 {
+		return true // Disjoint
+	}
+	if x >= x0 && y >= y0 && x+size-1 <= x1 && y+size-1 <= y1 {
+		return emit(Range{t, t + size*size - 1}) // Contained
+	}
+
+	size /= 2
+	for i := 0; i < 4; i++ {
+		if !s.rangeQuery(x0, y0, x1, y1, t+i*size*size, size, emit) {
+			return false
+		}
+	}
+	return true
+}
+
+// IndicesWithinRadius returns the sorted list of ranges on the curve that cover every cell within
+// the Euclidean distance r of the cell (x,y), so a radius join only needs to read these ranges
+// before refining by the exact distance. The ranges are merged as in RangeQuery, and cover no
+// cells further away than r.
+func (s *Hilbert) IndicesWithinRadius(x, y, r int) ([]Range, error) {
+	switch {
+	case x < 0 || x >= s.N:
+		return nil, &RangeError{Name: "x", Value: x, Min: 0, Max: s.N - 1}
+	case y < 0 || y >= s.N:
+		return nil, &RangeError{Name: "y", Value: y, Min: 0, Max: s.N - 1}
+	case r < 0:
+		return nil, &RangeError{Name: "r", Value: r, Min: 0, Max: math.MaxInt}
+	}
+
+	// Every cell is within sqrt(2)*(N-1) of (x,y), so larger radii cover the whole space. The
+	// squared radius is clamped to 2*(N-1)^2, which fits in an int even at MaxOrder.
+	maxR2 := 2 * (s.N - 1) * (s.N - 1)
+	r2, ok := mulChecked(r, r)
+	if !ok || r2 > maxR2 {
+		r, r2 = s.N-1, maxR2
+	}
+
+	var ranges []Range
+	for cy := max(0, y-r); cy <= min(s.N-1, y+r); cy++ {
+		dy := cy - y
+		w := int(math.Sqrt(float64(r2 - dy*dy)))
+		for w*w > r2-dy*dy {
+			w--
+		}
+		for (w+1)*(w+1) <= r2-dy*dy {
+			w++
+		}
+
+		row, _ := s.RangeQuery(max(0, x-w), cy, min(s.N-1, x+w), cy)
+		ranges = append(ranges, row...)
+	}
+	return MergeRanges(ranges), nil
+}
+
+// CountInRect returns the number of cells within the rectangle with corners (x0,y0) and (x1,y1)
+// inclusive, after clipping it to the space, so a rectangle entirely outside of the space has no
+// cells. A *RangeError is returned if the corners are not ordered.
+func (s *Hilbert) CountInRect(x0, y0, x1, y1 int) (int, error) {
+	if x0 > x1 {
+		return 0, &RangeError{Name: "x0", Value: x0, Min: math.MinInt, Max: x1}
+	}
+	if y0 > y1 {
+		return 0, &RangeError{Name: "y0", Value: y0, Min: math.MinInt, Max: y1}
+	}
+
+	x0, y0 = max(x0, 0), max(y0, 0)
+	x1, y1 = min(x1, s.N-1), min(y1, s.N-1)
+	if x0 > x1 || y0 > y1 {
+		return 0, nil
+	}
+	return (x1 - x0 + 1) * (y1 - y0 + 1), nil
+}
+
+// CentroidMode selects how RangeCentroid picks the representative cell of a range.
+type CentroidMode int
+
+// Supported centroid modes.
+const (
+	// CentroidMiddle uses the cell of the middle value of the range, which takes constant time.
+	CentroidMiddle CentroidMode = iota
+
+	// CentroidSpatial uses the cell in the range nearest to the mean position of all the cells in
+	// the range, which takes time proportional to the length of the range. The earliest cell on
+	// the curve is used if several are equally near.
+	CentroidSpatial
+)
+
+// RangeCentroid returns a representative cell for the values in [lo, hi] on the curve, such as
+// for placing a label, picked as described by mode. The cell is always one of the range's cells.
+func (s *Hilbert) RangeCentroid(lo, hi int, mode CentroidMode) (x, y int, err error) {
+	if err := s.validRange(lo, hi, "lo", "hi"); err != nil {
+		return -1, -1, err
+	}
+
+	switch mode {
+	case CentroidMiddle:
+		return s.Map(lo + (hi-lo)/2)
+	case CentroidSpatial:
+		x, y = s.spatialCentroid(lo, hi)
+		return x, y, nil
+	default:
+		return -1, -1, ErrOutOfRange
+	}
+}
+
+// spatialCentroid returns the cell for CentroidSpatial of the values in [lo, hi], which must be
+// within the space.
+func (s *Hilbert) spatialCentroid(lo, hi int) (x, y int) {
+	var sumX, sumY float64
+	for t := lo; t <= hi; t++ {
+		cx, cy, _ := s.Map(t)
+		sumX += float64(cx)
+		sumY += float64(cy)
+	}
+	n := float64(hi - lo + 1)
+	mx, my := sumX/n, sumY/n
+
+	best := math.Inf(1)
+	for t := lo; t <= hi; t++ {
+		cx, cy, _ := s.Map(t)
+		if d := math.Hypot(float64(cx)-mx, float64(cy)-my); d < best {
+			best, x, y = d, cx, cy
+		}
+	}
+	return x, y
+}
+
+// RangesCoverRect checks how well ranges cover the rectangle with corners (x0,y0) and (x1,y1)
+// inclusive, returning the number of cells in the rectangle which are not in any range, and the
+// number of cells in the ranges which are outside of the rectangle. Both are zero if the ranges
+// cover the rectangle exactly. The ranges may overlap and be in any order. A *RangeError is
+// returned if the rectangle is invalid, or within an *IndexError if a range is empty or not on
+// the curve.
+func (s *Hilbert) RangesCoverRect(ranges []Range, x0, y0, x1, y1 int) (missing, extra int, err error) {
+	want, err := s.RangeQuery(x0, y0, x1, y1)
+	if err != nil {
+		return 0, 0, err
+	}
+	for i, r := range ranges {
+		if err := s.validRange(r.Lo, r.Hi, "Lo", "Hi"); err != nil {
+			return 0, 0, &IndexError{Index: i, Err: err}
+		}
+	}
+	got := MergeRanges(ranges)
+
+	// Both lists are sorted and disjoint, so sweep through them counting the cells in common.
+	common := 0
+	for i, j := 0, 0; i < len(got) && j < len(want); {
+		if lo, hi := max(got[i].Lo, want[j].Lo), min(got[i].Hi, want[j].Hi); lo <= hi {
+			common += hi - lo + 1
+		}
+		if got[i].Hi < want[j].Hi {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	covered := 0
+	for _, r := range got {
+		covered += r.Len()
+	}
+	return (x1-x0+1)*(y1-y0+1) - common, covered - common, nil
+}
+
+// RectPoints returns an iterator over the coordinates of every cell within the rectangle with
+// corners (x0,y0) and (x1,y1) inclusive, in ascending order along the curve. Nothing is yielded
+// if the rectangle is not within the space. The cells are generated as they are iterated, so the
+// memory used does not depend on the size of the rectangle.
+func (s *Hilbert) RectPoints(x0, y0, x1, y1 int) iter.Seq[[2]int] {
+	return func(yield func([2]int) bool) {
+		if s.validRect(x0, y0, x1, y1) != nil {
+			return
+		}
+		s.rangeQuery(x0, y0, x1, y1, 0, s.N, func(r Range) bool {
+			for t := r.Lo; t <= r.Hi; t++ {
+				x, y, _ := s.Map(t)
+				if !yield([2]int{x, y}) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+}
+
+// AroundPoint returns an iterator over every cell in the space, ordered by increasing distance
+// along the curve from the cell (x,y), starting with (x,y) itself. When two cells are the same
+// distance away, the one earlier on the curve is yielded first. Nothing is yielded if (x,y) is not
+// within the space.
+func (s *Hilbert) AroundPoint(x, y int) iter.Seq[[2]int] {
+	return func(yield func([2]int) bool) {
+		t, err := s.MapInverse(x, y)
+		if err != nil {
+			return
+		}
+		emit := func(d int) bool {
+			x, y, _ := s.Map(d)
+			return yield([2]int{x, y})
+		}
+
+		if !emit(t) {
+			return
+		}
+		for d := 1; t-d >= 0 || t+d < s.N*s.N; d++ {
+			if t-d >= 0 && !emit(t-d) {
+				return
+			}
+			if t+d < s.N*s.N && !emit(t+d) {
+				return
+			}
+		}
+	}
+}
+
+// ExpandingRings returns an iterator over the rings of cells around the cell (x,y), for prefetching
+// outwards from a point of focus. For each radius r, starting at 1, the sorted and merged ranges
+// covering the cells at a Chebyshev distance of exactly r from (x,y) are yielded, leaving out
+// those outside of the space. The iteration stops once a ring lies entirely outside the space.
+// Nothing is yielded if (x,y) is not within the space.
+func (s *Hilbert) ExpandingRings(x, y int) iter.Seq[[]Range] {
+	return func(yield func([]Range) bool) {
+		if x < 0 || x >= s.N || y < 0 || y >= s.N {
+			return
+		}
+
+		for r := 1; x-r >= 0 || y-r >= 0 || x+r < s.N || y+r < s.N; r++ {
+			var ranges []Range
+			add := func(x0, y0, x1, y1 int) {
+				x0, y0, x1, y1 = max(x0, 0), max(y0, 0), min(x1, s.N-1), min(y1, s.N-1)
+				if x0 > x1 || y0 > y1 {
+					return
+				}
+				s.rangeQuery(x0, y0, x1, y1, 0, s.N, func(rg Range) bool {
+					ranges = append(ranges, rg)
+					return true
+				})
+			}
+
+			if y-r >= 0 {
+				add(x-r, y-r, x+r, y-r) // Top
+			}
+			if y+r < s.N {
+				add(x-r, y+r, x+r, y+r) // Bottom
+			}
+			if x-r >= 0 {
+				add(x-r, y-r+1, x-r, y+r-1) // Left
+			}
+			if x+r < s.N {
+				add(x+r, y-r+1, x+r, y+r-1) // Right
+			}
+
+			if !yield(MergeRanges(ranges)) {
+				return
+			}
+		}
+	}
+}
+
+// RangeQueryBudget is like RangeQuery, but merges neighbouring ranges to reduce the number of
+// ranges returned, as long as the extra values read outside of the rectangle stay within
+// maxOverreadPct percent of the number of cells in the rectangle. The smallest gaps are merged
+// first, which minimises the number of ranges for the budget. The actual over-read achieved, as a
+// percentage, is also returned.
+func (s *Hilbert) RangeQueryBudget(x0, y0, x1, y1 int, maxOverreadPct float64) ([]Range, float64, error) {
+	if maxOverreadPct < 0 || math.IsNaN(maxOverreadPct) {
+		return nil, 0, ErrOutOfRange
+	}
+
+	ranges, err := s.RangeQuery(x0, y0, x1, y1)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	area := (x1 - x0 + 1) * (y1 - y0 + 1)
+	budget := maxOverreadPct / 100 * float64(area)
+
+	merge := make([]bool, len(ranges)-1)
+	extra := 0
+	for _, i := range smallestGaps(ranges) {
+		if float64(extra+gapLen(ranges, i)) > budget {
+			break
+		}
+		extra += gapLen(ranges, i)
+		merge[i] = true
+	}
+
+	return mergeGaps(ranges, merge), float64(extra) / float64(area) * 100, nil
+}
+
+// Ranges is like RangeQuery, but returns at most maxRanges ranges, by merging neighbouring ranges
+// across the smallest gaps first. This covers the rectangle with the fewest extra values outside
+// of it for the number of ranges, trading precision for fewer range scans in an ordered store.
+// ErrNotPositive is returned if maxRanges is less than one.
+func (s *Hilbert) Ranges(x0, y0, x1, y1 int, maxRanges int) ([]Range, error) {
+	if maxRanges < 1 {
+		return nil, ErrNotPositive
+	}
+
+	ranges, err := s.RangeQuery(x0, y0, x1, y1)
+	if err != nil || len(ranges) <= maxRanges {
+		return ranges, err
+	}
+
+	merge := make([]bool, len(ranges)-1)
+	for _, i := range smallestGaps(ranges)[:len(ranges)-maxRanges] {
+		merge[i] = true
+	}
+	return mergeGaps(ranges, merge), nil
+}
+
+// gapLen returns the number of values between ranges[i] and ranges[i+1].
+func gapLen(ranges []Range, i int) int {
+	return ranges[i+1].Lo - ranges[i].Hi - 1
+}
+
+// smallestGaps returns the indices of the gaps between the sorted ranges, where gap i is between
+// ranges[i] and ranges[i+1], ordered from the smallest gap to the largest.
+func smallestGaps(ranges []Range) []int {
+	gaps := make([]int, len(ranges)-1)
+	for i := range gaps {
+		gaps[i] = i
+	}
+	sort.SliceStable(gaps, func(i, j int) bool {
+		return gapLen(ranges, gaps[i]) < gapLen(ranges, gaps[j])
+	})
+	return gaps
+}
+
+// mergeGaps joins ranges[i] and ranges[i+1] for every i where merge[i] is true, modifying ranges
+// in place.
+func mergeGaps(ranges []Range, merge []bool) []Range {
+	merged := ranges[:1]
+	for i, r := range ranges[1:] {
+		if merge[i] {
+			merged[len(merged)-1].Hi = r.Hi
+		} else {
+			merged = append(merged, r)
+		}
+	}
+	return merged
+}
+
+// Chunks returns an iterator over consecutive ranges which together exactly cover the whole
+// curve, [0, N*N-1]. Each range covers one square sub-quadrant of the space, so is spatially
+// compact. The ranges all have the same length, the power of four nearest to approxSize by ratio
+// rather than difference, limited to [1, N*N]. That is, approxSize is rounded to the nearest power
+// of four in log space, so 9 gives 16 rather than 4, and 2 gives 4 rather than 1.
+func (s *Hilbert) Chunks(approxSize int) iter.Seq[Range] {
+	level := 0
+	if approxSize > 1 {
+		level = int(math.Round(math.Log2(float64(approxSize)) / 2))
+	}
+	if order := s.GetOrder(); level > order {
+		level = order
+	}
+	size := 1 << uint(2*level)
+
+	return func(yield func(Range) bool) {
+		for t := 0; t < s.N*s.N; t += size {
+			if !yield(Range{t, t + size - 1}) {
+				return
+			}
+		}
+	}
+}
+
+// BoundaryPoints returns an iterator over the coordinates of the cells on the outer edge of the
+// space, in ascending order along the curve. For N=1 the single cell is yielded.
+func (s *Hilbert) BoundaryPoints() iter.Seq[[2]int] {
+	return func(yield func([2]int) bool) {
+		n := s.N - 1
+		var ranges []Range
+		for _, edge := range [4][4]int{{0, 0, n, 0}, {0, n, n, n}, {0, 0, 0, n}, {n, 0, n, n}} {
+			r, _ := s.RangeQuery(edge[0], edge[1], edge[2], edge[3])
+			ranges = append(ranges, r...)
+		}
+
+		for _, r := range MergeRanges(ranges) {
+			for t := r.Lo; t <= r.Hi; t++ {
+				x, y, _ := s.Map(t)
+				if !yield([2]int{x, y}) {
+					return
+				}
+			}
+		}
+	}
+}
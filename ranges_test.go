@@ -0,0 +1,782 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// bruteForceRanges returns the ranges covering the rectangle, by testing every value on the curve.
+func bruteForceRanges(s *Hilbert, x0, y0, x1, y1 int) []Range {
+	var ranges []Range
+	for d := 0; d < s.N*s.N; d++ {
+		x, y, _ := s.Map(d)
+		if x < x0 || x > x1 || y < y0 || y > y1 {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].Hi+1 == d {
+			ranges[n-1].Hi = d
+		} else {
+			ranges = append(ranges, Range{d, d})
+		}
+	}
+	return ranges
+}
+
+func TestRangeQuery(t *testing.T) {
+	testCases := []struct {
+		x0, y0, x1, y1 int
+		want           []Range
+	}{
+		{0, 0, 15, 15, []Range{{0, 255}}},
+		{0, 0, 1, 1, []Range{{0, 3}}},
+		{4, 12, 4, 12, []Range{{96, 96}}},
+		{0, 8, 7, 15, []Range{{64, 127}}},
+		{0, 0, 15, 0, []Range{{0, 1}, {14, 16}, {19, 21}, {234, 236}, {239, 241}, {254, 255}}},
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		got, err := s.RangeQuery(tc.x0, tc.y0, tc.x1, tc.y1)
+		if err != nil {
+			t.Errorf("RangeQuery(%d, %d, %d, %d) returned error: %s", tc.x0, tc.y0, tc.x1, tc.y1, err)
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("RangeQuery(%d, %d, %d, %d) = %v want %v", tc.x0, tc.y0, tc.x1, tc.y1, got, tc.want)
+		}
+	}
+}
+
+func TestRangeQueryErrors(t *testing.T) {
+	testCases := []struct {
+		x0, y0, x1, y1 int
+		want           RangeError
+	}{
+		{-1, 0, 1, 1, RangeError{"x0", -1, 0, 1}},
+		{0, -1, 1, 1, RangeError{"y0", -1, 0, 1}},
+		{0, 0, 16, 1, RangeError{"x1", 16, 0, 15}},
+		{0, 0, 1, 16, RangeError{"y1", 16, 0, 15}},
+		{2, 0, 1, 1, RangeError{"x0", 2, 0, 1}},
+		{0, 2, 1, 1, RangeError{"y0", 2, 0, 1}},
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		_, err := s.RangeQuery(tc.x0, tc.y0, tc.x1, tc.y1)
+		if !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("RangeQuery(%d, %d, %d, %d) = %q want %q", tc.x0, tc.y0, tc.x1, tc.y1, err, ErrOutOfRange)
+		}
+		var re *RangeError
+		if !errors.As(err, &re) || *re != tc.want {
+			t.Errorf("RangeQuery(%d, %d, %d, %d) = %q want %q", tc.x0, tc.y0, tc.x1, tc.y1, err, &tc.want)
+		}
+		if _, _, err := s.RangeQueryBudget(tc.x0, tc.y0, tc.x1, tc.y1, 10); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("RangeQueryBudget(%d, %d, %d, %d) = %q want %q", tc.x0, tc.y0, tc.x1, tc.y1, err, ErrOutOfRange)
+		}
+	}
+
+	if _, _, err := s.RangeQueryBudget(0, 0, 1, 1, -1); err != ErrOutOfRange {
+		t.Errorf("RangeQueryBudget(0, 0, 1, 1, -1) = %q want %q", err, ErrOutOfRange)
+	}
+}
+
+func TestRangeQueryRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	h, _ := NewHilbert(32, false)
+	v, _ := NewHilbert(32, true)
+	for _, s := range []*Hilbert{h, v, h.Reversed()} {
+		for i := 0; i < 100; i++ {
+			x0, y0 := r.Intn(s.N), r.Intn(s.N)
+			x1, y1 := x0+r.Intn(s.N-x0), y0+r.Intn(s.N-y0)
+
+			got, err := s.RangeQuery(x0, y0, x1, y1)
+			if err != nil {
+				t.Errorf("RangeQuery(%d, %d, %d, %d) returned error: %s", x0, y0, x1, y1, err)
+			}
+			if want := bruteForceRanges(s, x0, y0, x1, y1); !reflect.DeepEqual(got, want) {
+				t.Errorf("RangeQuery(%d, %d, %d, %d) = %v want %v", x0, y0, x1, y1, got, want)
+			}
+		}
+	}
+}
+
+func TestRangeQuerySeq(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	h, _ := NewHilbert(32, false)
+	v, _ := NewHilbert(32, true)
+	for _, s := range []*Hilbert{h, v, h.Reversed()} {
+		for i := 0; i < 100; i++ {
+			x0, y0 := r.Intn(s.N), r.Intn(s.N)
+			x1, y1 := x0+r.Intn(s.N-x0), y0+r.Intn(s.N-y0)
+
+			var got []Range
+			for rg := range s.RangeQuerySeq(x0, y0, x1, y1) {
+				got = append(got, rg)
+			}
+			if want, _ := s.RangeQuery(x0, y0, x1, y1); !reflect.DeepEqual(got, want) {
+				t.Errorf("RangeQuerySeq(%d, %d, %d, %d) = %v want %v", x0, y0, x1, y1, got, want)
+			}
+		}
+	}
+
+	var got []Range
+	for rg := range h.RangeQuerySeq(1, 1, 30, 30) {
+		got = append(got, rg)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if want, _ := h.RangeQuery(1, 1, 30, 30); !reflect.DeepEqual(got, want[:2]) {
+		t.Errorf("RangeQuerySeq(1, 1, 30, 30) first 2 = %v want %v", got, want[:2])
+	}
+
+	for rg := range h.RangeQuerySeq(0, 0, 32, 0) {
+		t.Errorf("RangeQuerySeq(0, 0, 32, 0) yielded %v, want nothing", rg)
+	}
+}
+
+func TestRangeQueryBudget(t *testing.T) {
+	s, err := NewHilbert(32, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	x0, y0, x1, y1 := 3, 5, 20, 27
+	area := (x1 - x0 + 1) * (y1 - y0 + 1)
+	exact, _ := s.RangeQuery(x0, y0, x1, y1)
+
+	prev := len(exact) + 1
+	for _, pct := range []float64{0, 1, 5, 10, 50, 100, 1000} {
+		got, overread, err := s.RangeQueryBudget(x0, y0, x1, y1, pct)
+		if err != nil {
+			t.Fatalf("RangeQueryBudget(%f) returned error: %s", pct, err)
+		}
+		if overread > pct {
+			t.Errorf("RangeQueryBudget(%f) over-read %f%%, want at most %f%%", pct, overread, pct)
+		}
+		if len(got) > prev {
+			t.Errorf("RangeQueryBudget(%f) returned %d ranges, more than %d for a smaller budget", pct, len(got), prev)
+		}
+		prev = len(got)
+
+		// Every exact range must be inside one of the returned ranges, and the extra values
+		// must match the reported over-read.
+		total := 0
+		for _, r := range got {
+			total += r.Len()
+		}
+		if want := float64(total-area) / float64(area) * 100; overread != want {
+			t.Errorf("RangeQueryBudget(%f) reported over-read %f%% want %f%%", pct, overread, want)
+		}
+		j := 0
+		for _, e := range exact {
+			for j < len(got) && got[j].Hi < e.Lo {
+				j++
+			}
+			if j == len(got) || got[j].Lo > e.Lo || got[j].Hi < e.Hi {
+				t.Errorf("RangeQueryBudget(%f) = %v does not cover %v", pct, got, e)
+				break
+			}
+		}
+	}
+
+	got, overread, _ := s.RangeQueryBudget(x0, y0, x1, y1, 0)
+	if !reflect.DeepEqual(got, exact) || overread != 0 {
+		t.Errorf("RangeQueryBudget(0) = (%v, %f) want (%v, 0)", got, overread, exact)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	s, err := NewHilbert(32, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	x0, y0, x1, y1 := 3, 5, 20, 27
+	exact, _ := s.RangeQuery(x0, y0, x1, y1)
+
+	prevTotal := s.N * s.N
+	for limit := 1; limit <= len(exact)+2; limit++ {
+		got, err := s.Ranges(x0, y0, x1, y1, limit)
+		if err != nil {
+			t.Fatalf("Ranges(%d) returned error: %s", limit, err)
+		}
+		if want := min(limit, len(exact)); len(got) != want {
+			t.Errorf("Ranges(%d) returned %d ranges want %d", limit, len(got), want)
+		}
+
+		// Allowing more ranges must never read more values.
+		total := 0
+		for _, r := range got {
+			total += r.Len()
+		}
+		if total > prevTotal {
+			t.Errorf("Ranges(%d) covers %d values, more than %d for fewer ranges", limit, total, prevTotal)
+		}
+		prevTotal = total
+
+		j := 0
+		for _, e := range exact {
+			for j < len(got) && got[j].Hi < e.Lo {
+				j++
+			}
+			if j == len(got) || got[j].Lo > e.Lo || got[j].Hi < e.Hi {
+				t.Errorf("Ranges(%d) = %v does not cover %v", limit, got, e)
+				break
+			}
+		}
+	}
+
+	got, _ := s.Ranges(x0, y0, x1, y1, 1)
+	if want := []Range{{exact[0].Lo, exact[len(exact)-1].Hi}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Ranges(1) = %v want %v", got, want)
+	}
+
+	if _, err := s.Ranges(x0, y0, x1, y1, 0); err != ErrNotPositive {
+		t.Errorf("Ranges(0) = %v want %v", err, ErrNotPositive)
+	}
+	if _, err := s.Ranges(0, 0, 32, 0, 4); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("Ranges(0, 0, 32, 0) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestRangeMethods(t *testing.T) {
+	r := Range{3, 7}
+
+	if got := r.Len(); got != 5 {
+		t.Errorf("%v.Len() = %d want 5", r, got)
+	}
+
+	containsTestCases := []struct {
+		t    int
+		want bool
+	}{
+		{2, false},
+		{3, true},
+		{5, true},
+		{7, true},
+		{8, false},
+	}
+	for _, tc := range containsTestCases {
+		if got := r.Contains(tc.t); got != tc.want {
+			t.Errorf("%v.Contains(%d) = %t want %t", r, tc.t, got, tc.want)
+		}
+	}
+
+	pairTestCases := []struct {
+		o           Range
+		wantOverlap bool
+		wantMerge   Range
+		wantOk      bool
+	}{
+		{Range{0, 1}, false, Range{3, 7}, false},
+		{Range{0, 2}, false, Range{0, 7}, true},
+		{Range{0, 3}, true, Range{0, 7}, true},
+		{Range{4, 5}, true, Range{3, 7}, true},
+		{Range{1, 9}, true, Range{1, 9}, true},
+		{Range{7, 9}, true, Range{3, 9}, true},
+		{Range{8, 9}, false, Range{3, 9}, true},
+		{Range{9, 9}, false, Range{3, 7}, false},
+	}
+	for _, tc := range pairTestCases {
+		if got := r.Overlaps(tc.o); got != tc.wantOverlap {
+			t.Errorf("%v.Overlaps(%v) = %t want %t", r, tc.o, got, tc.wantOverlap)
+		}
+		if got := tc.o.Overlaps(r); got != tc.wantOverlap {
+			t.Errorf("%v.Overlaps(%v) = %t want %t", tc.o, r, got, tc.wantOverlap)
+		}
+		if got, ok := r.Merge(tc.o); got != tc.wantMerge || ok != tc.wantOk {
+			t.Errorf("%v.Merge(%v) = (%v, %t) want (%v, %t)", r, tc.o, got, ok, tc.wantMerge, tc.wantOk)
+		}
+	}
+}
+
+func TestMergeRanges(t *testing.T) {
+	testCases := []struct {
+		in, want []Range
+	}{
+		{nil, nil},
+		{[]Range{{1, 2}}, []Range{{1, 2}}},
+		{[]Range{{5, 6}, {1, 2}}, []Range{{1, 2}, {5, 6}}},
+		{[]Range{{3, 4}, {1, 2}}, []Range{{1, 4}}},
+		{[]Range{{1, 10}, {2, 3}, {12, 14}, {11, 11}}, []Range{{1, 14}}},
+		{[]Range{{8, 9}, {0, 0}, {2, 5}, {4, 6}}, []Range{{0, 0}, {2, 6}, {8, 9}}},
+	}
+
+	for _, tc := range testCases {
+		in := append([]Range(nil), tc.in...)
+		got := MergeRanges(in)
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("MergeRanges(%v) = %v want %v", tc.in, got, tc.want)
+		}
+		if !reflect.DeepEqual(in, tc.in) {
+			t.Errorf("MergeRanges(%v) modified its input to %v", tc.in, in)
+		}
+	}
+}
+
+func TestRangeDiff(t *testing.T) {
+	testCases := []struct {
+		before, after []Range
+		entered, left []Range
+	}{
+		{nil, nil, nil, nil},
+		{nil, []Range{{1, 3}}, []Range{{1, 3}}, nil},
+		{[]Range{{1, 3}}, nil, nil, []Range{{1, 3}}},
+		{[]Range{{1, 3}}, []Range{{1, 3}}, nil, nil},
+		{[]Range{{0, 10}}, []Range{{5, 15}}, []Range{{11, 15}}, []Range{{0, 4}}},
+		{[]Range{{0, 10}}, []Range{{2, 3}, {6, 7}}, nil, []Range{{0, 1}, {4, 5}, {8, 10}}},
+		{[]Range{{6, 9}, {0, 2}, {1, 4}}, []Range{{3, 7}, {20, 21}}, []Range{{5, 5}, {20, 21}}, []Range{{0, 2}, {8, 9}}},
+	}
+
+	for _, tc := range testCases {
+		entered, left := RangeDiff(tc.before, tc.after)
+		if !reflect.DeepEqual(entered, tc.entered) || !reflect.DeepEqual(left, tc.left) {
+			t.Errorf("RangeDiff(%v, %v) = (%v, %v) want (%v, %v)", tc.before, tc.after, entered, left, tc.entered, tc.left)
+		}
+	}
+}
+
+func TestRangeDiffRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	random := func() []Range {
+		ranges := make([]Range, r.Intn(5))
+		for i := range ranges {
+			lo := r.Intn(100)
+			ranges[i] = Range{lo, lo + r.Intn(20)}
+		}
+		return ranges
+	}
+	covered := func(ranges []Range) map[int]bool {
+		m := make(map[int]bool)
+		for _, rg := range ranges {
+			for v := rg.Lo; v <= rg.Hi; v++ {
+				m[v] = true
+			}
+		}
+		return m
+	}
+
+	for i := 0; i < 100; i++ {
+		before, after := random(), random()
+		entered, left := RangeDiff(before, after)
+		b, a, e, l := covered(before), covered(after), covered(entered), covered(left)
+		for v := 0; v < 120; v++ {
+			if e[v] != (a[v] && !b[v]) || l[v] != (b[v] && !a[v]) {
+				t.Errorf("RangeDiff(%v, %v) = (%v, %v) wrong for %d", before, after, entered, left, v)
+				break
+			}
+		}
+		if !reflect.DeepEqual(entered, MergeRanges(entered)) || !reflect.DeepEqual(left, MergeRanges(left)) {
+			t.Errorf("RangeDiff(%v, %v) = (%v, %v) is not merged", before, after, entered, left)
+		}
+	}
+}
+
+func TestRectPoints(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	s, err := NewHilbert(32, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for i := 0; i < 50; i++ {
+		x0, y0 := r.Intn(s.N), r.Intn(s.N)
+		x1, y1 := x0+r.Intn(s.N-x0), y0+r.Intn(s.N-y0)
+
+		var want [][2]int
+		for _, rng := range bruteForceRanges(s, x0, y0, x1, y1) {
+			for d := rng.Lo; d <= rng.Hi; d++ {
+				x, y, _ := s.Map(d)
+				want = append(want, [2]int{x, y})
+			}
+		}
+
+		var got [][2]int
+		for p := range s.RectPoints(x0, y0, x1, y1) {
+			got = append(got, p)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("RectPoints(%d, %d, %d, %d) = %v want %v", x0, y0, x1, y1, got, want)
+		}
+	}
+}
+
+func TestRectPointsStop(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var got [][2]int
+	for p := range s.RectPoints(0, 0, 15, 0) {
+		got = append(got, p)
+		if len(got) == 3 {
+			break
+		}
+	}
+	if want := [][2]int{{0, 0}, {1, 0}, {2, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RectPoints(0, 0, 15, 0) first 3 = %v want %v", got, want)
+	}
+
+	for p := range s.RectPoints(0, 0, 16, 0) {
+		t.Errorf("RectPoints(0, 0, 16, 0) yielded %v, want nothing", p)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	testCases := []struct {
+		n, approxSize, wantLen int
+	}{
+		{1, 100, 1},
+		{16, -1, 1},
+		{16, 0, 1},
+		{16, 1, 1},
+		{16, 2, 4}, // Exactly between 1 and 4, rounds up.
+		{16, 5, 4},
+		{16, 9, 16}, // Nearer to 16 than 4 by ratio, though not by difference.
+		{16, 40, 64},
+		{16, 64, 64},
+		{16, 1000, 256},
+		{32, 1000, 1024},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbert(tc.n, false)
+		if err != nil {
+			t.Fatalf("NewHilbert(%d) failed: %s", tc.n, err)
+		}
+
+		next := 0
+		for r := range s.Chunks(tc.approxSize) {
+			if r.Lo != next || r.Len() != tc.wantLen {
+				t.Errorf("NewHilbert(%d).Chunks(%d) yielded %v want {%d %d}", tc.n, tc.approxSize, r, next, next+tc.wantLen-1)
+				break
+			}
+			next = r.Hi + 1
+
+			// Each chunk must be a square.
+			var minX, minY, maxX, maxY = s.N, s.N, -1, -1
+			for d := r.Lo; d <= r.Hi; d++ {
+				x, y, _ := s.Map(d)
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+			if w, h := maxX-minX+1, maxY-minY+1; w*h != r.Len() || w != h {
+				t.Errorf("NewHilbert(%d).Chunks(%d) yielded %v covering a %d by %d area", tc.n, tc.approxSize, r, w, h)
+			}
+		}
+		if next != tc.n*tc.n {
+			t.Errorf("NewHilbert(%d).Chunks(%d) covered [0, %d) want [0, %d)", tc.n, tc.approxSize, next, tc.n*tc.n)
+		}
+	}
+}
+
+func TestBoundaryPoints(t *testing.T) {
+	for _, n := range []int{1, 2, 4, 16} {
+		s, err := NewHilbert(n, false)
+		if err != nil {
+			t.Fatalf("NewHilbert(%d) failed: %s", n, err)
+		}
+
+		var want [][2]int
+		for d := 0; d < n*n; d++ {
+			x, y, _ := s.Map(d)
+			if x == 0 || y == 0 || x == n-1 || y == n-1 {
+				want = append(want, [2]int{x, y})
+			}
+		}
+
+		var got [][2]int
+		for p := range s.BoundaryPoints() {
+			got = append(got, p)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("NewHilbert(%d).BoundaryPoints() = %v want %v", n, got, want)
+		}
+	}
+}
+
+func TestAroundPoint(t *testing.T) {
+	s, err := NewHilbert(8, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, p := range [][2]int{{0, 0}, {3, 5}, {7, 0}} {
+		focus, _ := s.MapInverse(p[0], p[1])
+
+		seen := make(map[[2]int]bool)
+		last := 0
+		for c := range s.AroundPoint(p[0], p[1]) {
+			if seen[c] {
+				t.Errorf("AroundPoint(%d, %d) yielded %v twice", p[0], p[1], c)
+			}
+			seen[c] = true
+
+			d, _ := s.MapInverse(c[0], c[1])
+			dist := d - focus
+			if dist < 0 {
+				dist = -dist
+			}
+			if dist < last {
+				t.Errorf("AroundPoint(%d, %d) yielded %v at distance %d after distance %d", p[0], p[1], c, dist, last)
+			}
+			last = dist
+		}
+		if len(seen) != s.N*s.N {
+			t.Errorf("AroundPoint(%d, %d) yielded %d cells want %d", p[0], p[1], len(seen), s.N*s.N)
+		}
+	}
+}
+
+func TestAroundPointStop(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var got [][2]int
+	for c := range s.AroundPoint(1, 1) {
+		got = append(got, c)
+		if len(got) == 3 {
+			break
+		}
+	}
+	// (1,1) is t=2, so the first cells are t=2, t=1 and t=3.
+	if want := [][2]int{{1, 1}, {1, 0}, {0, 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AroundPoint(1, 1) first 3 = %v want %v", got, want)
+	}
+
+	for range s.AroundPoint(4, 0) {
+		t.Errorf("AroundPoint(4, 0) yielded a cell, want none")
+	}
+}
+
+func TestExpandingRings(t *testing.T) {
+	s, err := NewHilbert(8, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, focus := range [][2]int{{0, 0}, {3, 4}, {7, 1}} {
+		r := 0
+		for ranges := range s.ExpandingRings(focus[0], focus[1]) {
+			r++
+
+			var want []Range
+			for d := 0; d < s.N*s.N; d++ {
+				x, y, _ := s.Map(d)
+				if max(x-focus[0], focus[0]-x, y-focus[1], focus[1]-y) != r {
+					continue
+				}
+				if n := len(want); n > 0 && want[n-1].Hi+1 == d {
+					want[n-1].Hi = d
+				} else {
+					want = append(want, Range{d, d})
+				}
+			}
+			if !reflect.DeepEqual(ranges, want) {
+				t.Errorf("ExpandingRings(%d, %d) ring %d = %v want %v", focus[0], focus[1], r, ranges, want)
+			}
+		}
+
+		wantRings := max(focus[0], focus[1], s.N-1-focus[0], s.N-1-focus[1])
+		if r != wantRings {
+			t.Errorf("ExpandingRings(%d, %d) yielded %d rings want %d", focus[0], focus[1], r, wantRings)
+		}
+	}
+
+	for range s.ExpandingRings(8, 0) {
+		t.Errorf("ExpandingRings(8, 0) yielded a ring, want nothing")
+	}
+}
+
+func TestIndicesWithinRadius(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range [][3]int{{0, 0, 0}, {5, 7, 1}, {5, 7, 3}, {0, 15, 6}, {8, 8, 40}, {3, 12, 5}} {
+		x, y, r := tc[0], tc[1], tc[2]
+		got, err := s.IndicesWithinRadius(x, y, r)
+		if err != nil {
+			t.Errorf("IndicesWithinRadius(%d, %d, %d) failed: %s", x, y, r, err)
+			continue
+		}
+
+		var want []Range
+		for d := 0; d < s.N*s.N; d++ {
+			cx, cy, _ := s.Map(d)
+			if (cx-x)*(cx-x)+(cy-y)*(cy-y) <= r*r {
+				want = append(want, Range{d, d})
+			}
+		}
+		if want = MergeRanges(want); !reflect.DeepEqual(got, want) {
+			t.Errorf("IndicesWithinRadius(%d, %d, %d) = %v want %v", x, y, r, got, want)
+		}
+	}
+
+	// Radii reaching every cell cover the whole space, even where r*r overflows.
+	for _, r := range []int{22, math.MaxInt >> 1, math.MaxInt} {
+		want := []Range{{0, s.N*s.N - 1}}
+		if got, err := s.IndicesWithinRadius(0, 0, r); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("IndicesWithinRadius(0, 0, %d) = (%v, %v) want (%v, nil)", r, got, err, want)
+		}
+	}
+	if got, _ := s.IndicesWithinRadius(0, 0, 21); len(got) == 1 && got[0] == (Range{0, s.N*s.N - 1}) {
+		t.Errorf("IndicesWithinRadius(0, 0, 21) = %v, which includes (15,15)", got)
+	}
+
+	for _, tc := range [][3]int{{-1, 0, 1}, {0, 16, 1}, {0, 0, -1}} {
+		if _, err := s.IndicesWithinRadius(tc[0], tc[1], tc[2]); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("IndicesWithinRadius(%d, %d, %d) = %v want %v", tc[0], tc[1], tc[2], err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestCountInRect(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		x0, y0, x1, y1 int
+		want           int
+		err            error
+	}{
+		{0, 0, 0, 0, 1, nil},
+		{2, 3, 5, 9, 28, nil},
+		{0, 0, 15, 15, 256, nil},
+		{-5, -5, 100, 100, 256, nil}, // Clipped to the whole space
+		{-5, 2, 1, 2, 2, nil},        // Clipped on the left
+		{14, 14, 20, 20, 4, nil},     // Clipped on the bottom right
+		{16, 0, 20, 5, 0, nil},       // Entirely outside
+		{-3, -3, -1, -1, 0, nil},     // Entirely outside
+		{5, 0, 4, 0, 0, ErrOutOfRange},
+		{0, 5, 0, 4, 0, ErrOutOfRange},
+	}
+
+	for _, tc := range testCases {
+		got, err := s.CountInRect(tc.x0, tc.y0, tc.x1, tc.y1)
+		if got != tc.want || !errors.Is(err, tc.err) {
+			t.Errorf("CountInRect(%d, %d, %d, %d) = (%d, %v) want (%d, %v)", tc.x0, tc.y0, tc.x1, tc.y1, got, err, tc.want, tc.err)
+		}
+	}
+}
+
+func TestRangeCentroid(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		lo, hi int
+		mode   CentroidMode
+		wantX  int
+		wantY  int
+	}{
+		{0, 0, CentroidMiddle, 0, 0},
+		{0, 3, CentroidMiddle, 1, 0},   // Middle is t=1
+		{0, 15, CentroidMiddle, 1, 2},  // Middle is t=7
+		{0, 3, CentroidSpatial, 0, 0},  // All four cells are equally near (0.5, 0.5)
+		{0, 15, CentroidSpatial, 1, 1}, // Mean is (1.5, 1.5), and (1,1) is the earliest nearest
+		{4, 7, CentroidSpatial, 0, 2},  // The quadrant at (0,2)
+	}
+
+	for _, tc := range testCases {
+		x, y, err := s.RangeCentroid(tc.lo, tc.hi, tc.mode)
+		if err != nil || x != tc.wantX || y != tc.wantY {
+			t.Errorf("RangeCentroid(%d, %d, %d) = (%d, %d, %v) want (%d, %d, nil)", tc.lo, tc.hi, tc.mode, x, y, err, tc.wantX, tc.wantY)
+		}
+	}
+
+	for _, tc := range [][3]int{{-1, 3, 0}, {3, 2, 0}, {0, 16, 1}, {0, 3, 2}} {
+		if _, _, err := s.RangeCentroid(tc[0], tc[1], CentroidMode(tc[2])); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("RangeCentroid(%d, %d, %d) = %v want %v", tc[0], tc[1], tc[2], err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestRangesCoverRect(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	exact, _ := s.RangeQuery(3, 2, 10, 12)
+	budget, _, _ := s.RangeQueryBudget(3, 2, 10, 12, 20)
+
+	// Drop the first range, and add a range entirely outside of the rectangle.
+	x, y, _ := s.Map(exact[len(exact)-1].Hi + 1)
+	if x >= 3 && x <= 10 && y >= 2 && y <= 12 {
+		t.Fatalf("Map(%d) = (%d, %d) is inside the rectangle", exact[len(exact)-1].Hi+1, x, y)
+	}
+	broken := append(append([]Range(nil), exact[1:]...), Range{exact[len(exact)-1].Hi + 1, exact[len(exact)-1].Hi + 1})
+
+	overreadBudget := 0
+	for _, r := range budget {
+		overreadBudget += r.Len()
+	}
+	overreadBudget -= 8 * 11
+
+	testCases := []struct {
+		name           string
+		ranges         []Range
+		missing, extra int
+	}{
+		{"exact", exact, 0, 0},
+		{"exact, reversed and overlapping", append(append([]Range(nil), exact...), exact[0], Range{exact[0].Lo, exact[0].Lo}), 0, 0},
+		{"budget", budget, 0, overreadBudget},
+		{"broken", broken, exact[0].Len(), 1},
+		{"none", nil, 88, 0},
+		{"everything", []Range{{0, 255}}, 0, 256 - 88},
+	}
+
+	for _, tc := range testCases {
+		missing, extra, err := s.RangesCoverRect(tc.ranges, 3, 2, 10, 12)
+		if err != nil || missing != tc.missing || extra != tc.extra {
+			t.Errorf("RangesCoverRect(%s) = (%d, %d, %v) want (%d, %d, nil)", tc.name, missing, extra, err, tc.missing, tc.extra)
+		}
+	}
+
+	for _, ranges := range [][]Range{{{5, 4}}, {{-1, 4}}, {{250, 256}}} {
+		if _, _, err := s.RangesCoverRect(ranges, 3, 2, 10, 12); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("RangesCoverRect(%v) = %v want %v", ranges, err, ErrOutOfRange)
+		}
+	}
+	if _, _, err := s.RangesCoverRect(exact, 3, 2, 10, 16); !errors.Is(err, ErrOutOfRange) {
+		t.Errorf("RangesCoverRect(3, 2, 10, 16) = %v want %v", err, ErrOutOfRange)
+	}
+}
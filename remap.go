@@ -0,0 +1,59 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// sameDimensions returns ErrDimensionsDiffer if the two curves cover different sized spaces.
+func sameDimensions(src, dst SpaceFilling) error {
+	sw, sh := src.GetDimensions()
+	dw, dh := dst.GetDimensions()
+	if sw != dw || sh != dh {
+		return ErrDimensionsDiffer
+	}
+	return nil
+}
+
+// Remap transforms t on the src curve to the value on the dst curve for the same cell. The two
+// curves must have the same dimensions.
+func Remap(src, dst SpaceFilling, t int) (int, error) {
+	if err := sameDimensions(src, dst); err != nil {
+		return -1, err
+	}
+
+	x, y, err := src.Map(t)
+	if err != nil {
+		return -1, err
+	}
+	return dst.MapInverse(x, y)
+}
+
+// RemapSlice is like Remap, but transforms every value in ts, returning a new slice. If any value
+// fails to transform, the error is returned and no slice.
+func RemapSlice(src, dst SpaceFilling, ts []int) ([]int, error) {
+	if err := sameDimensions(src, dst); err != nil {
+		return nil, err
+	}
+
+	out := make([]int, len(ts))
+	for i, t := range ts {
+		x, y, err := src.Map(t)
+		if err != nil {
+			return nil, err
+		}
+		if out[i], err = dst.MapInverse(x, y); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
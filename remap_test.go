@@ -0,0 +1,78 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRemap(t *testing.T) {
+	h, _ := NewHilbert(4, false)
+	v, _ := NewHilbert(4, true)
+	r := rowMajor{4, 4}
+
+	for d := 0; d < 16; d++ {
+		got, err := Remap(h, r, d)
+		if err != nil {
+			t.Errorf("Remap(h, r, %d) returned error: %s", d, err)
+		}
+		x, y, _ := h.Map(d)
+		if want := y*4 + x; got != want {
+			t.Errorf("Remap(h, r, %d) = %d want %d", d, got, want)
+		}
+
+		back, _ := Remap(v, h, d)
+		if there, _ := Remap(h, v, back); there != d {
+			t.Errorf("Remap(h, v, Remap(v, h, %d)) = %d want %d", d, there, d)
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		src     SpaceFilling
+		dst     SpaceFilling
+		t       int
+		wantErr error
+	}{
+		{"different dimensions", h, rowMajor{4, 5}, 0, ErrDimensionsDiffer},
+		{"out of range", h, v, 16, ErrOutOfRange},
+	}
+	for _, tc := range testCases {
+		if _, err := Remap(tc.src, tc.dst, tc.t); err != tc.wantErr {
+			t.Errorf("Remap(%s) = %q want %q", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRemapSlice(t *testing.T) {
+	h, _ := NewHilbert(4, false)
+	r := rowMajor{4, 4}
+
+	got, err := RemapSlice(h, r, []int{0, 1, 2, 15})
+	if err != nil {
+		t.Errorf("RemapSlice() returned error: %s", err)
+	}
+	if want := []int{0, 1, 5, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RemapSlice() = %v want %v", got, want)
+	}
+
+	if got, err := RemapSlice(h, r, []int{0, 16}); got != nil || err != ErrOutOfRange {
+		t.Errorf("RemapSlice() = (%v, %q) want (nil, %q)", got, err, ErrOutOfRange)
+	}
+	if got, err := RemapSlice(h, rowMajor{2, 8}, []int{0}); got != nil || err != ErrDimensionsDiffer {
+		t.Errorf("RemapSlice() = (%v, %q) want (nil, %q)", got, err, ErrDimensionsDiffer)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "image"
+
+// RTreeNode is a node of a packed R-tree built by PackRTree. Leaves hold the indices of the
+// rectangles they contain in Items, and other nodes hold their children in Children.
+type RTreeNode struct {
+	Bounds   image.Rectangle // The union of the bounds of everything below the node
+	Children []*RTreeNode
+	Items    []int
+}
+
+// IsLeaf returns true if the node holds rectangles rather than other nodes.
+func (n *RTreeNode) IsLeaf() bool {
+	return n.Children == nil
+}
+
+// PackLeaves groups rects into the leaves of a Hilbert packed R-tree, by sorting them along a
+// Hilbert curve by their centers, as with SortFunc, and filling each leaf with the next fanout
+// rectangles in turn. Every leaf is full except possibly the last, and leaves hold indices into
+// rects, so rects is not reordered. Rectangles should be canonical, but may be empty, so points
+// can be packed as zero sized rectangles. ErrNotPositive is returned if fanout is not positive.
+func PackLeaves(rects []image.Rectangle, fanout int) ([]*RTreeNode, error) {
+	if fanout <= 0 {
+		return nil, ErrNotPositive
+	}
+
+	order := make([]int, len(rects))
+	for i := range order {
+		order[i] = i
+	}
+	SortFunc(order, func(i int) image.Point {
+		r := rects[i]
+		return image.Pt(r.Min.X+(r.Max.X-r.Min.X)/2, r.Min.Y+(r.Max.Y-r.Min.Y)/2)
+	})
+
+	leaves := make([]*RTreeNode, 0, (len(rects)+fanout-1)/fanout)
+	for lo := 0; lo < len(order); lo += fanout {
+		items := order[lo:min(lo+fanout, len(order)):min(lo+fanout, len(order))]
+		leaf := &RTreeNode{Bounds: rects[items[0]], Items: items}
+		for _, i := range items[1:] {
+			leaf.Bounds = union(leaf.Bounds, rects[i])
+		}
+		leaves = append(leaves, leaf)
+	}
+	return leaves, nil
+}
+
+// PackRTree builds a Hilbert packed R-tree over rects, with at most fanout children in each node,
+// and returns its root. The leaves are made by PackLeaves, and each level above groups the next
+// fanout nodes of the level below, which are already in Hilbert order, until a single root
+// remains. nil is returned if rects is empty, and ErrOutOfRange if fanout is less than 2, as the
+// tree would never narrow to a single root.
+func PackRTree(rects []image.Rectangle, fanout int) (*RTreeNode, error) {
+	if fanout < 2 {
+		return nil, ErrOutOfRange
+	}
+	level, _ := PackLeaves(rects, fanout)
+	if len(level) == 0 {
+		return nil, nil
+	}
+
+	for len(level) > 1 {
+		parents := make([]*RTreeNode, 0, (len(level)+fanout-1)/fanout)
+		for lo := 0; lo < len(level); lo += fanout {
+			children := level[lo:min(lo+fanout, len(level)):min(lo+fanout, len(level))]
+			parent := &RTreeNode{Bounds: children[0].Bounds, Children: children}
+			for _, c := range children[1:] {
+				parent.Bounds = union(parent.Bounds, c.Bounds)
+			}
+			parents = append(parents, parent)
+		}
+		level = parents
+	}
+	return level[0], nil
+}
+
+// union returns the smallest rectangle containing both a and b. Unlike image.Rectangle.Union,
+// empty rectangles are not ignored, so the union of two points is the box between them.
+func union(a, b image.Rectangle) image.Rectangle {
+	return image.Rect(min(a.Min.X, b.Min.X), min(a.Min.Y, b.Min.Y), max(a.Max.X, b.Max.X), max(a.Max.Y, b.Max.Y))
+}
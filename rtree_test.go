@@ -0,0 +1,125 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"image"
+	"testing"
+)
+
+func TestPackLeaves(t *testing.T) {
+	s, _ := NewHilbert(4, false)
+
+	// A 4x4 grid of unit squares, each indexed by y*4+x.
+	var rects []image.Rectangle
+	for y := 0; y < 4; y++ {
+		for x := 0; x < 4; x++ {
+			rects = append(rects, image.Rect(x, y, x+1, y+1))
+		}
+	}
+
+	leaves, err := PackLeaves(rects, 4)
+	if err != nil {
+		t.Fatalf("PackLeaves() returned error: %s", err)
+	}
+	if len(leaves) != 4 {
+		t.Fatalf("PackLeaves() returned %d leaves want 4", len(leaves))
+	}
+
+	// Each leaf is the next four cells along the curve, which make up a quadrant.
+	for i, leaf := range leaves {
+		for j, item := range leaf.Items {
+			x, y, _ := s.Map(4*i + j)
+			if item != y*4+x {
+				t.Errorf("PackLeaves() leaf %d item %d = %d want %d", i, j, item, y*4+x)
+			}
+		}
+		x, y, _ := s.Map(4 * i)
+		want := image.Rect(x&^1, y&^1, x&^1+2, y&^1+2)
+		if leaf.Bounds != want || !leaf.IsLeaf() {
+			t.Errorf("PackLeaves() leaf %d bounds = %v want %v", i, leaf.Bounds, want)
+		}
+	}
+
+	if _, err := PackLeaves(rects, 0); err != ErrNotPositive {
+		t.Errorf("PackLeaves(0) = %v want %v", err, ErrNotPositive)
+	}
+	if leaves, err := PackLeaves(nil, 4); len(leaves) != 0 || err != nil {
+		t.Errorf("PackLeaves(nil) = (%v, %v) want ([], nil)", leaves, err)
+	}
+}
+
+func TestPackRTree(t *testing.T) {
+	// Points are packed as zero sized rectangles.
+	var rects []image.Rectangle
+	for i := 0; i < 17; i++ {
+		p := image.Pt(i*7%13, i*5%11)
+		rects = append(rects, image.Rectangle{p, p})
+	}
+
+	root, err := PackRTree(rects, 4)
+	if err != nil {
+		t.Fatalf("PackRTree() returned error: %s", err)
+	}
+
+	// 17 points make 5 leaves, then 2 nodes, and then the root.
+	seen := make([]bool, len(rects))
+	var check func(n *RTreeNode, depth int)
+	check = func(n *RTreeNode, depth int) {
+		if len(n.Children)+len(n.Items) > 4 {
+			t.Errorf("PackRTree() node %v has more than 4 entries", n.Bounds)
+		}
+		if n.IsLeaf() {
+			if depth != 2 {
+				t.Errorf("PackRTree() leaf %v at depth %d want 2", n.Bounds, depth)
+			}
+			for _, i := range n.Items {
+				if seen[i] || !rects[i].In(n.Bounds) {
+					t.Errorf("PackRTree() item %d %v is repeated or not within its leaf %v", i, rects[i], n.Bounds)
+				}
+				seen[i] = true
+			}
+			return
+		}
+		for _, c := range n.Children {
+			if !c.Bounds.In(n.Bounds) {
+				t.Errorf("PackRTree() node %v is not within its parent %v", c.Bounds, n.Bounds)
+			}
+			check(c, depth+1)
+		}
+	}
+	check(root, 0)
+	for i, ok := range seen {
+		if !ok {
+			t.Errorf("PackRTree() item %d is missing", i)
+		}
+	}
+	if want := image.Rect(0, 0, 12, 10); root.Bounds != want {
+		t.Errorf("PackRTree() root bounds = %v want %v", root.Bounds, want)
+	}
+}
+
+func TestPackRTreeErrors(t *testing.T) {
+	if _, err := PackRTree([]image.Rectangle{{}}, 1); err != ErrOutOfRange {
+		t.Errorf("PackRTree(1) = %v want %v", err, ErrOutOfRange)
+	}
+	if root, err := PackRTree(nil, 4); root != nil || err != nil {
+		t.Errorf("PackRTree(nil) = (%v, %v) want (nil, nil)", root, err)
+	}
+	root, _ := PackRTree([]image.Rectangle{image.Rect(1, 2, 3, 4)}, 4)
+	if !root.IsLeaf() || len(root.Items) != 1 || root.Bounds != image.Rect(1, 2, 3, 4) {
+		t.Errorf("PackRTree(one rectangle) = %+v want a single leaf", root)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// coordinate is the type of the coordinates used by AxesToTranspose and TransposeToAxes.
+type coordinate interface {
+	~int | ~uint64
+}
+
+// AxesToTranspose converts the coordinates of a point in any number of dimensions, axes, into the
+// "transpose" form of its Hilbert index, in place, using John Skilling's algorithm from
+// "Programming the Hilbert curve" (2004). bits is the number of bits in each coordinate, so the
+// space is 2^bits wide in each dimension.
+//
+// In the transpose form the index is spread across the elements, which when interleaved, most
+// significant bit first and starting with the first element, give the index. For two dimensions
+// the index of (x,y) is the index of the horizontal Hilbert curve, that is
+// NewHilbert(1<<bits, false).MapInverse(x, y).
+//
+// The elements must be non-negative and less than 2^bits, and bits must be in [1, 62] for int
+// coordinates, or [1, 64] for uint64 coordinates.
+func AxesToTranspose[T coordinate](axes []T, bits int) {
+	n := len(axes)
+	m := T(1) << uint(bits-1)
+
+	// Inverse undo
+	for q := m; q > 1; q >>= 1 {
+		p := q - 1
+		for i := 0; i < n; i++ {
+			if axes[i]&q != 0 {
+				axes[0] ^= p // Invert
+			} else {
+				t := (axes[0] ^ axes[i]) & p // Exchange
+				axes[0] ^= t
+				axes[i] ^= t
+			}
+		}
+	}
+
+	// Gray encode
+	for i := 1; i < n; i++ {
+		axes[i] ^= axes[i-1]
+	}
+	var t T
+	for q := m; q > 1; q >>= 1 {
+		if axes[n-1]&q != 0 {
+			t ^= q - 1
+		}
+	}
+	for i := 0; i < n; i++ {
+		axes[i] ^= t
+	}
+}
+
+// TransposeToAxes is the inverse of AxesToTranspose, converting the transpose form of a Hilbert
+// index into the coordinates of the point, in place.
+func TransposeToAxes[T coordinate](transpose []T, bits int) {
+	n := len(transpose)
+	if n == 0 {
+		return
+	}
+	m := T(2) << uint(bits-1)
+
+	// Gray decode by H ^ (H/2)
+	t := transpose[n-1] >> 1
+	for i := n - 1; i > 0; i-- {
+		transpose[i] ^= transpose[i-1]
+	}
+	transpose[0] ^= t
+
+	// Undo excess work
+	for q := T(2); q != m; q <<= 1 {
+		p := q - 1
+		for i := n - 1; i >= 0; i-- {
+			if transpose[i]&q != 0 {
+				transpose[0] ^= p // Invert
+			} else {
+				t := (transpose[0] ^ transpose[i]) & p // Exchange
+				transpose[0] ^= t
+				transpose[i] ^= t
+			}
+		}
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+// interleave returns the index held in the transpose form, see AxesToTranspose.
+func interleave(transpose []int, bits int) int {
+	t := 0
+	for i := bits - 1; i >= 0; i-- {
+		for _, v := range transpose {
+			t = t<<1 | v>>uint(i)&1
+		}
+	}
+	return t
+}
+
+// deinterleave is the inverse of interleave.
+func deinterleave(t, n, bits int) []int {
+	transpose := make([]int, n)
+	for i := 0; i < bits*n; i++ {
+		transpose[n-1-i%n] |= (t >> uint(i) & 1) << uint(i/n)
+	}
+	return transpose
+}
+
+func TestAxesToTransposeMatchesHilbert(t *testing.T) {
+	for bits := 1; bits <= 6; bits++ {
+		s, err := NewHilbert(1<<uint(bits), false)
+		if err != nil {
+			t.Fatalf("Failed to create hibert space: %s", err)
+		}
+
+		for d := 0; d < s.N*s.N; d++ {
+			x, y, _ := s.Map(d)
+
+			axes := []int{x, y}
+			AxesToTranspose(axes, bits)
+			if got := interleave(axes, bits); got != d {
+				t.Errorf("AxesToTranspose([%d, %d], %d) = %d want %d", x, y, bits, got, d)
+			}
+
+			transpose := deinterleave(d, 2, bits)
+			TransposeToAxes(transpose, bits)
+			if want := []int{x, y}; !reflect.DeepEqual(transpose, want) {
+				t.Errorf("TransposeToAxes(%d, %d) = %v want %v", d, bits, transpose, want)
+			}
+		}
+	}
+}
+
+func TestTransposeRoundTrip(t *testing.T) {
+	// A 3D curve, 8 cells wide, built from the primitives must visit every cell once, with each
+	// step moving to an adjacent cell.
+	const bits, n = 3, 3
+	seen := make(map[[n]int]bool)
+	var last []int
+	for d := 0; d < 1<<uint(bits*n); d++ {
+		axes := deinterleave(d, n, bits)
+		TransposeToAxes(axes, bits)
+
+		p := [n]int{axes[0], axes[1], axes[2]}
+		if seen[p] {
+			t.Errorf("TransposeToAxes(%d) = %v visited twice", d, p)
+		}
+		seen[p] = true
+
+		if last != nil {
+			dist := 0
+			for i := range axes {
+				if axes[i] > last[i] {
+					dist += axes[i] - last[i]
+				} else {
+					dist += last[i] - axes[i]
+				}
+			}
+			if dist != 1 {
+				t.Errorf("TransposeToAxes(%d) = %v is not adjacent to %v", d, axes, last)
+			}
+		}
+		last = append([]int(nil), axes...)
+
+		transpose := append([]int(nil), axes...)
+		AxesToTranspose(transpose, bits)
+		if got := interleave(transpose, bits); got != d {
+			t.Errorf("AxesToTranspose(%v) = %d want %d", axes, got, d)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Snap returns the cell nearest to the location (fx,fy), and its value t on the curve. The
+// location is in grid space, where the center of cell (x,y) is at exactly (x,y), and halves are
+// rounded up, so 0.5 snaps to 1. If the nearest cell is outside of the space, Snap returns
+// ErrOutOfRange, unless clamp is true, in which case the nearest cell on the edge is used.
+func (s *Hilbert) Snap(fx, fy float64, clamp bool) (x, y, t int, err error) {
+	if math.IsNaN(fx) || math.IsNaN(fy) {
+		return -1, -1, -1, ErrNotFinite
+	}
+
+	if x, err = s.snap(fx, clamp); err != nil {
+		return -1, -1, -1, err
+	}
+	if y, err = s.snap(fy, clamp); err != nil {
+		return -1, -1, -1, err
+	}
+
+	t, err = s.MapInverse(x, y)
+	return x, y, t, err
+}
+
+// snap rounds f half up to the nearest coordinate within [0, N-1].
+func (s *Hilbert) snap(f float64, clamp bool) (int, error) {
+	f = math.Floor(f + 0.5)
+	if f < 0 || f > float64(s.N-1) {
+		if !clamp {
+			return -1, ErrOutOfRange
+		}
+		f = math.Max(0, math.Min(f, float64(s.N-1)))
+	}
+	return int(f), nil
+}
+
+// BinBatch returns the value on the curve of the cell containing each position (fxs[i],fys[i]),
+// where the positions are in the world box with corners (minX,minY) and (maxX,maxY), which is
+// divided into N by N equally sized cells. Positions on the maximum edges of the box are in the
+// last row or column of cells.
+//
+// Positions outside of the box are handled with the curve's BoundsPolicy. With the default,
+// BoundsError, they are flagged by setting their value to -1, and the remaining positions are
+// still binned. Positions which are not finite are always flagged.
+//
+// ErrInvalidLength is returned if fxs and fys are different lengths, ErrNotFinite if the box is
+// not finite, and ErrOutOfRange if it is empty.
+func (s *Hilbert) BinBatch(fxs, fys []float64, minX, minY, maxX, maxY float64) ([]int, error) {
+	if len(fxs) != len(fys) {
+		return nil, ErrInvalidLength
+	}
+	q, err := NewQuantizer(s, minX, minY, maxX, maxY)
+	if err != nil {
+		return nil, err
+	}
+
+	ts := make([]int, len(fxs))
+	for i := range fxs {
+		if ts[i], err = q.Index(fxs[i], fys[i]); err != nil {
+			ts[i] = -1
+		}
+	}
+	return ts, nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSnap(t *testing.T) {
+	testCases := []struct {
+		fx, fy  float64
+		clamp   bool
+		wantX   int
+		wantY   int
+		wantT   int
+		wantErr error
+	}{
+		{0, 0, false, 0, 0, 0, nil},
+		{4.2, 11.8, false, 4, 12, 96, nil},
+		{3.5, 11.5, false, 4, 12, 96, nil},
+		{4.49, 12.49, false, 4, 12, 96, nil},
+		{-0.5, -0.5, false, 0, 0, 0, nil},
+		{14.5, -0.2, false, 15, 0, 255, nil},
+		{15.5, 0, false, -1, -1, -1, ErrOutOfRange},
+		{0, -0.51, false, -1, -1, -1, ErrOutOfRange},
+		{15.5, 0, true, 15, 0, 255, nil},
+		{1e300, -1e300, true, 15, 0, 255, nil},
+		{math.Inf(-1), math.Inf(-1), true, 0, 0, 0, nil},
+		{math.Inf(1), 0, false, -1, -1, -1, ErrOutOfRange},
+		{math.NaN(), 0, true, -1, -1, -1, ErrNotFinite},
+		{0, math.NaN(), false, -1, -1, -1, ErrNotFinite},
+	}
+
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	for _, tc := range testCases {
+		x, y, d, err := s.Snap(tc.fx, tc.fy, tc.clamp)
+		if x != tc.wantX || y != tc.wantY || d != tc.wantT || err != tc.wantErr {
+			t.Errorf("Snap(%g, %g, %t) = (%d, %d, %d, %v) want (%d, %d, %d, %v)",
+				tc.fx, tc.fy, tc.clamp, x, y, d, err, tc.wantX, tc.wantY, tc.wantT, tc.wantErr)
+		}
+	}
+}
+
+func TestBinBatch(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+	clamped, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsClamp))
+	wrapped, _ := NewHilbert(4, false, WithBoundsPolicy(BoundsWrap))
+
+	// World box is [-1, 1] by [10, 30], so cells are 0.5 wide and 5 high.
+	fxs := []float64{-1, -0.6, 0.99, 1, -1.2, 1.7, 0, math.NaN()}
+	fys := []float64{10, 16, 29, 30, 10, 30, math.Inf(1), 10}
+
+	cell := func(s *Hilbert, x, y int) int {
+		t, _ := s.MapInverse(x, y)
+		return t
+	}
+	testCases := []struct {
+		name string
+		s    *Hilbert
+		want []int
+	}{
+		{"error", s, []int{cell(s, 0, 0), cell(s, 0, 1), cell(s, 3, 3), cell(s, 3, 3), -1, -1, -1, -1}},
+		{"clamp", clamped, []int{cell(s, 0, 0), cell(s, 0, 1), cell(s, 3, 3), cell(s, 3, 3), cell(s, 0, 0), cell(s, 3, 3), -1, -1}},
+		{"wrap", wrapped, []int{cell(s, 0, 0), cell(s, 0, 1), cell(s, 3, 3), cell(s, 3, 3), cell(s, 3, 0), cell(s, 1, 3), -1, -1}},
+	}
+
+	for _, tc := range testCases {
+		got, err := tc.s.BinBatch(fxs, fys, -1, 10, 1, 30)
+		if err != nil {
+			t.Errorf("BinBatch(%s) failed: %s", tc.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("BinBatch(%s) = %v want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBinBatchErrors(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		fxs, fys               []float64
+		minX, minY, maxX, maxY float64
+		want                   error
+	}{
+		{[]float64{1}, nil, 0, 0, 1, 1, ErrInvalidLength},
+		{nil, nil, math.NaN(), 0, 1, 1, ErrNotFinite},
+		{nil, nil, 0, 0, math.Inf(1), 1, ErrNotFinite},
+		{nil, nil, 0, 0, 0, 1, ErrOutOfRange},
+		{nil, nil, 0, 1, 1, 0, ErrOutOfRange},
+		{nil, nil, 0, 0, 1, 1, nil},
+	}
+
+	for _, tc := range testCases {
+		if _, err := s.BinBatch(tc.fxs, tc.fys, tc.minX, tc.minY, tc.maxX, tc.maxY); err != tc.want {
+			t.Errorf("BinBatch(%v, %v, %g, %g, %g, %g) = %v want %v", tc.fxs, tc.fys, tc.minX, tc.minY, tc.maxX, tc.maxY, err, tc.want)
+		}
+	}
+}
+
+func BenchmarkBinBatch(b *testing.B) {
+	s, _ := NewHilbert(1024, false)
+	r := rand.New(rand.NewSource(1))
+	fxs, fys := make([]float64, 10000), make([]float64, 10000)
+	for i := range fxs {
+		fxs[i], fys[i] = r.Float64(), r.Float64()
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.BinBatch(fxs, fys, 0, 0, 1, 1)
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"image"
+	"math/bits"
+	"sort"
+)
+
+// Sort orders points along a Hilbert curve covering their bounding box, as done before bulk
+// loading a Hilbert R-tree, or as a heuristic for the travelling salesman problem. The order of
+// the curve is chosen from the size of the box, up to MaxOrder64; boxes wider than 2^32 are
+// scaled down to fit, so points very close together may then share a cell. Points in the same
+// cell keep their original order.
+func Sort(points []image.Point) {
+	SortFunc(points, func(p image.Point) image.Point { return p })
+}
+
+// SortFunc is like Sort, but for a slice of any type, using point to get the coordinates of each
+// item. point is called once per item.
+func SortFunc[T any](items []T, point func(T) image.Point) {
+	if len(items) < 2 {
+		return
+	}
+
+	points := make([]image.Point, len(items))
+	lo, hi := point(items[0]), point(items[0]) // Corners of the bounding box, inclusive
+	for i, item := range items {
+		p := point(item)
+		points[i] = p
+		lo.X, lo.Y = min(lo.X, p.X), min(lo.Y, p.Y)
+		hi.X, hi.Y = max(hi.X, p.X), max(hi.Y, p.Y)
+	}
+
+	// Offsets from the minimum corner are computed as unsigned, so the widest possible box,
+	// from math.MinInt to math.MaxInt, does not overflow.
+	span := max(uint64(hi.X)-uint64(lo.X), uint64(hi.Y)-uint64(lo.Y))
+	order := bits.Len64(span)
+	shift := uint(max(order-MaxOrder64, 0))
+	order -= int(shift)
+
+	keys := make([]uint64, len(items))
+	for i, p := range points {
+		x := (uint64(p.X) - uint64(lo.X)) >> shift
+		y := (uint64(p.Y) - uint64(lo.Y)) >> shift
+		keys[i] = Encode2D(uint32(x), uint32(y), order)
+	}
+	sort.Stable(byKey[T]{keys, items})
+}
+
+// byKey sorts items by keys, moving both together.
+type byKey[T any] struct {
+	keys  []uint64
+	items []T
+}
+
+func (b byKey[T]) Len() int           { return len(b.keys) }
+func (b byKey[T]) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey[T]) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.items[i], b.items[j] = b.items[j], b.items[i]
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"image"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	// Points filling a 16x16 box, offset so the coordinates are negative, are sorted in the same
+	// order as the curve.
+	r := rand.New(rand.NewSource(1))
+	var points []image.Point
+	for _, i := range r.Perm(s.N * s.N) {
+		points = append(points, image.Point{i%s.N - 100, i/s.N - 50})
+	}
+	Sort(points)
+	for d, p := range points {
+		x, y, _ := s.Map(d)
+		if p != (image.Point{x - 100, y - 50}) {
+			t.Errorf("Sort() point %d = %v want (%d,%d)", d, p, x-100, y-50)
+		}
+	}
+
+	// The curve is chosen from the larger side, and equal points keep their order.
+	got := []image.Point{{3, 0}, {0, 0}, {0, 1}, {0, 0}, {1, 1}}
+	Sort(got)
+	if want := []image.Point{{0, 0}, {0, 0}, {1, 1}, {0, 1}, {3, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort() = %v want %v", got, want)
+	}
+
+	// The widest possible box must not overflow.
+	got = []image.Point{{math.MaxInt, 0}, {math.MinInt, 0}, {0, 0}}
+	Sort(got)
+	if want := []image.Point{{math.MinInt, 0}, {0, 0}, {math.MaxInt, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Sort() = %v want %v", got, want)
+	}
+
+	Sort(nil)
+	Sort([]image.Point{{1, 2}})
+}
+
+func TestSortFunc(t *testing.T) {
+	type tile struct {
+		name string
+		x, y int
+	}
+	tiles := []tile{{"d", 1, 0}, {"a", 0, 0}, {"c", 1, 1}, {"b", 0, 1}}
+	SortFunc(tiles, func(tl tile) image.Point { return image.Point{tl.x, tl.y} })
+
+	var names string
+	for _, tl := range tiles {
+		names += tl.name
+	}
+	if names != "abcd" {
+		t.Errorf("SortFunc() = %q want %q", names, "abcd")
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Sphere maps points on the unit sphere to and from a single index, in the same way as S2. The
+// sphere is projected onto the six faces of a cube, each of which is covered by a Hilbert curve
+// of the same order, and the index is the face, from 0 to 5, followed by the value on the curve of
+// that face. The faces are those facing +x, +y, +z, -x, -y and -z, in that order, and the curves
+// of the odd faces are vertical, so each curve ends next to where the curve of the following face
+// starts, and the last face ends next to the start of the first. Consecutive indices are therefore
+// always adjacent on the sphere, including across the edges of faces.
+//
+// The quadratic projection of S2 is used from the faces to the curves, so cells near the corners
+// of a face are not much smaller than those at the center.
+type Sphere struct {
+	faces [6]*Hilbert
+}
+
+// NewSphere returns a new Sphere with a curve of the given order on each face, so the sphere is
+// divided into 6 * 2^order * 2^order cells. ErrNegativeOrder is returned if order is negative, and
+// ErrTooLarge if the number of cells would not fit in an int.
+func NewSphere(order int) (*Sphere, error) {
+	if order < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if order > MaxOrder-1 {
+		return nil, ErrTooLarge
+	}
+
+	s := &Sphere{}
+	for face := range s.faces {
+		s.faces[face], _ = NewHilbert(1<<uint(order), face%2 == 1)
+	}
+	return s, nil
+}
+
+// Order returns the order of the curve on each face.
+func (s *Sphere) Order() int {
+	return s.faces[0].GetOrder()
+}
+
+// Len returns the number of cells, 6*N*N where N is 2^Order().
+func (s *Sphere) Len() int {
+	return 6 * s.faces[0].N * s.faces[0].N
+}
+
+// Index returns the index of the cell containing the point in the direction of (x,y,z), which
+// need not be of unit length. ErrNotFinite is returned if any coordinate is not finite, and
+// ErrOutOfRange if they are all zero.
+func (s *Sphere) Index(x, y, z float64) (int, error) {
+	for _, v := range []float64{x, y, z} {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return -1, ErrNotFinite
+		}
+	}
+	if x == 0 && y == 0 && z == 0 {
+		return -1, ErrOutOfRange
+	}
+
+	face, u, v := xyzToFaceUV(x, y, z)
+	curve := s.faces[face]
+	n := float64(curve.N)
+
+	// Clamp, as points on the maximum edges, or rounding, give N.
+	i := int(math.Min(math.Max(math.Floor(uvToST(u)*n), 0), n-1))
+	j := int(math.Min(math.Max(math.Floor(uvToST(v)*n), 0), n-1))
+	t, _ := curve.MapInverse(i, j)
+	return face*curve.N*curve.N + t, nil
+}
+
+// Point returns the unit vector to the center of the cell with index t. ErrOutOfRange is returned
+// if t is not within [0, Len()-1].
+func (s *Sphere) Point(t int) (x, y, z float64, err error) {
+	if t < 0 || t >= s.Len() {
+		return math.NaN(), math.NaN(), math.NaN(), ErrOutOfRange
+	}
+
+	curve := s.faces[0]
+	face, ft := t/(curve.N*curve.N), t%(curve.N*curve.N)
+	i, j, _ := s.faces[face].Map(ft)
+	n := float64(curve.N)
+	x, y, z = faceUVToXYZ(face, stToUV((float64(i)+0.5)/n), stToUV((float64(j)+0.5)/n))
+
+	l := math.Sqrt(x*x + y*y + z*z)
+	return x / l, y / l, z / l, nil
+}
+
+// IndexLatLng returns the index of the cell containing the position, given as a latitude and
+// longitude in degrees. ErrNotFinite is returned if the position is not finite, and ErrOutOfRange
+// if the latitude is not within [-90, 90] or the longitude is not within [-180, 180].
+func (s *Sphere) IndexLatLng(lat, lng float64) (int, error) {
+	if math.IsNaN(lat) || math.IsInf(lat, 0) || math.IsNaN(lng) || math.IsInf(lng, 0) {
+		return -1, ErrNotFinite
+	}
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return -1, ErrOutOfRange
+	}
+
+	phi, theta := lat*math.Pi/180, lng*math.Pi/180
+	return s.Index(math.Cos(phi)*math.Cos(theta), math.Cos(phi)*math.Sin(theta), math.Sin(phi))
+}
+
+// LatLng returns the latitude and longitude, in degrees, of the center of the cell with index t.
+// ErrOutOfRange is returned if t is not within [0, Len()-1].
+func (s *Sphere) LatLng(t int) (lat, lng float64, err error) {
+	x, y, z, err := s.Point(t)
+	if err != nil {
+		return math.NaN(), math.NaN(), err
+	}
+	return math.Asin(z) * 180 / math.Pi, math.Atan2(y, x) * 180 / math.Pi, nil
+}
+
+// xyzToFaceUV returns the face of the cube which (x,y,z) projects onto, which is the one facing
+// the axis of its largest component, and the position on that face, with u and v within [-1, 1].
+func xyzToFaceUV(x, y, z float64) (face int, u, v float64) {
+	ax, ay, az := math.Abs(x), math.Abs(y), math.Abs(z)
+	switch {
+	case ax >= ay && ax >= az:
+		face = 0
+		if x < 0 {
+			face = 3
+		}
+	case ay >= az:
+		face = 1
+		if y < 0 {
+			face = 4
+		}
+	default:
+		face = 2
+		if z < 0 {
+			face = 5
+		}
+	}
+
+	switch face {
+	case 0:
+		return face, y / x, z / x
+	case 1:
+		return face, -x / y, z / y
+	case 2:
+		return face, -x / z, -y / z
+	case 3:
+		return face, z / x, y / x
+	case 4:
+		return face, z / y, -x / y
+	}
+	return face, -y / z, -x / z
+}
+
+// faceUVToXYZ is the inverse of xyzToFaceUV, returning a point in the direction of (u,v) on the
+// face, which is not of unit length.
+func faceUVToXYZ(face int, u, v float64) (x, y, z float64) {
+	switch face {
+	case 0:
+		return 1, u, v
+	case 1:
+		return -u, 1, v
+	case 2:
+		return -u, -v, 1
+	case 3:
+		return -1, -v, -u
+	case 4:
+		return v, -1, -u
+	}
+	return v, u, -1
+}
+
+// uvToST converts a position on a face within [-1, 1] to the position within [0, 1] on its curve,
+// with the quadratic projection of S2.
+func uvToST(u float64) float64 {
+	if u >= 0 {
+		return 0.5 * math.Sqrt(1+3*u)
+	}
+	return 1 - 0.5*math.Sqrt(1-3*u)
+}
+
+// stToUV is the inverse of uvToST.
+func stToUV(s float64) float64 {
+	if s >= 0.5 {
+		return (4*s*s - 1) / 3
+	}
+	return (1 - 4*(1-s)*(1-s)) / 3
+}
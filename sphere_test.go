@@ -0,0 +1,154 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"testing"
+)
+
+// sphereCorners returns the unit vectors to the four corners of the cell with index t.
+func sphereCorners(s *Sphere, t int) [4][3]float64 {
+	n := s.faces[0].N
+	face := t / (n * n)
+	i, j, _ := s.faces[face].Map(t % (n * n))
+
+	var corners [4][3]float64
+	for k, c := range [4][2]int{{i, j}, {i + 1, j}, {i, j + 1}, {i + 1, j + 1}} {
+		x, y, z := faceUVToXYZ(face, stToUV(float64(c[0])/float64(n)), stToUV(float64(c[1])/float64(n)))
+		l := math.Sqrt(x*x + y*y + z*z)
+		corners[k] = [3]float64{x / l, y / l, z / l}
+	}
+	return corners
+}
+
+// sharedCorners returns the number of corners the two cells have in common.
+func sharedCorners(a, b [4][3]float64) int {
+	shared := 0
+	for _, p := range a {
+		for _, q := range b {
+			if math.Abs(p[0]-q[0]) < 1e-12 && math.Abs(p[1]-q[1]) < 1e-12 && math.Abs(p[2]-q[2]) < 1e-12 {
+				shared++
+			}
+		}
+	}
+	return shared
+}
+
+func TestSphere(t *testing.T) {
+	for order := 0; order <= 4; order++ {
+		s, err := NewSphere(order)
+		if err != nil {
+			t.Fatalf("NewSphere(%d) returned error: %s", order, err)
+		}
+		if got, want := s.Len(), 6<<uint(2*order); got != want {
+			t.Errorf("NewSphere(%d).Len() = %d want %d", order, got, want)
+		}
+		if got := s.Order(); got != order {
+			t.Errorf("NewSphere(%d).Order() = %d want %d", order, got, order)
+		}
+
+		for d := 0; d < s.Len(); d++ {
+			x, y, z, err := s.Point(d)
+			if err != nil {
+				t.Fatalf("Point(%d) returned error: %s", d, err)
+			}
+			if l := x*x + y*y + z*z; math.Abs(l-1) > 1e-12 {
+				t.Errorf("Point(%d) = (%f, %f, %f) is not of unit length", d, x, y, z)
+			}
+			if got, err := s.Index(x, y, z); err != nil || got != d {
+				t.Errorf("Index(Point(%d)) = (%d, %v) want (%d, nil)", d, got, err, d)
+			}
+
+			lat, lng, _ := s.LatLng(d)
+			if got, err := s.IndexLatLng(lat, lng); err != nil || got != d {
+				t.Errorf("IndexLatLng(LatLng(%d)) = (%d, %v) want (%d, nil)", d, got, err, d)
+			}
+
+			// Consecutive cells, including the last and the first, share an edge.
+			next := (d + 1) % s.Len()
+			if order > 0 && sharedCorners(sphereCorners(s, d), sphereCorners(s, next)) != 2 {
+				t.Errorf("NewSphere(%d): cells %d and %d do not share an edge", order, d, next)
+			}
+		}
+	}
+}
+
+func TestSphereIndex(t *testing.T) {
+	s, _ := NewSphere(2)
+
+	testCases := []struct {
+		x, y, z float64
+		want    int
+	}{
+		{1, 0, 0, 0*16 + 8},
+		{0, 1, 0, 1*16 + 8},
+		{0, 0, 1, 2*16 + 8},
+		{-1, 0, 0, 3*16 + 8},
+		{0, -1, 0, 4*16 + 8},
+		{0, 0, -1, 5*16 + 8},
+		{1, -1, -1, 0},
+		{5, -5, -5, 0},
+	}
+	for _, tc := range testCases {
+		if got, err := s.Index(tc.x, tc.y, tc.z); err != nil || got != tc.want {
+			t.Errorf("Index(%g, %g, %g) = (%d, %v) want (%d, nil)", tc.x, tc.y, tc.z, got, err, tc.want)
+		}
+	}
+
+	if got, err := s.IndexLatLng(90, 0); err != nil || got/16 != 2 {
+		t.Errorf("IndexLatLng(90, 0) = (%d, %v) want a cell on face 2", got, err)
+	}
+	if got, err := s.IndexLatLng(0, 180); err != nil || got/16 != 3 {
+		t.Errorf("IndexLatLng(0, 180) = (%d, %v) want a cell on face 3", got, err)
+	}
+}
+
+func TestSphereErrors(t *testing.T) {
+	if _, err := NewSphere(-1); err != ErrNegativeOrder {
+		t.Errorf("NewSphere(-1) = %v want %v", err, ErrNegativeOrder)
+	}
+	if _, err := NewSphere(MaxOrder); err != ErrTooLarge {
+		t.Errorf("NewSphere(%d) = %v want %v", MaxOrder, err, ErrTooLarge)
+	}
+	if _, err := NewSphere(MaxOrder - 1); err != nil {
+		t.Errorf("NewSphere(%d) returned error: %s", MaxOrder-1, err)
+	}
+
+	s, _ := NewSphere(3)
+	if _, err := s.Index(0, 0, 0); err != ErrOutOfRange {
+		t.Errorf("Index(0, 0, 0) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := s.Index(math.NaN(), 0, 1); err != ErrNotFinite {
+		t.Errorf("Index(NaN, 0, 1) = %v want %v", err, ErrNotFinite)
+	}
+	if _, err := s.Index(0, math.Inf(1), 1); err != ErrNotFinite {
+		t.Errorf("Index(0, +Inf, 1) = %v want %v", err, ErrNotFinite)
+	}
+	if _, err := s.IndexLatLng(91, 0); err != ErrOutOfRange {
+		t.Errorf("IndexLatLng(91, 0) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := s.IndexLatLng(0, math.NaN()); err != ErrNotFinite {
+		t.Errorf("IndexLatLng(0, NaN) = %v want %v", err, ErrNotFinite)
+	}
+	for _, d := range []int{-1, s.Len()} {
+		if _, _, _, err := s.Point(d); err != ErrOutOfRange {
+			t.Errorf("Point(%d) = %v want %v", d, err, ErrOutOfRange)
+		}
+		if _, _, err := s.LatLng(d); err != ErrOutOfRange {
+			t.Errorf("LatLng(%d) = %v want %v", d, err, ErrOutOfRange)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Stack represents a continuous curve over a row or column of any number of square Hilbert
+// curves, with a single index across all of them. Each tile uses the orientation the stack runs
+// in, so the end of each tile is next to the start of the following one, and tile i holds the
+// values [i*TileSize^2, (i+1)*TileSize^2-1]. Tiled is the Stack filling a given rectangle.
+// Implements SpaceFilling interface.
+type Stack struct {
+	TileSize, Tiles int
+
+	orientation Orientation
+	tile        *Hilbert
+}
+
+// NewStack returns a Stack of tiles square curves, each tileSize wide, which must be a power of
+// two. The tiles are placed side by side along x for Horizontal, or stacked along y for Vertical,
+// and any other orientation returns ErrOutOfRange. ErrTooLarge is returned if the number of cells
+// would not fit in an int.
+func NewStack(tileSize, tiles int, o Orientation) (*Stack, error) {
+	tile, err := NewHilbert(tileSize, o == Vertical)
+	if err != nil {
+		return nil, err
+	}
+	if tiles <= 0 {
+		return nil, ErrNotPositive
+	}
+	if o != Horizontal && o != Vertical {
+		return nil, ErrOutOfRange
+	}
+	if tiles > math.MaxInt/(tileSize*tileSize) {
+		return nil, ErrTooLarge
+	}
+
+	return &Stack{
+		TileSize:    tileSize,
+		Tiles:       tiles,
+		orientation: o,
+		tile:        tile,
+	}, nil
+}
+
+// Orientation returns the direction the tiles are placed in.
+func (s *Stack) Orientation() Orientation {
+	return s.orientation
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *Stack) GetDimensions() (int, int) {
+	if s.orientation == Vertical {
+		return s.TileSize, s.TileSize * s.Tiles
+	}
+	return s.TileSize * s.Tiles, s.TileSize
+}
+
+// Map transforms a one dimension value, t, in the range [0, Tiles*TileSize^2-1] to coordinates on
+// the curve in the two-dimension space.
+func (s *Stack) Map(t int) (x, y int, err error) {
+	cells := s.TileSize * s.TileSize
+	if t < 0 || t/cells >= s.Tiles {
+		return -1, -1, ErrOutOfRange
+	}
+
+	x, y = s.tile.MustMap(t % cells)
+	offset := t / cells * s.TileSize
+	if s.orientation == Vertical {
+		return x, y + offset, nil
+	}
+	return x + offset, y, nil
+}
+
+// MapInverse transform coordinates on the curve from (x,y) to t.
+func (s *Stack) MapInverse(x, y int) (t int, err error) {
+	along, across := x, y
+	if s.orientation == Vertical {
+		along, across = y, x
+	}
+	if across < 0 || across >= s.TileSize || along < 0 || along/s.TileSize >= s.Tiles {
+		return -1, ErrOutOfRange
+	}
+
+	t = s.tile.MustMapInverse(x%s.TileSize, y%s.TileSize)
+	return along/s.TileSize*s.TileSize*s.TileSize + t, nil
+}
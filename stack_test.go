@@ -0,0 +1,98 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewStackErrors(t *testing.T) {
+	testCases := []struct {
+		tileSize, tiles int
+		o               Orientation
+		want            error
+	}{
+		{0, 4, Horizontal, ErrNotPositive},
+		{4, 0, Horizontal, ErrNotPositive},
+		{3, 4, Vertical, ErrNotPowerOfTwo},
+		{4, 4, Orientation(2), ErrOutOfRange},
+		{1 << 8, math.MaxInt/(1<<16) + 1, Horizontal, ErrTooLarge},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewStack(tc.tileSize, tc.tiles, tc.o)
+		if s != nil || err != tc.want {
+			t.Errorf("NewStack(%d, %d, %s) = (%+v, %q) did not fail want (?, %q)", tc.tileSize, tc.tiles, tc.o, s, err, tc.want)
+		}
+	}
+}
+
+func TestStack(t *testing.T) {
+	testCases := []struct {
+		tileSize, tiles int
+		o               Orientation
+		w, h            int
+	}{
+		{1, 1, Horizontal, 1, 1},
+		{1, 5, Vertical, 1, 5},
+		{4, 3, Horizontal, 12, 4},
+		{8, 5, Vertical, 8, 40},
+		{16, 1, Vertical, 16, 16},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewStack(tc.tileSize, tc.tiles, tc.o)
+		if err != nil {
+			t.Fatalf("NewStack(%d, %d, %s) failed: %s", tc.tileSize, tc.tiles, tc.o, err)
+		}
+		if w, h := s.GetDimensions(); w != tc.w || h != tc.h {
+			t.Errorf("%s GetDimensions() = (%d, %d) want (%d, %d)", DescribeCurve(s), w, h, tc.w, tc.h)
+		}
+		if got := JumpCount(s); got != 0 {
+			t.Errorf("JumpCount(%s) = %d want 0", DescribeCurve(s), got)
+		}
+
+		tile, _ := NewHilbert(tc.tileSize, tc.o == Vertical)
+		cells := tc.tileSize * tc.tileSize
+		seen := make(map[[2]int]bool)
+		for d := 0; d < tc.w*tc.h; d++ {
+			x, y, err := s.Map(d)
+			if err != nil || x < 0 || x >= tc.w || y < 0 || y >= tc.h || seen[[2]int{x, y}] {
+				t.Fatalf("%s Map(%d) = (%d, %d, %v) is out of range or repeated", DescribeCurve(s), d, x, y, err)
+			}
+			seen[[2]int{x, y}] = true
+
+			// Within each tile the curve is the same as a single square curve.
+			if tx, ty, _ := tile.Map(d % cells); tx != x%tc.tileSize || ty != y%tc.tileSize {
+				t.Errorf("%s Map(%d) = (%d, %d) is at (%d, %d) in its tile want (%d, %d)", DescribeCurve(s), d, x, y, x%tc.tileSize, y%tc.tileSize, tx, ty)
+			}
+			if got, err := s.MapInverse(x, y); err != nil || got != d {
+				t.Errorf("%s Failed Map(%d) -> MapInverse(%d, %d) -> %d", DescribeCurve(s), d, x, y, got)
+			}
+		}
+
+		for _, d := range []int{-1, tc.w * tc.h} {
+			if _, _, err := s.Map(d); err != ErrOutOfRange {
+				t.Errorf("%s Map(%d) = %v want %v", DescribeCurve(s), d, err, ErrOutOfRange)
+			}
+		}
+		for _, p := range [][2]int{{-1, 0}, {0, -1}, {tc.w, 0}, {0, tc.h}} {
+			if _, err := s.MapInverse(p[0], p[1]); err != ErrOutOfRange {
+				t.Errorf("%s MapInverse(%d, %d) = %v want %v", DescribeCurve(s), p[0], p[1], err, ErrOutOfRange)
+			}
+		}
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// Transform is a symmetry of the square, optionally combined with reversing the direction of the
+// curve. The mirror is applied first, then the rotation.
+type Transform struct {
+	// Mirror reflects the space left to right, mapping (x, y) to (N-1-x, y).
+	Mirror bool
+
+	// Rotation is the number of quarter turns, in the range [0, 3]. Each quarter turn maps (x, y)
+	// to (N-1-y, x).
+	Rotation int
+
+	// Reversed traverses the curve in the opposite direction.
+	Reversed bool
+}
+
+// Apply returns the cell that (x, y) is moved to by the transform, in a space of width n. The
+// Reversed field has no effect on coordinates.
+func (tr Transform) Apply(n, x, y int) (int, int) {
+	if tr.Mirror {
+		x = n - 1 - x
+	}
+	for i := 0; i < tr.Rotation&3; i++ {
+		x, y = n-1-y, x
+	}
+	return x, y
+}
+
+// IsSymmetryOf returns true if other is the same as the receiver after a rotation, reflection or
+// reversal, along with the transform. That is, for every t, other.Map(t) is the receiver's
+// Map(t), or Map(N*N-1-t) if reversed, moved by the transform. The simplest transform is returned,
+// preferring no reversal, then no mirror, then the fewest quarter turns. Curves of different sizes
+// are never symmetries of each other.
+func (s *Hilbert) IsSymmetryOf(other *Hilbert) (bool, Transform) {
+	if s.N != other.N {
+		return false, Transform{}
+	}
+
+	last := s.N*s.N - 1
+	for _, reversed := range []bool{false, true} {
+		for _, mirror := range []bool{false, true} {
+			for rotation := 0; rotation < 4; rotation++ {
+				tr := Transform{Mirror: mirror, Rotation: rotation, Reversed: reversed}
+				if s.transformedBy(other, tr, last) {
+					return true, tr
+				}
+			}
+		}
+	}
+	return false, Transform{}
+}
+
+// transformedBy returns true if every cell of other is the receiver's cell moved by tr.
+func (s *Hilbert) transformedBy(other *Hilbert, tr Transform, last int) bool {
+	for t := 0; t <= last; t++ {
+		st := t
+		if tr.Reversed {
+			st = last - t
+		}
+		x, y, _ := s.Map(st)
+		x, y = tr.Apply(s.N, x, y)
+		ox, oy, _ := other.Map(t)
+		if x != ox || y != oy {
+			return false
+		}
+	}
+	return true
+}
+
+// WithTransform lays the curve out moved by tr, relative to the layout chosen by
+// verticalCompatible, so curves can be rotated and mirrored into whichever corners are needed to
+// stitch them together horizontally, vertically or in a grid. For example, NewHilbert(n, false,
+// WithTransform(Transform{Mirror: true, Rotation: 3})) maps the same as NewHilbert(n, true). If
+// tr.Reversed is true the curve is also reversed, as by Reversed.
+func WithTransform(tr Transform) Option {
+	return func(s *Hilbert) {
+		for i := uint8(0); i < 8; i++ {
+			state, mirrored := i&3, i&4 != 0
+			if layoutMatches(state, mirrored, func(x, y int) (int, int) {
+				x, y = applyLayout(s.startState, s.mirrored, 2, x, y)
+				return tr.Apply(2, x, y)
+			}) {
+				s.startState, s.mirrored = state, mirrored
+				break
+			}
+		}
+		s.reversed = s.reversed != tr.Reversed
+	}
+}
+
+// Transform returns the transform which moves the horizontal curve, as created by
+// NewHilbert(N, false), to this one. The simplest is returned, preferring no mirror, then the
+// fewest quarter turns, with Reversed set if the curve is reversed.
+func (s *Hilbert) Transform() Transform {
+	for _, mirror := range []bool{false, true} {
+		for rotation := 0; rotation < 4; rotation++ {
+			tr := Transform{Mirror: mirror, Rotation: rotation, Reversed: s.reversed}
+			if layoutMatches(s.startState, s.mirrored, func(x, y int) (int, int) {
+				return tr.Apply(2, x, y)
+			}) {
+				return tr
+			}
+		}
+	}
+	return Transform{Reversed: s.reversed}
+}
+
+// applyLayout moves (x, y) in a space of width n from the horizontal curve to the layout of a
+// curve with the given start state and mirroring. The start state transposes the space if bit 0
+// is set, and then flips it in both x and y if bit 1 is set.
+func applyLayout(state uint8, mirrored bool, n, x, y int) (int, int) {
+	if state&1 == 1 {
+		x, y = y, x
+	}
+	if state&2 == 2 {
+		x, y = n-1-x, n-1-y
+	}
+	if mirrored {
+		x = n - 1 - x
+	}
+	return x, y
+}
+
+// layoutMatches returns true if move, in a space of width 2, agrees with the layout of a curve
+// with the given start state and mirroring. A symmetry of the square is identified by where it
+// moves two adjacent corners, so only those are checked.
+func layoutMatches(state uint8, mirrored bool, move func(x, y int) (int, int)) bool {
+	for _, p := range [2][2]int{{0, 0}, {1, 0}} {
+		x, y := applyLayout(state, mirrored, 2, p[0], p[1])
+		mx, my := move(p[0], p[1])
+		if x != mx || y != my {
+			return false
+		}
+	}
+	return true
+}
+
+// layout packs the start state and mirroring of the curve into a single value, used to check
+// lookup tables were built for the same layout.
+func (s *Hilbert) layout() uint8 {
+	return s.startState | uint8(b2i(s.mirrored))<<2
+}
+
+// hasDefaultLayout returns true if the curve is laid out as chosen by verticalCompatible alone,
+// without a WithTransform option.
+func (s *Hilbert) hasDefaultLayout() bool {
+	return s.layout() == uint8(b2i(s.verticalCompatible))
+}
+
+// canonicalLayout returns the simplest layout and direction which map every value on the curve to
+// the same cell as this one, so equivalent curves can be identified. A curve is decided by its
+// first and last cells, and each of the eight curves over a space is reached by two of the
+// sixteen layouts and directions, as reversing the curve and mirroring it across the line between
+// its ends gives it back. For N=1 all of them are the same curve. The layouts chosen by
+// verticalCompatible alone are preferred, and then the forward direction.
+func (s *Hilbert) canonicalLayout() (layout uint8, reversed bool) {
+	ends := func(layout uint8, reversed bool) [4]int {
+		state, mirrored := layout&3, layout&4 != 0
+		sx, sy := applyLayout(state, mirrored, s.N, 0, 0)
+		ex, ey := applyLayout(state, mirrored, s.N, s.N-1, 0)
+		if reversed {
+			return [4]int{ex, ey, sx, sy}
+		}
+		return [4]int{sx, sy, ex, ey}
+	}
+
+	want := ends(s.layout(), s.reversed)
+	for layout := uint8(0); layout < 8; layout++ {
+		for _, reversed := range []bool{false, true} {
+			if ends(layout, reversed) == want {
+				return layout, reversed
+			}
+		}
+	}
+	return s.layout(), s.reversed
+}
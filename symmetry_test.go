@@ -0,0 +1,139 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestTransformApply(t *testing.T) {
+	tests := []struct {
+		tr           Transform
+		x, y         int
+		wantX, wantY int
+	}{
+		{Transform{}, 1, 0, 1, 0},
+		{Transform{Rotation: 1}, 1, 0, 3, 1},
+		{Transform{Rotation: 2}, 1, 0, 2, 3},
+		{Transform{Rotation: 3}, 1, 0, 0, 2},
+		{Transform{Mirror: true}, 1, 0, 2, 0},
+		{Transform{Mirror: true, Rotation: 3}, 1, 0, 0, 1},
+	}
+	for _, test := range tests {
+		if x, y := test.tr.Apply(4, test.x, test.y); x != test.wantX || y != test.wantY {
+			t.Errorf("%+v.Apply(4, %d, %d) = (%d, %d) want (%d, %d)", test.tr, test.x, test.y, x, y, test.wantX, test.wantY)
+		}
+	}
+}
+
+func TestIsSymmetryOf(t *testing.T) {
+	h, _ := NewHilbert(8, false)
+	v, _ := NewHilbert(8, true)
+
+	tests := []struct {
+		name string
+		s, o *Hilbert
+		want Transform
+	}{
+		{"identity", h, h, Transform{}},
+		{"vertical", h, v, Transform{Mirror: true, Rotation: 3}},
+		{"horizontal", v, h, Transform{Mirror: true, Rotation: 3}},
+		// Reversing the curve is the same as mirroring it, so no reversal is needed.
+		{"reversed", h, h.Reversed(), Transform{Mirror: true}},
+		{"vertical reversed", h, v.Reversed(), Transform{Rotation: 3}},
+	}
+	for _, test := range tests {
+		ok, tr := test.s.IsSymmetryOf(test.o)
+		if !ok || tr != test.want {
+			t.Errorf("%s: IsSymmetryOf() = (%t, %+v) want (true, %+v)", test.name, ok, tr, test.want)
+		}
+	}
+
+	small, _ := NewHilbert(4, false)
+	if ok, _ := h.IsSymmetryOf(small); ok {
+		t.Errorf("IsSymmetryOf() of a different size = true want false")
+	}
+}
+
+func TestWithTransform(t *testing.T) {
+	for n := 1; n <= 16; n *= 2 {
+		for _, vertical := range []bool{false, true} {
+			base, _ := NewHilbert(n, vertical)
+			for _, reversed := range []bool{false, true} {
+				for _, mirror := range []bool{false, true} {
+					for rotation := 0; rotation < 4; rotation++ {
+						tr := Transform{Mirror: mirror, Rotation: rotation, Reversed: reversed}
+						s, _ := NewHilbert(n, vertical, WithTransform(tr))
+						if !base.transformedBy(s, tr, n*n-1) {
+							t.Errorf("NewHilbert(%d, %t, WithTransform(%+v)) is not the transformed curve", n, vertical, tr)
+							continue
+						}
+
+						// Every way of walking the curve must agree with Map.
+						g := s.Generator()
+						for d := 0; d < n*n; d++ {
+							x, y, _ := s.Map(d)
+							if tx, ty, _ := s.MapTrace(d, nil); tx != x || ty != y {
+								t.Errorf("WithTransform(%+v).MapTrace(%d) = (%d, %d) want (%d, %d)", tr, d, tx, ty, x, y)
+							}
+							if got, _ := s.MapInverse(x, y); got != d {
+								t.Errorf("WithTransform(%+v).MapInverse(%d, %d) = %d want %d", tr, x, y, got, d)
+							}
+							if gx, gy, _ := g.Next(); gx != x || gy != y {
+								t.Errorf("WithTransform(%+v).Generator() = (%d, %d) want (%d, %d)", tr, gx, gy, x, y)
+							}
+							if d+1 < n*n {
+								wantX, wantY, _ := s.Map(d + 1)
+								if nx, ny, err := s.NextCell(x, y); err != nil || nx != wantX || ny != wantY {
+									t.Errorf("WithTransform(%+v).NextCell(%d, %d) = (%d, %d, %v) want (%d, %d, nil)", tr, x, y, nx, ny, err, wantX, wantY)
+								}
+							}
+						}
+
+						ts, xs, ys := make([]int, n*n), make([]int, n*n), make([]int, n*n)
+						for d := range ts {
+							ts[d] = d
+						}
+						s.MapBatch(ts, xs, ys)
+						s.MapInverseBatch(xs, ys, ts)
+						for d := range ts {
+							if x, y, _ := s.Map(d); xs[d] != x || ys[d] != y || ts[d] != d {
+								t.Errorf("WithTransform(%+v).MapBatch()[%d] = (%d, %d) and back to %d want (%d, %d)", tr, d, xs[d], ys[d], ts[d], x, y)
+							}
+						}
+
+						// Applying the transform of the curve to the horizontal curve gives it back.
+						got := s.Transform()
+						if h, _ := NewHilbert(n, false, WithTransform(got)); !h.transformedBy(s, Transform{}, n*n-1) {
+							t.Errorf("NewHilbert(%d, %t, WithTransform(%+v)).Transform() = %+v, which is a different curve", n, vertical, tr, got)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestWithTransformVertical(t *testing.T) {
+	h, _ := NewHilbert(8, false, WithTransform(Transform{Mirror: true, Rotation: 3}))
+	v, _ := NewHilbert(8, true)
+	if !h.transformedBy(v, Transform{}, 63) {
+		t.Errorf("WithTransform(%+v) of the horizontal curve is not the vertical curve", Transform{Mirror: true, Rotation: 3})
+	}
+	if h.Orientation() != Vertical || h.IsVerticalCompatible() {
+		t.Errorf("Orientation(), IsVerticalCompatible() = %s, %t want %s, false", h.Orientation(), h.IsVerticalCompatible(), Vertical)
+	}
+	if got, want := v.Transform(), (Transform{Mirror: true, Rotation: 3}); got != want {
+		t.Errorf("NewHilbert(8, true).Transform() = %+v want %+v", got, want)
+	}
+}
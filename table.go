@@ -0,0 +1,96 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math"
+
+// Table represents an arbitrary space-filling order of an N by N space, defined by a lookup table,
+// such as one generated by another tool. Implements SpaceFilling interface.
+type Table struct {
+	N int // The width/height of the space.
+
+	index []int // index[y*N+x] is the value of the cell (x,y) on the curve.
+	cells []int // cells[t] is y*N+x for the cell (x,y) at t.
+}
+
+// NewFromTable returns a new Table using forward as the coordinate-to-index table, where
+// forward[y*N+x] is the value t for the cell (x,y). The length of forward must be a perfect
+// square N*N, and it must be a permutation of [0, N*N). forward is copied, so may be modified
+// afterwards.
+func NewFromTable(forward []int) (*Table, error) {
+	if len(forward) == 0 {
+		return nil, ErrNotPositive
+	}
+
+	n := int(math.Sqrt(float64(len(forward))))
+	for n*n > len(forward) {
+		n--
+	}
+	for (n+1)*(n+1) <= len(forward) {
+		n++
+	}
+	if n*n != len(forward) {
+		return nil, ErrNotSquare
+	}
+
+	cells := make([]int, len(forward))
+	seen := make([]bool, len(forward))
+	for p, t := range forward {
+		if t < 0 || t >= len(forward) || seen[t] {
+			return nil, ErrBadTables
+		}
+		seen[t] = true
+		cells[t] = p
+	}
+
+	return &Table{
+		N:     n,
+		index: append([]int(nil), forward...),
+		cells: cells,
+	}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *Table) GetDimensions() (int, int) {
+	return s.N, s.N
+}
+
+// Map transforms a one dimension value, t, in the range [0, n^2-1] to the coordinates of the cell
+// at t in the table, where x and y are within [0,n-1].
+func (s *Table) Map(t int) (x, y int, err error) {
+	if t < 0 || t >= len(s.cells) {
+		return -1, -1, ErrOutOfRange
+	}
+	return s.cells[t] % s.N, s.cells[t] / s.N, nil
+}
+
+// MapInverse transform coordinates from (x,y) to t using the table.
+func (s *Table) MapInverse(x, y int) (t int, err error) {
+	if x < 0 || x >= s.N || y < 0 || y >= s.N {
+		return -1, ErrOutOfRange
+	}
+	return s.index[y*s.N+x], nil
+}
+
+// IsConnectedRange returns true if the cells with values in the range [lo, hi] form a single
+// 4-connected region. Unlike a Hilbert curve, a table may jump between distant cells, so for
+// example the range [0, 1] of a row-major table for a 2x2 space is connected, but [1, 2] is not.
+// ErrOutOfRange is returned if the range is not within the curve.
+func (s *Table) IsConnectedRange(lo, hi int) (bool, error) {
+	if lo < 0 || hi >= len(s.cells) || lo > hi {
+		return false, ErrOutOfRange
+	}
+	return connectedRange(s, lo, hi), nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "testing"
+
+func TestNewFromTable(t *testing.T) {
+	h, err := NewHilbert(16, true)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	forward := make([]int, h.N*h.N)
+	for d := range forward {
+		x, y, _ := h.Map(d)
+		forward[y*h.N+x] = d
+	}
+
+	s, err := NewFromTable(forward)
+	if err != nil {
+		t.Fatalf("NewFromTable() failed: %s", err)
+	}
+	forward[0] = -1 // The table must have been copied.
+
+	if w, h := s.GetDimensions(); w != 16 || h != 16 {
+		t.Errorf("GetDimensions() = (%d, %d) want (16, 16)", w, h)
+	}
+	for d := 0; d < s.N*s.N; d++ {
+		wantX, wantY, _ := h.Map(d)
+		x, y, err := s.Map(d)
+		if err != nil || x != wantX || y != wantY {
+			t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, wantX, wantY)
+		}
+		if got, err := s.MapInverse(x, y); err != nil || got != d {
+			t.Errorf("MapInverse(%d, %d) = (%d, %v) want (%d, nil)", x, y, got, err, d)
+		}
+	}
+}
+
+func TestNewFromTableErrors(t *testing.T) {
+	var testCases = []struct {
+		forward []int
+		want    error
+	}{
+		{nil, ErrNotPositive},
+		{[]int{0, 1, 2}, ErrNotSquare},
+		{[]int{0, 1, 2, 3, 4, 5, 6, 7}, ErrNotSquare},
+		{[]int{0, 1, 2, 2}, ErrBadTables},
+		{[]int{0, 1, 2, 4}, ErrBadTables},
+		{[]int{0, -1, 2, 3}, ErrBadTables},
+		{[]int{3, 2, 1, 0}, nil},
+		{[]int{0}, nil},
+	}
+
+	for _, tc := range testCases {
+		if _, err := NewFromTable(tc.forward); err != tc.want {
+			t.Errorf("NewFromTable(%v) = %v want %v", tc.forward, err, tc.want)
+		}
+	}
+}
+
+func TestTableOutOfRange(t *testing.T) {
+	s, err := NewFromTable([]int{0, 3, 1, 2})
+	if err != nil {
+		t.Fatalf("NewFromTable() failed: %s", err)
+	}
+
+	if _, _, err := s.Map(4); err != ErrOutOfRange {
+		t.Errorf("Map(4) = %v want %v", err, ErrOutOfRange)
+	}
+	if _, err := s.MapInverse(2, 0); err != ErrOutOfRange {
+		t.Errorf("MapInverse(2, 0) = %v want %v", err, ErrOutOfRange)
+	}
+	if x, y, _ := s.Map(3); x != 1 || y != 0 {
+		t.Errorf("Map(3) = (%d, %d) want (1, 0)", x, y)
+	}
+}
+
+func TestTableIsConnectedRange(t *testing.T) {
+	// Row-major order of a 3x3 space.
+	s, err := NewFromTable([]int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+	if err != nil {
+		t.Fatalf("NewFromTable() failed: %s", err)
+	}
+
+	testCases := []struct {
+		lo, hi int
+		want   bool
+	}{
+		{0, 0, true},
+		{0, 2, true},
+		{0, 3, true},
+		{2, 3, false},
+		{2, 4, false},
+		{2, 5, true},
+		{1, 3, false},
+		{1, 4, true},
+		{0, 8, true},
+	}
+	for _, tc := range testCases {
+		if got, err := s.IsConnectedRange(tc.lo, tc.hi); err != nil || got != tc.want {
+			t.Errorf("IsConnectedRange(%d, %d) = (%t, %v) want (%t, nil)", tc.lo, tc.hi, got, err, tc.want)
+		}
+	}
+
+	for _, r := range []Range{{-1, 0}, {0, 9}, {5, 4}} {
+		if _, err := s.IsConnectedRange(r.Lo, r.Hi); err != ErrOutOfRange {
+			t.Errorf("IsConnectedRange(%d, %d) = %v want %v", r.Lo, r.Hi, err, ErrOutOfRange)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+)
+
+// maxTableN is the largest N which lookup tables can be built for, as entries are 32 bits.
+const maxTableN = 1 << 16
+
+// tableEntryBytes is the size in bytes of each entry in the lookup tables.
+const tableEntryBytes = 4
+
+// tablesMagic identifies the format written by ExportTables.
+var tablesMagic = [4]byte{'H', 'L', 'B', '1'}
+
+// tablesLayoutMagic identifies the format written by ExportTables for curves created with
+// WithTransform, where the header is followed by a single byte recording the layout.
+var tablesLayoutMagic = [4]byte{'H', 'L', 'B', '2'}
+
+// tablesHeader is the header written by ExportTables, followed by the layout byte if the magic
+// is tablesLayoutMagic, and then N*N little-endian uint32 values of the forward table.
+type tablesHeader struct {
+	Magic              [4]byte
+	N                  uint64
+	VerticalCompatible bool
+	Reversed           bool
+}
+
+// Prewarm builds lookup tables for the curve, so that all future calls to Map and MapInverse are
+// a single table lookup. The tables use 8*N*N bytes, and can only be built when N is at most
+// 65536. Prewarm must not be called concurrently with other methods.
+func (s *Hilbert) Prewarm() error {
+	if s.forward != nil {
+		return nil
+	}
+	if s.N > maxTableN {
+		return ErrTooLarge
+	}
+
+	forward := make([]uint32, s.N*s.N)
+	for t := range forward {
+		x, y, _ := s.Map(t)
+		forward[t] = uint32(y*s.N + x)
+	}
+	return s.setTables(forward)
+}
+
+// NewHilbertCached is like NewHilbert, but also calls Prewarm, so Map and MapInverse are always a
+// single table lookup. The tables use 8*n*n bytes, which is 512 KiB for n=256, so this is best
+// suited to small curves which are mapped many times. ErrTooLarge is returned if n is more than
+// 65536.
+func NewHilbertCached(n int, verticalCompatible bool, opts ...Option) (*Hilbert, error) {
+	s, err := NewHilbert(n, verticalCompatible, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Prewarm(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// MaxOrderForMemory returns the largest order of curve whose lookup tables, as built by Prewarm,
+// fit within the given number of bytes, or -1 if not even the tables for N=1 fit. Both tables
+// have N*N 4 byte entries, so use 8*N*N bytes in total. The result is never more than 16, the
+// largest order tables can be built for.
+func MaxOrderForMemory(bytes int) int {
+	order := -1
+	for n := uint64(1); n <= maxTableN && bytes >= 0 && 2*tableEntryBytes*n*n <= uint64(bytes); n *= 2 {
+		order++
+	}
+	return order
+}
+
+// setTables sets the lookup tables from the forward table, after checking it is a permutation.
+func (s *Hilbert) setTables(forward []uint32) error {
+	inverse := make([]uint32, len(forward))
+	seen := make([]bool, len(forward))
+	for t, p := range forward {
+		if int(p) >= len(forward) || seen[p] {
+			return ErrBadTables
+		}
+		seen[p] = true
+		inverse[p] = uint32(t)
+	}
+
+	s.forward, s.inverse = forward, inverse
+	return nil
+}
+
+// ExportTables writes the lookup tables to w, building them first if needed, so they can later
+// be loaded with ImportTables instead of being rebuilt. The tables are preceded by a header
+// recording N, verticalCompatible, any transform and if the curve is reversed.
+func (s *Hilbert) ExportTables(w io.Writer) error {
+	if err := s.Prewarm(); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	header := tablesHeader{
+		Magic:              tablesMagic,
+		N:                  uint64(s.N),
+		VerticalCompatible: s.verticalCompatible,
+		Reversed:           s.reversed,
+	}
+	if !s.hasDefaultLayout() {
+		header.Magic = tablesLayoutMagic
+	}
+	if err := binary.Write(bw, binary.LittleEndian, header); err != nil {
+		return err
+	}
+	if header.Magic == tablesLayoutMagic {
+		if err := bw.WriteByte(s.layout()); err != nil {
+			return err
+		}
+	}
+	if err := binary.Write(bw, binary.LittleEndian, s.forward); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// ImportTables loads lookup tables previously written by ExportTables. ErrConfigMismatch is
+// returned if the tables were written for a differently configured curve, and
+// ErrBadTables if they are corrupt. On error the curve is left unchanged. ImportTables must not
+// be called concurrently with other methods.
+func (s *Hilbert) ImportTables(r io.Reader) error {
+	var header tablesHeader
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		return err
+	}
+	layout := uint8(b2i(header.VerticalCompatible))
+	switch header.Magic {
+	case tablesMagic:
+	case tablesLayoutMagic:
+		var b [1]byte
+		if _, err := io.ReadFull(r, b[:]); err != nil {
+			return err
+		}
+		layout = b[0]
+	default:
+		return ErrBadTables
+	}
+	if header.N != uint64(s.N) || header.VerticalCompatible != s.verticalCompatible || header.Reversed != s.reversed ||
+		layout != s.layout() {
+		return ErrConfigMismatch
+	}
+	if s.N > maxTableN {
+		return ErrTooLarge
+	}
+
+	forward := make([]uint32, s.N*s.N)
+	if err := binary.Read(bufio.NewReader(r), binary.LittleEndian, forward); err != nil {
+		return err
+	}
+	return s.setTables(forward)
+}
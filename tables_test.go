@@ -0,0 +1,238 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"testing"
+)
+
+// sameMapping checks that got maps every value exactly as want does.
+func sameMapping(t *testing.T, got, want *Hilbert) {
+	t.Helper()
+	for d := 0; d < want.N*want.N; d++ {
+		wantX, wantY, _ := want.Map(d)
+		x, y, err := got.Map(d)
+		if err != nil || x != wantX || y != wantY {
+			t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, wantX, wantY)
+		}
+		d2, err := got.MapInverse(wantX, wantY)
+		if err != nil || d2 != d {
+			t.Errorf("MapInverse(%d, %d) = (%d, %v) want (%d, nil)", wantX, wantY, d2, err, d)
+		}
+	}
+}
+
+func TestPrewarm(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		want, _ := NewHilbert(32, vertical)
+		s, _ := NewHilbert(32, vertical)
+		if err := s.Prewarm(); err != nil {
+			t.Fatalf("Prewarm() returned error: %s", err)
+		}
+		sameMapping(t, s, want)
+
+		if _, _, err := s.Map(32 * 32); err != ErrOutOfRange {
+			t.Errorf("Map(%d) = %q want %q", 32*32, err, ErrOutOfRange)
+		}
+		if _, err := s.MapInverse(32, 0); err != ErrOutOfRange {
+			t.Errorf("MapInverse(32, 0) = %q want %q", err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestPrewarmReversed(t *testing.T) {
+	want, _ := NewHilbert(16, true)
+	want = want.Reversed()
+	s := want.Reversed().Reversed()
+	if err := s.Prewarm(); err != nil {
+		t.Fatalf("Prewarm() returned error: %s", err)
+	}
+	sameMapping(t, s, want)
+}
+
+func TestNewHilbertCached(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		want, _ := NewHilbert(256, vertical)
+		s, err := NewHilbertCached(256, vertical)
+		if err != nil {
+			t.Fatalf("NewHilbertCached(256) returned error: %s", err)
+		}
+		if s.forward == nil || s.inverse == nil {
+			t.Errorf("NewHilbertCached(256) did not build the lookup tables")
+		}
+		sameMapping(t, s, want)
+	}
+
+	if _, err := NewHilbertCached(3, false); err != ErrNotPowerOfTwo {
+		t.Errorf("NewHilbertCached(3) = %v want %v", err, ErrNotPowerOfTwo)
+	}
+
+	s, err := NewHilbertCached(4, false, WithBoundsPolicy(BoundsWrap))
+	if err != nil {
+		t.Fatalf("NewHilbertCached(4) returned error: %s", err)
+	}
+	if x, y, err := s.Map(-1); err != nil || x != 3 || y != 0 {
+		t.Errorf("Map(-1) with BoundsWrap = (%d, %d, %v) want (3, 0, nil)", x, y, err)
+	}
+}
+
+func TestPrewarmTooLarge(t *testing.T) {
+	// maxTableN*2 is more than MaxOrder allows when int is 32 bits.
+	s, err := NewHilbert(maxTableN*2, false)
+	if err != nil {
+		t.Skipf("NewHilbert(%d) failed: %s", maxTableN*2, err)
+	}
+	if err := s.Prewarm(); err != ErrTooLarge {
+		t.Errorf("Prewarm() = %q want %q", err, ErrTooLarge)
+	}
+}
+
+func TestExportImportTables(t *testing.T) {
+	for _, vertical := range []bool{false, true} {
+		for _, tr := range []Transform{{}, {Rotation: 1}, {Mirror: true, Rotation: 2}} {
+			src, _ := NewHilbert(16, vertical, WithTransform(tr))
+
+			var buf bytes.Buffer
+			if err := src.ExportTables(&buf); err != nil {
+				t.Fatalf("ExportTables() returned error: %s", err)
+			}
+
+			dst, _ := NewHilbert(16, vertical, WithTransform(tr))
+			if err := dst.ImportTables(&buf); err != nil {
+				t.Fatalf("ImportTables() returned error: %s", err)
+			}
+			if dst.forward == nil {
+				t.Errorf("ImportTables() did not set the lookup tables")
+			}
+
+			want, _ := NewHilbert(16, vertical, WithTransform(tr))
+			sameMapping(t, dst, want)
+		}
+	}
+}
+
+func TestImportTablesErrors(t *testing.T) {
+	src, _ := NewHilbert(4, false)
+	var buf bytes.Buffer
+	if err := src.ExportTables(&buf); err != nil {
+		t.Fatalf("ExportTables() returned error: %s", err)
+	}
+	exported := buf.Bytes()
+
+	// The first entry of the forward table follows the 14 byte header.
+	duplicate := append([]byte(nil), exported...)
+	copy(duplicate[14:18], duplicate[18:22])
+
+	var reversed bytes.Buffer
+	if err := src.Reversed().ExportTables(&reversed); err != nil {
+		t.Fatalf("ExportTables() returned error: %s", err)
+	}
+
+	var transformed bytes.Buffer
+	rotated, _ := NewHilbert(4, false, WithTransform(Transform{Rotation: 2}))
+	if err := rotated.ExportTables(&transformed); err != nil {
+		t.Fatalf("ExportTables() returned error: %s", err)
+	}
+
+	badMagic := append([]byte(nil), exported...)
+	badMagic[0] = 'X'
+
+	testCases := []struct {
+		name     string
+		n        int
+		vertical bool
+		data     []byte
+		wantErr  error
+	}{
+		{"different N", 8, false, exported, ErrConfigMismatch},
+		{"different orientation", 4, true, exported, ErrConfigMismatch},
+		{"reversed", 4, false, reversed.Bytes(), ErrConfigMismatch},
+		{"transformed", 4, false, transformed.Bytes(), ErrConfigMismatch},
+		{"truncated transform", 4, false, transformed.Bytes()[:14], io.EOF},
+		{"bad magic", 4, false, badMagic, ErrBadTables},
+		{"not a permutation", 4, false, duplicate, ErrBadTables},
+		{"truncated", 4, false, exported[:len(exported)-1], io.ErrUnexpectedEOF},
+		{"empty", 4, false, nil, io.EOF},
+	}
+
+	for _, tc := range testCases {
+		s, _ := NewHilbert(tc.n, tc.vertical)
+		if err := s.ImportTables(bytes.NewReader(tc.data)); err != tc.wantErr {
+			t.Errorf("ImportTables(%s) = %q want %q", tc.name, err, tc.wantErr)
+		}
+		if s.forward != nil || s.inverse != nil {
+			t.Errorf("ImportTables(%s) modified the curve on error", tc.name)
+		}
+	}
+}
+
+func TestMaxOrderForMemory(t *testing.T) {
+	testCases := []struct {
+		bytes int
+		want  int
+	}{
+		{-1, -1},
+		{0, -1},
+		{7, -1},
+		{8, 0},
+		{31, 0},
+		{32, 1},
+		{8 * 1024 * 1024, 10},
+		{8*1024*1024 - 1, 9},
+		{math.MaxInt, 16},
+	}
+
+	for _, tc := range testCases {
+		if got := MaxOrderForMemory(tc.bytes); got != tc.want {
+			t.Errorf("MaxOrderForMemory(%d) = %d want %d", tc.bytes, got, tc.want)
+		}
+	}
+}
+
+func BenchmarkMapPrewarmed(b *testing.B) {
+	s, err := NewHilbert(benchmarkN, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+	if err := s.Prewarm(); err != nil {
+		b.Fatalf("Prewarm() failed: %s", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for d := 0; d < benchmarkN*benchmarkN; d++ {
+			s.Map(d)
+		}
+	}
+}
+
+func BenchmarkMapInverseCached(b *testing.B) {
+	s, err := NewHilbertCached(benchmarkN, false)
+	if err != nil {
+		b.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for x := 0; x < benchmarkN; x++ {
+			for y := 0; y < benchmarkN; y++ {
+				s.MapInverse(x, y)
+			}
+		}
+	}
+}
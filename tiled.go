@@ -0,0 +1,74 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "math/bits"
+
+// Tiled represents a continuous Hilbert curve over a rectangle whose sides are powers of two, by
+// placing square Hilbert curves one after another along the longer side. For a wide rectangle
+// the squares use the horizontal orientation, and for a tall one the vertical orientation, so the
+// end of each square is next to the start of the following one. It is the Stack of those squares,
+// sized from the rectangle. Unlike NewHilbertRect, the values on the curve are exactly
+// [0, W*H-1], with no padding. Implements SpaceFilling interface.
+type Tiled struct {
+	W, H int
+
+	stack *Stack // The squares, of side min(W, H), along the longer side
+}
+
+// NewTiledHilbert returns a Tiled curve of width w and height h, which must both be powers of
+// two. ErrTooLarge is returned if w*h would not fit in an int.
+func NewTiledHilbert(w, h int) (*Tiled, error) {
+	if w <= 0 || h <= 0 {
+		return nil, ErrNotPositive
+	}
+	if w&(w-1) != 0 || h&(h-1) != 0 {
+		return nil, ErrNotPowerOfTwo
+	}
+	if bits.TrailingZeros(uint(w))+bits.TrailingZeros(uint(h)) >= bits.UintSize-1 {
+		return nil, ErrTooLarge
+	}
+
+	o := Horizontal
+	if h > w {
+		o = Vertical
+	}
+	stack, err := NewStack(min(w, h), max(w, h)/min(w, h), o)
+	if err != nil {
+		return nil, err
+	}
+	return &Tiled{W: w, H: h, stack: stack}, nil
+}
+
+// GetDimensions returns the width and height of the 2D space.
+func (s *Tiled) GetDimensions() (int, int) {
+	return s.W, s.H
+}
+
+// Squares returns the number of squares the rectangle is made of.
+func (s *Tiled) Squares() int {
+	return s.stack.Tiles
+}
+
+// Map transforms a one dimension value, t, in the range [0, W*H-1] to coordinates on the curve in
+// the two-dimension space, where x is within [0,W-1] and y is within [0,H-1].
+func (s *Tiled) Map(t int) (x, y int, err error) {
+	return s.stack.Map(t)
+}
+
+// MapInverse transform coordinates on the curve from (x,y) to t.
+func (s *Tiled) MapInverse(x, y int) (t int, err error) {
+	return s.stack.MapInverse(x, y)
+}
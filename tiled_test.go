@@ -0,0 +1,106 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestNewTiledHilbertErrors(t *testing.T) {
+	testCases := []struct {
+		w, h int
+		want error
+	}{
+		{0, 4, ErrNotPositive},
+		{4, -1, ErrNotPositive},
+		{12, 4, ErrNotPowerOfTwo},
+		{4, 3, ErrNotPowerOfTwo},
+		{1 << (bits.UintSize / 2), 1 << (bits.UintSize / 2), ErrTooLarge},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewTiledHilbert(tc.w, tc.h)
+		if s != nil || err != tc.want {
+			t.Errorf("NewTiledHilbert(%d, %d) = (%+v, %q) did not fail want (?, %q)", tc.w, tc.h, s, err, tc.want)
+		}
+	}
+}
+
+func TestTiledHilbert(t *testing.T) {
+	testCases := []struct {
+		w, h    int
+		squares int
+	}{
+		{1, 1, 1},
+		{16, 16, 1},
+		{512, 128, 4},
+		{2, 1, 2},
+		{8, 64, 8},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewTiledHilbert(tc.w, tc.h)
+		if err != nil {
+			t.Fatalf("NewTiledHilbert(%d, %d) failed: %s", tc.w, tc.h, err)
+		}
+		if got := s.Squares(); got != tc.squares {
+			t.Errorf("%s Squares() = %d want %d", DescribeCurve(s), got, tc.squares)
+		}
+		if got := JumpCount(s); got != 0 {
+			t.Errorf("JumpCount(%s) = %d want 0", DescribeCurve(s), got)
+		}
+
+		seen := make(map[[2]int]bool)
+		for d := 0; d < tc.w*tc.h; d++ {
+			x, y, err := s.Map(d)
+			if err != nil || x < 0 || x >= tc.w || y < 0 || y >= tc.h || seen[[2]int{x, y}] {
+				t.Fatalf("%s Map(%d) = (%d, %d, %v) is out of range or repeated", DescribeCurve(s), d, x, y, err)
+			}
+			seen[[2]int{x, y}] = true
+			if got, err := s.MapInverse(x, y); err != nil || got != d {
+				t.Errorf("%s Failed Map(%d) -> MapInverse(%d, %d) -> %d", DescribeCurve(s), d, x, y, got)
+			}
+		}
+
+		if _, _, err := s.Map(tc.w * tc.h); err != ErrOutOfRange {
+			t.Errorf("%s Map(%d) = %v want %v", DescribeCurve(s), tc.w*tc.h, err, ErrOutOfRange)
+		}
+		if _, err := s.MapInverse(tc.w, 0); err != ErrOutOfRange {
+			t.Errorf("%s MapInverse(%d, 0) = %v want %v", DescribeCurve(s), tc.w, err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestTiledHilbertIsStack(t *testing.T) {
+	for _, tc := range [][2]int{{8, 8}, {32, 8}, {4, 16}} {
+		w, h := tc[0], tc[1]
+		s, err := NewTiledHilbert(w, h)
+		if err != nil {
+			t.Fatalf("NewTiledHilbert(%d, %d) failed: %s", w, h, err)
+		}
+		o := Horizontal
+		if h > w {
+			o = Vertical
+		}
+		stack, _ := NewStack(min(w, h), max(w, h)/min(w, h), o)
+		for d := 0; d < w*h; d++ {
+			x, y, _ := s.Map(d)
+			if sx, sy, _ := stack.Map(d); x != sx || y != sy {
+				t.Errorf("NewTiledHilbert(%d, %d).Map(%d) = (%d, %d) want (%d, %d)", w, h, d, x, y, sx, sy)
+			}
+		}
+	}
+}
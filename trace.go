@@ -0,0 +1,64 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+// MapTrace is the same as Map, but calls visit at each level of the recursion, to help with
+// debugging and teaching. The levels are visited from the smallest sub-square up, and at level
+// k, (x,y) are the coordinates of t within its sub-square of width 2^(k+1), after that square
+// has been rotated and offset. The coordinates are always for the horizontal orientation, the
+// vertical rotation, and any transform, is only applied to the returned result.
+//
+// MapTrace is a separate copy of the Map algorithm, so Map pays no cost for the tracing.
+func (s *Hilbert) MapTrace(t int, visit func(level, x, y int)) (x, y int, err error) {
+	if t < 0 || t >= s.N*s.N {
+		var ok bool
+		if t, ok = s.bounds.fit(t, s.N*s.N); !ok {
+			return -1, -1, ErrOutOfRange
+		}
+	}
+
+	if s.reversed {
+		t = s.N*s.N - 1 - t
+	}
+
+	level := 0
+	for i := 1; i < s.N; i = i * 2 {
+		rx := t&2 == 2
+		ry := t&1 == 1
+		if rx {
+			ry = !ry
+		}
+
+		x, y = s.rotate(i, x, y, rx, ry)
+
+		if rx {
+			x = x + i
+		}
+		if ry {
+			y = y + i
+		}
+
+		if visit != nil {
+			visit(level, x, y)
+		}
+		level++
+
+		t /= 4
+	}
+
+	x, y = applyLayout(s.startState, s.mirrored, s.N, x, y)
+
+	return
+}
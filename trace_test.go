@@ -0,0 +1,75 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMapTrace(t *testing.T) {
+	s, err := NewHilbert(16, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	var got [][3]int
+	x, y, err := s.MapTrace(96, func(level, x, y int) {
+		got = append(got, [3]int{level, x, y})
+	})
+	if err != nil {
+		t.Fatalf("MapTrace(96) returned error: %s", err)
+	}
+	if x != 4 || y != 12 {
+		t.Errorf("MapTrace(96) = (%d, %d) want (4, 12)", x, y)
+	}
+
+	// 96 is 1200 in base 4, so the first two levels are at the start of their sub-squares.
+	want := [][3]int{{0, 0, 0}, {1, 0, 0}, {2, 4, 4}, {3, 4, 12}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("MapTrace(96) visited %v want %v", got, want)
+	}
+}
+
+func TestMapTraceMatchesMap(t *testing.T) {
+	h, _ := NewHilbert(16, false)
+	v, _ := NewHilbert(16, true)
+	for _, s := range []*Hilbert{h, v, v.Reversed()} {
+		for d := -1; d <= s.N*s.N; d++ {
+			wantX, wantY, wantErr := s.Map(d)
+			levels := 0
+			x, y, err := s.MapTrace(d, func(level, x, y int) {
+				if level != levels {
+					t.Errorf("MapTrace(%d) visited level %d want %d", d, level, levels)
+				}
+				if width := 2 << uint(level); x < 0 || x >= width || y < 0 || y >= width {
+					t.Errorf("MapTrace(%d) visited (%d, %d) outside of the %d by %d sub-square", d, x, y, width, width)
+				}
+				levels++
+			})
+			if x != wantX || y != wantY || err != wantErr {
+				t.Errorf("MapTrace(%d) = (%d, %d, %v) want (%d, %d, %v)", d, x, y, err, wantX, wantY, wantErr)
+			}
+			if err == nil && levels != s.GetOrder() {
+				t.Errorf("MapTrace(%d) visited %d levels want %d", d, levels, s.GetOrder())
+			}
+		}
+	}
+
+	s, _ := NewHilbert(4, false)
+	if x, y, err := s.MapTrace(5, nil); x != 0 || y != 3 || err != nil {
+		t.Errorf("MapTrace(5, nil) = (%d, %d, %v) want (0, 3, nil)", x, y, err)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import "image"
+
+// maxTurnsOrder is the largest order of curve DecodeTurns accepts, so the number of cells fits
+// within an int on all platforms.
+const maxTurnsOrder = 15
+
+// headingSteps are the moves (dx,dy) for each heading, in clockwise order starting with
+// HeadingRight, so turning right adds one to the index.
+var headingSteps = [4][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+
+// Polyline returns the coordinates of every cell in order along the curve, which are the vertices
+// of the line drawn through the centre of each cell. This uses memory proportional to N*N.
+func (s *Hilbert) Polyline() [][2]int {
+	points := make([][2]int, s.N*s.N)
+	for t := range points {
+		points[t][0], points[t][1], _ = s.Map(t)
+	}
+	return points
+}
+
+// Segment returns the coordinates of the cells from t0 to t1 inclusive, in order along the curve,
+// as a polyline through the centre of each cell. If simplify is true, the cells in the middle of
+// straight runs are left out, so only the ends of the segment and the cells where it turns are
+// returned, which draw the same line with far fewer points. A *RangeError is returned if the
+// range is not within the curve.
+func (s *Hilbert) Segment(t0, t1 int, simplify bool) ([]image.Point, error) {
+	if err := s.validRange(t0, t1, "t0", "t1"); err != nil {
+		return nil, err
+	}
+
+	var points []image.Point
+	if !simplify {
+		points = make([]image.Point, 0, t1-t0+1)
+	}
+	var dx, dy int
+	for t := t0; t <= t1; t++ {
+		x, y := s.mapValid(t)
+		p := image.Pt(x, y)
+		if simplify && len(points) >= 2 {
+			// Each step is to an adjacent cell, so the line is straight on if the step is the same
+			// as the last, and the previous point can be moved along instead.
+			last := points[len(points)-1]
+			if p.X-last.X == dx && p.Y-last.Y == dy {
+				points[len(points)-1] = p
+				continue
+			}
+		}
+		if len(points) > 0 {
+			last := points[len(points)-1]
+			dx, dy = p.X-last.X, p.Y-last.Y
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+// XYs returns the coordinates of the centre of every cell in order along the curve, as parallel
+// slices of x and y, in grid space where the centre of cell (x,y) is at exactly (x,y) as with
+// Snap. This is the form expected by plotting libraries such as gonum/plot.
+func (s *Hilbert) XYs() (xs, ys []float64) {
+	xs, ys = make([]float64, s.N*s.N), make([]float64, s.N*s.N)
+	for t := range xs {
+		x, y, _ := s.Map(t)
+		xs[t], ys[t] = float64(x), float64(y)
+	}
+	return xs, ys
+}
+
+// EncodeTurns returns the most compact encoding of the curve's path, as 2 bits per cell, packed
+// most significant first. The first 2 bits are the corner the curve starts in, as xbit<<1 | ybit,
+// and the next 2 bits the initial heading, in clockwise order from 0 for right, as with images.
+// Each remaining pair of bits is the turn taken at each cell between the first and last, being
+// the number of clockwise quarter turns, so 0 is straight on, 1 is right and 3 is left. The path
+// can be rebuilt with DecodeTurns.
+func (s *Hilbert) EncodeTurns() []byte {
+	codes := turnCodes(s.N)
+	data := make([]byte, (codes+3)/4)
+	put := func(i int, code int) {
+		data[i/4] |= byte(code) << uint(6-2*(i%4))
+	}
+
+	x, y, _ := s.Map(0)
+	put(0, b2i(x > 0)<<1|b2i(y > 0))
+	if s.N == 1 {
+		return data
+	}
+
+	prev := 0
+	for t := 1; t < s.N*s.N; t++ {
+		x1, y1, _ := s.Map(t)
+		h := int(headingBetween(x, y, x1, y1) - HeadingRight)
+		if t == 1 {
+			put(1, h)
+		} else {
+			put(t, (h-prev+4)%4)
+		}
+		x, y, prev = x1, y1, h
+	}
+	return data
+}
+
+// turnCodes returns the number of 2 bit codes EncodeTurns writes for a curve of width n.
+func turnCodes(n int) int {
+	if n == 1 {
+		return 2
+	}
+	return n * n
+}
+
+// DecodeTurns returns the coordinates of every cell visited by the path encoded in data by
+// EncodeTurns, for a curve of the given order, in the same form as Polyline. ErrInvalidLength is
+// returned if data is not the right length for the order, and ErrOutOfRange if the path leaves
+// the space. Orders above 15 return ErrTooLarge.
+func DecodeTurns(data []byte, order int) ([][2]int, error) {
+	if order < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if order > maxTurnsOrder {
+		return nil, ErrTooLarge
+	}
+
+	n := 1 << uint(order)
+	if len(data) != (turnCodes(n)+3)/4 {
+		return nil, ErrInvalidLength
+	}
+	get := func(i int) int {
+		return int(data[i/4]>>uint(6-2*(i%4))) & 3
+	}
+
+	corner := get(0)
+	points := make([][2]int, n*n)
+	points[0] = [2]int{(n - 1) * (corner >> 1), (n - 1) * (corner & 1)}
+
+	h := 0
+	for t := 1; t < n*n; t++ {
+		if t == 1 {
+			h = get(1)
+		} else {
+			h = (h + get(t)) % 4
+		}
+		x, y := points[t-1][0]+headingSteps[h][0], points[t-1][1]+headingSteps[h][1]
+		if x < 0 || x >= n || y < 0 || y >= n {
+			return nil, ErrOutOfRange
+		}
+		points[t] = [2]int{x, y}
+	}
+	return points, nil
+}
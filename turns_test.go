@@ -0,0 +1,164 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"errors"
+	"image"
+	"reflect"
+	"testing"
+)
+
+func TestPolyline(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	if got, want := s.Polyline(), [][2]int{{0, 0}, {0, 1}, {1, 1}, {1, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Polyline() = %v want %v", got, want)
+	}
+}
+
+func TestSegment(t *testing.T) {
+	s, err := NewHilbert(4, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	testCases := []struct {
+		t0, t1   int
+		simplify bool
+		want     []image.Point
+	}{
+		{0, 0, false, []image.Point{{0, 0}}},
+		{0, 0, true, []image.Point{{0, 0}}},
+		{0, 3, false, []image.Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+		{3, 6, false, []image.Point{{0, 1}, {0, 2}, {0, 3}, {1, 3}}},
+		{3, 6, true, []image.Point{{0, 1}, {0, 3}, {1, 3}}},
+		{4, 5, true, []image.Point{{0, 2}, {0, 3}}},
+		{0, 15, true, []image.Point{
+			{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 3}, {1, 3}, {1, 2}, {2, 2},
+			{2, 3}, {3, 3}, {3, 1}, {2, 1}, {2, 0}, {3, 0},
+		}},
+	}
+	for _, tc := range testCases {
+		got, err := s.Segment(tc.t0, tc.t1, tc.simplify)
+		if err != nil || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Segment(%d, %d, %t) = (%v, %v) want (%v, nil)", tc.t0, tc.t1, tc.simplify, got, err, tc.want)
+		}
+	}
+
+	for _, r := range [][2]int{{-1, 3}, {0, 16}, {5, 4}} {
+		if _, err := s.Segment(r[0], r[1], false); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Segment(%d, %d) = %v want %v", r[0], r[1], err, ErrOutOfRange)
+		}
+	}
+}
+
+func TestSegmentMatchesPolyline(t *testing.T) {
+	s, _ := NewHilbert(16, true)
+	polyline := s.Polyline()
+	got, _ := s.Segment(17, 200, false)
+	for i, p := range got {
+		if want := polyline[17+i]; p.X != want[0] || p.Y != want[1] {
+			t.Errorf("Segment(17, 200)[%d] = %v want %v", i, p, want)
+		}
+	}
+}
+
+func TestXYs(t *testing.T) {
+	s, err := NewHilbert(2, false)
+	if err != nil {
+		t.Fatalf("Failed to create hibert space: %s", err)
+	}
+
+	xs, ys := s.XYs()
+	if want := []float64{0, 0, 1, 1}; !reflect.DeepEqual(xs, want) {
+		t.Errorf("XYs() xs = %v want %v", xs, want)
+	}
+	if want := []float64{0, 1, 1, 0}; !reflect.DeepEqual(ys, want) {
+		t.Errorf("XYs() ys = %v want %v", ys, want)
+	}
+}
+
+func TestEncodeTurns(t *testing.T) {
+	s1, _ := NewHilbert(1, false)
+	s2, _ := NewHilbert(2, false)
+
+	testCases := []struct {
+		name string
+		s    *Hilbert
+		want []byte
+	}{
+		{"Hilbert(1)", s1, []byte{0x00}},
+		// Starts in corner 0 heading down (1), then turns left (3) twice.
+		{"Hilbert(2)", s2, []byte{0x1f}},
+		// Starts in corner 2, (1,0), heading down (1), then turns right (1) twice.
+		{"Hilbert(2).Reversed()", s2.Reversed(), []byte{0x95}},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.s.EncodeTurns(); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("EncodeTurns(%s) = %#v want %#v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTurnsRoundTrip(t *testing.T) {
+	for order := 0; order <= 6; order++ {
+		n := 1 << uint(order)
+		curves := []*Hilbert{}
+		for _, vc := range []bool{false, true} {
+			s, err := NewHilbert(n, vc)
+			if err != nil {
+				t.Fatalf("Failed to create hibert space: %s", err)
+			}
+			curves = append(curves, s, s.Reversed())
+		}
+
+		for _, s := range curves {
+			got, err := DecodeTurns(s.EncodeTurns(), order)
+			if err != nil {
+				t.Errorf("DecodeTurns(EncodeTurns(%s)) failed: %s", s.Fingerprint(), err)
+				continue
+			}
+			if want := s.Polyline(); !reflect.DeepEqual(got, want) {
+				t.Errorf("DecodeTurns(EncodeTurns(%s)) = %v want %v", s.Fingerprint(), got, want)
+			}
+		}
+	}
+}
+
+func TestDecodeTurnsErrors(t *testing.T) {
+	testCases := []struct {
+		data  []byte
+		order int
+		want  error
+	}{
+		{[]byte{0}, -1, ErrNegativeOrder},
+		{[]byte{0}, 16, ErrTooLarge},
+		{[]byte{0, 0}, 1, ErrInvalidLength},
+		{[]byte{0, 0}, 2, ErrInvalidLength},
+		{[]byte{0x30, 0, 0, 0}, 2, ErrOutOfRange}, // Heading up from (0,0)
+		{[]byte{0x00}, 1, ErrOutOfRange},          // Straight on along the top edge
+	}
+
+	for _, tc := range testCases {
+		if _, err := DecodeTurns(tc.data, tc.order); err != tc.want {
+			t.Errorf("DecodeTurns(%v, %d) = %v want %v", tc.data, tc.order, err, tc.want)
+		}
+	}
+}
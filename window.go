@@ -0,0 +1,154 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"sort"
+	"sync"
+)
+
+// Window represents a rectangular window into a larger Hilbert curve. Coordinates are local to
+// the window, with (0,0) being the window's top left corner, but values on the curve are those
+// of the larger curve, so the ordering is preserved across windows.
+//
+// As the values of the cells in a window are not contiguous, Map returns ErrOutOfRange for values
+// outside of the window.
+type Window struct {
+	curve  *Hilbert
+	ox, oy int // Top left corner of the window in the larger curve
+	w, h   int // Width and height of the window
+
+	rangesOnce sync.Once
+	ranges     []Range // Built on first use by NextValid
+}
+
+// NewHilbertWindow returns a new Window of width w and height h, with its top left corner at
+// (ox,oy), into the horizontal Hilbert curve of the given order, which is 2^order wide. The
+// window must lie inside the larger curve. ErrTooLarge is returned if N*N would not fit in an int.
+func NewHilbertWindow(order int, ox, oy, w, h int, opts ...Option) (*Window, error) {
+	if order < 0 {
+		return nil, ErrNegativeOrder
+	}
+	if order > MaxOrder {
+		return nil, ErrTooLarge
+	}
+	if w <= 0 || h <= 0 {
+		return nil, ErrNotPositive
+	}
+
+	curve, err := NewHilbert(1<<uint(order), false, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if ox < 0 || oy < 0 || ox > curve.N-w || oy > curve.N-h {
+		return nil, ErrOutOfRange
+	}
+
+	return &Window{
+		curve: curve,
+		ox:    ox,
+		oy:    oy,
+		w:     w,
+		h:     h,
+	}, nil
+}
+
+// NewHilbertRect returns a new Window of width w and height h, into the smallest horizontal
+// Hilbert curve which contains it, with the window in the top left corner. This allows curves
+// over rectangles whose sides are not powers of two. The cells of the larger curve outside of the
+// rectangle are padding, see Padding, which can be skipped with NextValid.
+func NewHilbertRect(w, h int, opts ...Option) (*Window, error) {
+	if w <= 0 || h <= 0 {
+		return nil, ErrNotPositive
+	}
+	return NewHilbertWindow(BitsFor(max(w, h)-1), 0, 0, w, h, opts...)
+}
+
+// Curve returns the larger curve the window is into.
+func (s *Window) Curve() *Hilbert {
+	return s.curve
+}
+
+// GetDimensions returns the width and height of the window.
+func (s *Window) GetDimensions() (int, int) {
+	return s.w, s.h
+}
+
+// GetBits returns the number of bits needed to hold each of the window's local coordinates, the
+// counterpart of GetOrder for rectangular spaces. Keys for the window can pack x into xbits bits
+// and y into ybits bits.
+func (s *Window) GetBits() (xbits, ybits int) {
+	return BitsFor(s.w - 1), BitsFor(s.h - 1)
+}
+
+// Map transforms a value, t, on the larger curve to coordinates local to the window. If the cell
+// at t is outside of the window, ErrOutOfRange is returned.
+func (s *Window) Map(t int) (x, y int, err error) {
+	x, y, err = s.curve.Map(t)
+	if err != nil {
+		return -1, -1, err
+	}
+	x, y = x-s.ox, y-s.oy
+	if x < 0 || x >= s.w || y < 0 || y >= s.h {
+		return -1, -1, ErrOutOfRange
+	}
+	return x, y, nil
+}
+
+// MapInverse transforms coordinates local to the window, (x,y), to the value t on the larger
+// curve.
+func (s *Window) MapInverse(x, y int) (t int, err error) {
+	if x < 0 || x >= s.w || y < 0 || y >= s.h {
+		return -1, ErrOutOfRange
+	}
+	return s.curve.MapInverse(x+s.ox, y+s.oy)
+}
+
+// Ranges returns the sorted list of ranges on the larger curve that exactly cover the window.
+func (s *Window) Ranges() []Range {
+	ranges, _ := s.curve.RangeQuery(s.ox, s.oy, s.ox+s.w-1, s.oy+s.h-1)
+	return ranges
+}
+
+// Padding returns the number of cells of the larger curve which are outside of the window. For a
+// window from NewHilbertRect this is N*N - w*h, where N is the smallest power of two which is at
+// least both w and h.
+func (s *Window) Padding() int {
+	return s.curve.N*s.curve.N - s.w*s.h
+}
+
+// NextValid returns the first cell in the window whose value on the larger curve is at least t,
+// in window coordinates, and nextT, the value to pass to NextValid to continue after it. ok is
+// false once there are no more cells in the window. Iterating from t = 0 visits every cell of the
+// window in curve order, skipping the padding without calling Map on it:
+//
+//	for x, y, t, ok := w.NextValid(0); ok; x, y, t, ok = w.NextValid(t) {
+//		...
+//	}
+func (s *Window) NextValid(t int) (x, y, nextT int, ok bool) {
+	s.rangesOnce.Do(func() {
+		s.ranges = s.Ranges()
+	})
+
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return s.ranges[i].Hi >= t
+	})
+	if i == len(s.ranges) {
+		return -1, -1, -1, false
+	}
+	t = max(t, s.ranges[i].Lo)
+	x, y, _ = s.Map(t)
+	return x, y, t + 1, true
+}
@@ -0,0 +1,174 @@
+// Copyright 2015 Google Inc. All Rights Reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hilbert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	s, err := NewHilbertWindow(5, 3, 10, 7, 4)
+	if err != nil {
+		t.Fatalf("NewHilbertWindow() failed: %s", err)
+	}
+	h := s.Curve()
+
+	if w, h := s.GetDimensions(); w != 7 || h != 4 {
+		t.Errorf("GetDimensions() = (%d, %d) want (7, 4)", w, h)
+	}
+
+	count := 0
+	for d := 0; d < h.N*h.N; d++ {
+		gx, gy, _ := h.Map(d)
+		inside := gx >= 3 && gx < 10 && gy >= 10 && gy < 14
+
+		x, y, err := s.Map(d)
+		if !inside {
+			if err != ErrOutOfRange {
+				t.Errorf("Map(%d) = %v want %v", d, err, ErrOutOfRange)
+			}
+			continue
+		}
+		count++
+		if err != nil || x != gx-3 || y != gy-10 {
+			t.Errorf("Map(%d) = (%d, %d, %v) want (%d, %d, nil)", d, x, y, err, gx-3, gy-10)
+		}
+		if got, err := s.MapInverse(x, y); err != nil || got != d {
+			t.Errorf("MapInverse(%d, %d) = (%d, %v) want (%d, nil)", x, y, got, err, d)
+		}
+	}
+	if count != 28 {
+		t.Errorf("Map found %d cells in the window want 28", count)
+	}
+
+	n := 0
+	for _, r := range s.Ranges() {
+		n += r.Len()
+	}
+	if n != 28 {
+		t.Errorf("Ranges() covers %d cells want 28", n)
+	}
+
+	if _, err := s.MapInverse(7, 0); err != ErrOutOfRange {
+		t.Errorf("MapInverse(7, 0) = %v want %v", err, ErrOutOfRange)
+	}
+}
+
+func TestNewHilbertWindowErrors(t *testing.T) {
+	testCases := []struct {
+		order, ox, oy, w, h int
+		want                error
+	}{
+		{-1, 0, 0, 1, 1, ErrNegativeOrder},
+		{32, 0, 0, 1, 1, ErrTooLarge},
+		{4, 0, 0, 0, 1, ErrNotPositive},
+		{4, 0, 0, 1, -1, ErrNotPositive},
+		{4, -1, 0, 1, 1, ErrOutOfRange},
+		{4, 0, 12, 4, 5, ErrOutOfRange},
+		{4, 13, 0, 4, 4, ErrOutOfRange},
+		{4, 12, 12, 4, 4, nil},
+		{0, 0, 0, 1, 1, nil},
+		{15, 1000, 1000, 1000, 1000, nil},
+	}
+
+	for _, tc := range testCases {
+		if _, err := NewHilbertWindow(tc.order, tc.ox, tc.oy, tc.w, tc.h); err != tc.want {
+			t.Errorf("NewHilbertWindow(%d, %d, %d, %d, %d) = %v want %v", tc.order, tc.ox, tc.oy, tc.w, tc.h, err, tc.want)
+		}
+	}
+}
+
+func TestNewHilbertRect(t *testing.T) {
+	testCases := []struct {
+		w, h         int
+		n            int
+		padding      int
+		xbits, ybits int
+	}{
+		{1, 1, 1, 0, 0, 0},
+		{3, 5, 8, 49, 2, 3},
+		{16, 16, 16, 0, 4, 4},
+		{17, 2, 32, 990, 5, 1},
+		{100, 60, 128, 10384, 7, 6},
+	}
+
+	for _, tc := range testCases {
+		s, err := NewHilbertRect(tc.w, tc.h)
+		if err != nil {
+			t.Errorf("NewHilbertRect(%d, %d) failed: %s", tc.w, tc.h, err)
+			continue
+		}
+		if s.Curve().N != tc.n {
+			t.Errorf("NewHilbertRect(%d, %d).Curve().N = %d want %d", tc.w, tc.h, s.Curve().N, tc.n)
+		}
+		if got := s.Padding(); got != tc.padding {
+			t.Errorf("NewHilbertRect(%d, %d).Padding() = %d want %d", tc.w, tc.h, got, tc.padding)
+		}
+		if xbits, ybits := s.GetBits(); xbits != tc.xbits || ybits != tc.ybits {
+			t.Errorf("NewHilbertRect(%d, %d).GetBits() = (%d, %d) want (%d, %d)", tc.w, tc.h, xbits, ybits, tc.xbits, tc.ybits)
+		}
+
+		// NextValid visits the same cells as iterating Map over the larger curve, skipping errors.
+		var want, got [][3]int
+		for d := 0; d < tc.n*tc.n; d++ {
+			if x, y, err := s.Map(d); err == nil {
+				want = append(want, [3]int{x, y, d})
+			}
+		}
+		for x, y, next, ok := s.NextValid(0); ok; x, y, next, ok = s.NextValid(next) {
+			got = append(got, [3]int{x, y, next - 1})
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("NewHilbertRect(%d, %d).NextValid() visited %v want %v", tc.w, tc.h, got, want)
+		}
+		if len(got) != tc.w*tc.h {
+			t.Errorf("NewHilbertRect(%d, %d).NextValid() visited %d cells want %d", tc.w, tc.h, len(got), tc.w*tc.h)
+		}
+	}
+
+	for _, size := range [][2]int{{0, 1}, {1, 0}, {-1, 5}} {
+		if _, err := NewHilbertRect(size[0], size[1]); err != ErrNotPositive {
+			t.Errorf("NewHilbertRect(%d, %d) = %v want %v", size[0], size[1], err, ErrNotPositive)
+		}
+	}
+}
+
+func TestNextValid(t *testing.T) {
+	s, err := NewHilbertRect(3, 3)
+	if err != nil {
+		t.Fatalf("NewHilbertRect(3, 3) failed: %s", err)
+	}
+
+	testCases := []struct {
+		t          int
+		x, y, next int
+		ok         bool
+	}{
+		{0, 0, 0, 1, true},
+		{4, 0, 2, 5, true},
+		{5, 1, 2, 8, true},      // Skips (0,3) and (1,3)
+		{9, 2, 1, 14, true},     // Skips the cells with x or y of 3
+		{15, -1, -1, -1, false}, // (3,0) is padding, and the last cell
+		{100, -1, -1, -1, false},
+	}
+
+	for _, tc := range testCases {
+		x, y, next, ok := s.NextValid(tc.t)
+		if x != tc.x || y != tc.y || next != tc.next || ok != tc.ok {
+			t.Errorf("NextValid(%d) = (%d, %d, %d, %t) want (%d, %d, %d, %t)", tc.t, x, y, next, ok, tc.x, tc.y, tc.next, tc.ok)
+		}
+	}
+}